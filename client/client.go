@@ -0,0 +1,251 @@
+// Package client connects to a qwixx server over its websocket, for programs that play or watch games from elsewhere.
+//
+// Dial connects to the server, the request helpers such as CreateLobby, JoinLobby and SubmitTurn send requests,
+// and Events delivers every message the server sends, decoded into its typed payload,
+// until the connection is lost for good or the client is closed.
+package client
+
+import (
+	"errors"
+	"fmt"
+	"qwixx/internal/protocol"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// eventBuffer is how many events the server can send ahead of the reader of Events before the client stops reading
+const eventBuffer = 64
+
+// ErrClosed is returned by the requests sent once the client is closed
+var ErrClosed = errors.New("client is closed")
+
+// Client is a connection to a qwixx server
+type Client struct {
+	url               string
+	dialer            *websocket.Dialer
+	reconnectAttempts int
+	reconnectBackoff  time.Duration
+
+	// mu guards the connection, which is replaced when reconnecting, and serializes writes to it
+	mu     sync.Mutex
+	conn   *websocket.Conn
+	closed bool
+	err    error
+
+	events chan Event
+	done   chan struct{}
+}
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithDialer dials the server with the given dialer rather than websocket.DefaultDialer
+func WithDialer(dialer *websocket.Dialer) Option {
+	return func(c *Client) {
+		c.dialer = dialer
+	}
+}
+
+// WithReconnect redials the server up to the given number of times when the connection is lost,
+// waiting the given backoff before the first attempt and twice as long as before the previous attempt before each next one.
+// Clients give up on the first lost connection by default.
+//
+// The server has no way to resume a connection: the new connection is a new client to it, in no lobby and no game.
+// A client that was in a game loses its seat, whose turns the server passes once they time out,
+// so callers must handle MessageReconnected by starting over, such as by creating or joining another lobby.
+func WithReconnect(attempts int, backoff time.Duration) Option {
+	return func(c *Client) {
+		c.reconnectAttempts = attempts
+		c.reconnectBackoff = backoff
+	}
+}
+
+// Dial connects to the server at the given websocket url, such as ws://localhost:8080/ws
+func Dial(url string, options ...Option) (*Client, error) {
+	c := &Client{
+		url:    url,
+		dialer: websocket.DefaultDialer,
+		events: make(chan Event, eventBuffer),
+		done:   make(chan struct{}),
+	}
+	for _, option := range options {
+		option(c)
+	}
+	conn, _, err := c.dialer.Dial(url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("connecting to %v: %w", url, err)
+	}
+	c.conn = conn
+	go c.readEvents(conn)
+	return c, nil
+}
+
+// Events delivers the messages of the server in the order they were sent.
+// It is closed once the connection is lost and cannot be made again, or the client is closed, see Err.
+func (c *Client) Events() <-chan Event {
+	return c.events
+}
+
+// Err returns why Events was closed, nil if it is still open or if the client was closed with Close
+func (c *Client) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.err
+}
+
+// Close closes the connection to the server, after which Events is closed and requests fail with ErrClosed
+func (c *Client) Close() error {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return nil
+	}
+	c.closed = true
+	close(c.done)
+	conn := c.conn
+	c.mu.Unlock()
+	return conn.Close()
+}
+
+func (c *Client) isClosed() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.closed
+}
+
+// readEvents emits the messages read off the given connection and those it is replaced with when reconnecting,
+// until the connection cannot be made again or the client is closed
+func (c *Client) readEvents(conn *websocket.Conn) {
+	defer close(c.events)
+	for {
+		lost := c.readMessages(conn)
+		if c.isClosed() {
+			return
+		}
+		var err error
+		if conn, err = c.reconnect(lost); err != nil {
+			c.mu.Lock()
+			c.err = err
+			c.mu.Unlock()
+			return
+		}
+		if !c.emit(Event{Type: MessageReconnected, Received: time.Now()}) {
+			return
+		}
+	}
+}
+
+// readMessages emits the messages read off the given connection until reading fails, returning why
+func (c *Client) readMessages(conn *websocket.Conn) error {
+	for {
+		var message Message
+		if err := conn.ReadJSON(&message); err != nil {
+			return err
+		}
+		event, err := decodeEvent(message, time.Now())
+		if err != nil {
+			// a message the client cannot make sense of leaves it out of step with the server
+			_ = conn.Close()
+			return err
+		}
+		if !c.emit(event) {
+			return ErrClosed
+		}
+	}
+}
+
+// emit delivers the given event, reporting false if the client was closed first
+func (c *Client) emit(event Event) bool {
+	select {
+	case c.events <- event:
+		return true
+	case <-c.done:
+		return false
+	}
+}
+
+// reconnect redials the server after the connection was lost for the given reason, returning the new connection
+func (c *Client) reconnect(lost error) (*websocket.Conn, error) {
+	backoff := c.reconnectBackoff
+	for attempt := 0; attempt < c.reconnectAttempts; attempt++ {
+		select {
+		case <-time.After(backoff):
+		case <-c.done:
+			return nil, ErrClosed
+		}
+		backoff *= 2
+		conn, _, err := c.dialer.Dial(c.url, nil)
+		if err != nil {
+			continue
+		}
+		c.mu.Lock()
+		if c.closed {
+			c.mu.Unlock()
+			_ = conn.Close()
+			return nil, ErrClosed
+		}
+		c.conn = conn
+		c.mu.Unlock()
+		return conn, nil
+	}
+	return nil, fmt.Errorf("lost connection to the server: %w", lost)
+}
+
+// Send sends the server a message of the given type with the given payload, a nil payload leaving it empty
+func (c *Client) Send(messageType MessageType, payload any) error {
+	message, err := protocol.NewMessage(messageType, payload)
+	if err != nil {
+		return err
+	}
+	return c.SendMessage(message)
+}
+
+// SendMessage sends the server the given message
+func (c *Client) SendMessage(message Message) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return ErrClosed
+	}
+	return c.conn.WriteJSON(message)
+}
+
+// CreateLobby creates a lobby hosted by the client under the given name, for a game on the sheet of the given variant,
+// the classic sheet if empty, played by the given ruleset, the classic rules if empty, and house rules.
+// The server answers with the LobbyState of the new lobby.
+func (c *Client) CreateLobby(name, variant, ruleset string, config GameConfig) error {
+	return c.Send(protocol.MessageCreateLobby, CreateLobby{Name: name, Variant: variant, Ruleset: ruleset, Config: config})
+}
+
+// JoinLobby joins the lobby with the given code under the given name. Everyone in the lobby is sent its new LobbyState.
+func (c *Client) JoinLobby(code, name string) error {
+	return c.Send(protocol.MessageJoinLobby, JoinLobby{Code: code, Name: name})
+}
+
+// AddBot adds a computer player with the given name to the lobby the client hosts, which only its host can do
+func (c *Client) AddBot(name string) error {
+	return c.Send(protocol.MessageAddBot, AddBot{Name: name})
+}
+
+// StartGame starts the game of the lobby the client hosts, which only its host can do
+func (c *Client) StartGame() error {
+	return c.Send(protocol.MessageStartGame, nil)
+}
+
+// SubmitTurn answers the prompt with the given ID. Only the white dice move may be set when answering an inactive prompt,
+// and leaving out both moves passes, or takes a penalty as the active player.
+func (c *Client) SubmitTurn(promptID int, whiteDiceMove, colorDiceMove *Move) error {
+	return c.Send(protocol.MessageSubmitTurn, SubmitTurn{PromptID: promptID, WhiteDiceMove: whiteDiceMove, ColorDiceMove: colorDiceMove})
+}
+
+// RequestHint asks for the recommended answer to the prompt with the given ID, which the server sends as a Hint
+func (c *Client) RequestHint(promptID int) error {
+	return c.Send(protocol.MessageRequestHint, RequestHint{PromptID: promptID})
+}
+
+// Chat says the given text to everyone in the client's lobby or game
+func (c *Client) Chat(text string) error {
+	return c.Send(protocol.MessageChat, Chat{Text: text})
+}
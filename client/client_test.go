@@ -0,0 +1,143 @@
+package client
+
+import (
+	"qwixx/internal/server/servertest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func dial(t *testing.T, url string, options ...Option) *Client {
+	t.Helper()
+	c, err := Dial(url, options...)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = c.Close() })
+	return c
+}
+
+// awaitEvent returns the next event of the given type, skipping events of other types
+func awaitEvent(t *testing.T, c *Client, messageType MessageType) Event {
+	t.Helper()
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case event, ok := <-c.Events():
+			require.True(t, ok, "events closed waiting for %v: %v", messageType, c.Err())
+			if event.Type == messageType {
+				return event
+			}
+		case <-timeout:
+			t.Fatalf("no %v event", messageType)
+		}
+	}
+}
+
+// await returns the payload of the next event of the given type, skipping events of other types
+func await[T any](t *testing.T, c *Client, messageType MessageType) T {
+	t.Helper()
+	event := awaitEvent(t, c, messageType)
+	payload, ok := event.Payload.(T)
+	require.True(t, ok, "%v payload is a %T", messageType, event.Payload)
+	return payload
+}
+
+func TestClient_Lobby(t *testing.T) {
+	url := servertest.New(t).URL
+	alice := dial(t, url)
+	bob := dial(t, url)
+
+	require.NoError(t, alice.CreateLobby("alice", "", "double", GameConfig{LocksToEnd: 3}))
+	lobby := await[LobbyState](t, alice, MessageLobbyState)
+	require.Equal(t, "alice", lobby.Host)
+	require.Equal(t, "double", lobby.Ruleset)
+	require.Equal(t, 3, lobby.Config.LocksToEnd)
+
+	require.NoError(t, bob.JoinLobby("nonsense", "bob"))
+	require.Contains(t, await[Error](t, bob, MessageError).Message, "no lobby")
+
+	require.NoError(t, bob.JoinLobby(lobby.Code, "bob"))
+	require.Equal(t, []string{"alice", "bob"}, await[LobbyState](t, bob, MessageLobbyState).Players)
+
+	require.NoError(t, bob.Chat("hi alice"))
+	require.Equal(t, Chat{From: "bob", Text: "hi alice"}, await[Chat](t, alice, MessageChat))
+}
+
+func TestClient_PlayGame(t *testing.T) {
+	url := servertest.New(t).URL
+	alice := dial(t, url)
+
+	require.NoError(t, alice.CreateLobby("alice", "", "", GameConfig{Hints: true}))
+	await[LobbyState](t, alice, MessageLobbyState)
+	require.NoError(t, alice.AddBot("bot"))
+	await[LobbyState](t, alice, MessageLobbyState)
+	require.NoError(t, alice.StartGame())
+	started := await[GameStarted](t, alice, MessageGameStarted)
+	require.Len(t, started.Players, 2)
+
+	// ask for a hint on the first prompt and play it, then pass on every other prompt until the game is over
+	hinted := false
+	timeout := time.After(10 * time.Second)
+	for {
+		select {
+		case event, ok := <-alice.Events():
+			require.True(t, ok, "events closed: %v", alice.Err())
+			require.False(t, event.Received.IsZero())
+			switch payload := event.Payload.(type) {
+			case Prompt:
+				if !hinted {
+					require.NoError(t, alice.RequestHint(payload.PromptID))
+					hint := await[Hint](t, alice, MessageHint)
+					require.Equal(t, payload.PromptID, hint.PromptID)
+					require.NoError(t, alice.SubmitTurn(payload.PromptID, hint.WhiteDiceMove, hint.ColorDiceMove))
+					hinted = true
+					continue
+				}
+				require.NoError(t, alice.SubmitTurn(payload.PromptID, nil, nil))
+			case GameOver:
+				require.NotEmpty(t, payload.WinnerID)
+				return
+			}
+		case <-timeout:
+			t.Fatal("the game did not end")
+		}
+	}
+}
+
+func TestClient_Reconnect(t *testing.T) {
+	testServer := servertest.New(t)
+	alice := dial(t, testServer.URL, WithReconnect(3, 10*time.Millisecond))
+
+	testServer.DropConnections()
+	require.Nil(t, awaitEvent(t, alice, MessageReconnected).Payload)
+
+	// the new connection is a new client to the server, free to create a lobby of its own
+	require.NoError(t, alice.CreateLobby("alice", "", "", GameConfig{}))
+	require.Equal(t, []string{"alice"}, await[LobbyState](t, alice, MessageLobbyState).Players)
+}
+
+func TestClient_LostConnection(t *testing.T) {
+	testServer := servertest.New(t)
+	alice := dial(t, testServer.URL)
+
+	testServer.DropConnections()
+	select {
+	case _, ok := <-alice.Events():
+		require.False(t, ok)
+	case <-time.After(5 * time.Second):
+		t.Fatal("events were not closed")
+	}
+	require.ErrorContains(t, alice.Err(), "lost connection to the server")
+}
+
+func TestClient_Close(t *testing.T) {
+	url := servertest.New(t).URL
+	alice := dial(t, url)
+
+	require.NoError(t, alice.Close())
+	for range alice.Events() {
+	}
+	require.NoError(t, alice.Err())
+	require.ErrorIs(t, alice.CreateLobby("alice", "", "", GameConfig{}), ErrClosed)
+	require.NoError(t, alice.Close())
+}
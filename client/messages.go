@@ -0,0 +1,119 @@
+package client
+
+import (
+	"qwixx/internal/game"
+	"qwixx/internal/game/actions"
+	"qwixx/internal/game/board"
+	"qwixx/internal/game/player"
+	"qwixx/internal/protocol"
+	"time"
+)
+
+// The messages of the server's websocket protocol, see the protocol package for what each of them means
+type (
+	Message     = protocol.Message
+	MessageType = protocol.MessageType
+
+	CreateLobby = protocol.CreateLobby
+	JoinLobby   = protocol.JoinLobby
+	AddBot      = protocol.AddBot
+	SubmitTurn  = protocol.SubmitTurn
+	RequestHint = protocol.RequestHint
+
+	LobbyState  = protocol.LobbyState
+	Error       = protocol.Error
+	PlayerInfo  = protocol.PlayerInfo
+	GameStarted = protocol.GameStarted
+	PlayOrder   = protocol.PlayOrder
+	Prompt      = protocol.Prompt
+	InvalidTurn = protocol.InvalidTurn
+	Hint        = protocol.Hint
+	BoardUpdate = protocol.BoardUpdate
+	RowLocked   = protocol.RowLocked
+	GameOver    = protocol.GameOver
+	Log         = protocol.Log
+	Chat        = protocol.Chat
+)
+
+// The types the messages are made of
+type (
+	GameConfig = game.GameConfig
+	Move       = actions.Move
+	DiceRoll   = actions.DiceRoll
+	BoardState = board.State
+	PlayerID   = player.PlayerID
+)
+
+// Messages sent from the server to a client
+const (
+	MessageLobbyState     = protocol.MessageLobbyState
+	MessageError          = protocol.MessageError
+	MessageGameStarted    = protocol.MessageGameStarted
+	MessagePlayOrder      = protocol.MessagePlayOrder
+	MessagePromptActive   = protocol.MessagePromptActive
+	MessagePromptInactive = protocol.MessagePromptInactive
+	MessageInvalidTurn    = protocol.MessageInvalidTurn
+	MessageBoardUpdate    = protocol.MessageBoardUpdate
+	MessageRowLocked      = protocol.MessageRowLocked
+	MessageGameOver       = protocol.MessageGameOver
+	MessageLog            = protocol.MessageLog
+	MessageHint           = protocol.MessageHint
+	MessageChat           = protocol.MessageChat
+)
+
+// MessageReconnected is never sent by the server. It is the type of the event a client emits once it has reconnected
+// after losing its connection, without a payload. The server does not keep seats across connections,
+// so a client that was in a lobby or a game is in neither after reconnecting.
+const MessageReconnected MessageType = "reconnected"
+
+// Event is a message the server sent, with its payload decoded into the type of the message,
+// such as a LobbyState for MessageLobbyState or a Prompt for MessagePromptActive and MessagePromptInactive.
+// The payload is nil for messages without one and for message types the client does not know.
+type Event struct {
+	Type    MessageType
+	Payload any
+	// Message is the message as it was received
+	Message Message
+	// Received is when the message was read off the connection
+	Received time.Time
+}
+
+// decoders decode the payloads of the message types the client knows
+var decoders = map[MessageType]func(message Message) (any, error){
+	MessageLobbyState:     decodeAs[LobbyState],
+	MessageError:          decodeAs[Error],
+	MessageGameStarted:    decodeAs[GameStarted],
+	MessagePlayOrder:      decodeAs[PlayOrder],
+	MessagePromptActive:   decodeAs[Prompt],
+	MessagePromptInactive: decodeAs[Prompt],
+	MessageInvalidTurn:    decodeAs[InvalidTurn],
+	MessageBoardUpdate:    decodeAs[BoardUpdate],
+	MessageRowLocked:      decodeAs[RowLocked],
+	MessageGameOver:       decodeAs[GameOver],
+	MessageLog:            decodeAs[Log],
+	MessageHint:           decodeAs[Hint],
+	MessageChat:           decodeAs[Chat],
+}
+
+func decodeAs[T any](message Message) (any, error) {
+	var payload T
+	if err := message.Decode(&payload); err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
+
+// decodeEvent decodes the payload of the given message into an event
+func decodeEvent(message Message, received time.Time) (Event, error) {
+	event := Event{Type: message.Type, Message: message, Received: received}
+	decode, ok := decoders[message.Type]
+	if !ok || len(message.Payload) == 0 {
+		return event, nil
+	}
+	payload, err := decode(message)
+	if err != nil {
+		return Event{}, err
+	}
+	event.Payload = payload
+	return event, nil
+}
@@ -0,0 +1,77 @@
+// qwixx-reference-bot is a minimal bot speaking the external bot protocol over stdin and stdout.
+// It crosses off the first legal cell it finds for each move, and serves as an example for bots written in other languages.
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"qwixx/internal/game/actions"
+	"qwixx/internal/game/board"
+	"qwixx/internal/game/player"
+	"qwixx/internal/game/rule_checker"
+)
+
+type referenceBot struct{}
+
+func (r referenceBot) GetName() string {
+	return "reference-bot"
+}
+
+func (r referenceBot) InformOfPlayOrder(playerNames []string) {
+	// stdout is reserved for replies, so diagnostics go to stderr
+	fmt.Fprintf(os.Stderr, "play order: %v\n", playerNames)
+}
+
+func (r referenceBot) PromptActivePlayerTurn(playerBoard board.Board, diceRoll actions.DiceRoll) actions.ActivePlayerTurn {
+	turn := actions.ActivePlayerTurn{
+		WhiteDiceMove: firstLegalMove(playerBoard, rule_checker.PossibleWhiteDiceMoves(playerBoard, diceRoll)),
+	}
+	if turn.WhiteDiceMove != nil {
+		// the color dice move is checked against the board after the white dice move is made
+		_ = playerBoard.MakeMove(*turn.WhiteDiceMove)
+	}
+	turn.ColorDiceMove = firstLegalMove(playerBoard, rule_checker.PossibleColorDiceMoves(playerBoard, diceRoll))
+	return turn
+}
+
+func (r referenceBot) PromptInactivePlayerTurn(playerBoard board.Board, diceRoll actions.DiceRoll) actions.InactivePlayerTurn {
+	return actions.InactivePlayerTurn{
+		WhiteDiceMove: firstLegalMove(playerBoard, rule_checker.PossibleWhiteDiceMoves(playerBoard, diceRoll)),
+	}
+}
+
+func firstLegalMove(playerBoard board.Board, possibleMoves []actions.Move) *actions.Move {
+	for _, move := range possibleMoves {
+		if ok, _ := playerBoard.IsMoveValid(move); ok {
+			return &move
+		}
+	}
+	return nil
+}
+
+func (r referenceBot) InformSuccessfulTurn(updatedBoard board.Board) {}
+
+func (r referenceBot) InformInvalidTurn(err error) {
+	fmt.Fprintf(os.Stderr, "turn rejected: %v\n", err)
+}
+
+func (r referenceBot) InformOfOpponentMove(playerID player.PlayerID, move actions.Move) {}
+
+func (r referenceBot) InformRowLocked(color actions.RowColor) {
+	fmt.Fprintf(os.Stderr, "row %v was locked\n", color)
+}
+
+func (r referenceBot) InformWin() {
+	fmt.Fprintln(os.Stderr, "won")
+}
+
+func (r referenceBot) InformLoss(winnerID player.PlayerID) {
+	fmt.Fprintf(os.Stderr, "lost to %v\n", winnerID)
+}
+
+func main() {
+	if err := player.ServeExternalBot(os.Stdin, os.Stdout, referenceBot{}); err != nil {
+		log.Fatal(err)
+	}
+}
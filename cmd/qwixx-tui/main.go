@@ -0,0 +1,86 @@
+// qwixx-tui is a full-screen terminal client for the qwixx server
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"qwixx/client"
+	"qwixx/internal/tui"
+)
+
+func main() {
+	serverURL := flag.String("server", "ws://localhost:8080/ws", "websocket url of the server")
+	name := flag.String("name", "", "your name in the game")
+	join := flag.String("join", "", "code of the lobby to join, a new lobby is created if empty")
+	variant := flag.String("variant", "", "sheet of the new lobby's game, the classic sheet if empty, ignored when joining")
+	rules := flag.String("ruleset", "", "rules for crossing off cells in the new lobby's game, the classic rules if empty, ignored when joining")
+	var config client.GameConfig
+	flag.IntVar(&config.LocksToEnd, "locks-to-end", 0, "number of locked rows that ends the new lobby's game, 2 if 0")
+	flag.IntVar(&config.PenaltiesToEnd, "penalties-to-end", 0, "number of penalties of one player that ends the new lobby's game, 4 if 0")
+	flag.IntVar(&config.PenaltyValue, "penalty-value", 0, "points each penalty costs in the new lobby's game, 5 if 0")
+	flag.BoolVar(&config.AnyDiceOrder, "any-dice-order", false, "let the active player use the color dice before the white dice")
+	flag.IntVar(&config.MaxTurns, "max-turns", 0, "number of turns after which the new lobby's unfinished game is stopped, 1000 if 0")
+	flag.Parse()
+	if *name == "" {
+		log.Fatal("a -name is needed to play")
+	}
+
+	c, err := client.Dial(*serverURL)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer c.Close()
+
+	if *join == "" {
+		err = c.CreateLobby(*name, *variant, *rules, config)
+	} else {
+		err = c.JoinLobby(*join, *name)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	restore, err := tui.EnterFullScreen(os.Stdin)
+	if err != nil {
+		log.Fatal(err)
+	}
+	err = run(c, tui.NewModel(*name))
+	restore()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// run redraws the screen after every key press and server message until the player quits
+func run(c *client.Client, model *tui.Model) error {
+	keys := make(chan tui.Key)
+	go tui.ReadKeys(os.Stdin, keys)
+
+	for !model.Quit {
+		width, height := tui.Size(os.Stdin)
+		fmt.Print(model.View(width, height))
+
+		select {
+		case event, ok := <-c.Events():
+			if !ok {
+				return c.Err()
+			}
+			if err := model.HandleMessage(event.Message); err != nil {
+				return err
+			}
+		case key, ok := <-keys:
+			if !ok {
+				return nil
+			}
+			for _, outgoing := range model.HandleKey(key) {
+				if err := c.SendMessage(outgoing); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
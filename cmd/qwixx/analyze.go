@@ -0,0 +1,56 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"qwixx/internal/game"
+	"qwixx/internal/review"
+)
+
+// analyze reviews the decisions of the game whose result JSON is in the given file, or on stdin for "-",
+// as written by play -result, listing the biggest blunders of its players
+func analyze(args []string) error {
+	flags := newFlagSet("analyze")
+	top := flags.Int("top", 10, "number of biggest blunders to list, every mistake if negative")
+	output := flags.String("output", "text", "output format: text or json")
+	if err := parseArgs(flags, args, 1); err != nil {
+		return err
+	}
+	if *output != "text" && *output != "json" {
+		return usageErrorf(flags, "unknown output format %q, expected text or json", *output)
+	}
+
+	result, err := readResult(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	report, err := review.Game(result)
+	if err != nil {
+		return err
+	}
+	report = report.Top(*top)
+	if *output == "json" {
+		return report.WriteJSON(os.Stdout)
+	}
+	return report.WriteText(os.Stdout)
+}
+
+// readResult reads the result of a game written by play -result from the file at the given path, or from stdin for "-"
+func readResult(path string) (game.GameResult, error) {
+	var in io.Reader = os.Stdin
+	if path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			return game.GameResult{}, err
+		}
+		defer file.Close()
+		in = file
+	}
+	var result game.GameResult
+	if err := json.NewDecoder(in).Decode(&result); err != nil {
+		return game.GameResult{}, fmt.Errorf("reading game result: %w", err)
+	}
+	return result, nil
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"qwixx/internal/game"
+	"qwixx/internal/game/board"
+	"qwixx/internal/render"
+	"strings"
+)
+
+// drawBoard draws the board written in the board notation, such as "R:2,3,5 Y:- G:12,10 B:12,11,9,7,6,2L P:1",
+// reporting why it is invalid if it is
+func drawBoard(args []string) error {
+	flags := newFlagSet("board")
+	penaltyValue := flags.Int("penalty-value", game.DefaultGameConfig().PenaltyValue, "points each penalty costs")
+	if err := parseArgs(flags, args, -1); err != nil {
+		return err
+	}
+	if flags.NArg() == 0 {
+		return usageErrorf(flags, "expected a board in the board notation")
+	}
+
+	b, penalties, err := board.Parse(strings.Join(flags.Args(), " "))
+	if err != nil {
+		return fmt.Errorf("invalid board: %w", err)
+	}
+	renderer := render.ForWriter(os.Stdout)
+	renderer.PenaltyValue = *penaltyValue
+	fmt.Println(renderer.Board(b, penalties))
+	return nil
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"os"
+	"qwixx/internal/botclient"
+	"qwixx/internal/game/player"
+	"qwixx/internal/logging"
+	"strings"
+	"time"
+)
+
+// bot joins a lobby on a remote server with a computer player, and plays the lobby's game once its host starts it
+func bot(args []string) error {
+	flags := newFlagSet("bot")
+	serverURL := flags.String("server", "ws://localhost:8080/ws", "websocket url of the server")
+	join := flags.String("join", "", "code of the lobby to join")
+	name := flags.String("name", "bot", "name of the bot in the game")
+	strategyName := flags.String("strategy", player.StrategyHard, fmt.Sprintf("strategy of the bot, one of %v", strings.Join(player.StrategyNames(), ", ")))
+	logLevel := flags.String("log-level", "info", "lowest level logged: debug, info, warn or error, the bot's own reasoning being logged at debug")
+	if err := parseArgs(flags, args, 0); err != nil {
+		return err
+	}
+	if *join == "" {
+		return usageErrorf(flags, "a -join code is needed to find the lobby")
+	}
+	strategy, err := player.NewStrategy(*strategyName, rand.New(rand.NewSource(time.Now().UnixNano())))
+	if err != nil {
+		return usageErrorf(flags, "%v", err)
+	}
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(*logLevel)); err != nil {
+		return usageErrorf(flags, "unknown log level %q, expected debug, info, warn or error", *logLevel)
+	}
+	logger := logging.NewHuman(os.Stderr, level)
+
+	client, err := botclient.Dial(*serverURL, player.NewStrategyPlayer(*name, strategy, logger), botclient.WithLogger(logger))
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	if err := client.Join(*join); err != nil {
+		return err
+	}
+	gameOver, err := client.Play()
+	if err != nil {
+		return err
+	}
+	if gameOver.Won {
+		fmt.Printf("%v won\n", *name)
+	} else {
+		fmt.Printf("%v lost to %v\n", *name, gameOver.WinnerID)
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"qwixx/internal/game/player"
+	"qwixx/internal/loadtest"
+	"strings"
+	"time"
+)
+
+// loadTest plays many games at once against a running server with simulated clients, and reports how long the server took to answer them
+func loadTest(args []string) error {
+	flags := newFlagSet("loadtest")
+	serverURL := flags.String("server", "ws://localhost:8080/ws", "websocket url of the server")
+	games := flags.Int("games", 250, "number of games played at the same time")
+	players := flags.Int("players", 4, "number of simulated clients playing each game")
+	strategy := flags.String("strategy", player.StrategyFirst, fmt.Sprintf("strategy the clients choose their turns with, one of %v", strings.Join(player.StrategyNames(), ", ")))
+	ramp := flags.Duration("ramp", 10*time.Second, "how long the starts of the games are spread over")
+	timeout := flags.Duration("timeout", 5*time.Minute, "how long a game may take before its clients give up")
+	statsInterval := flags.Duration("stats-interval", time.Second, "how often the stats of the server are sampled")
+	seed := flags.Int64("seed", 0, "seed of the strategies that need randomness, a random seed is used if 0")
+	output := flags.String("output", "text", "output format: text or json")
+	if err := parseArgs(flags, args, 0); err != nil {
+		return err
+	}
+	if *output != "text" && *output != "json" {
+		return usageErrorf(flags, "unknown output format %q, expected text or json", *output)
+	}
+	if *seed == 0 {
+		*seed = time.Now().UnixNano()
+	}
+	if *ramp < 0 {
+		return usageErrorf(flags, "the ramp cannot be negative")
+	}
+
+	report, err := loadtest.Run(loadtest.Config{
+		ServerURL:      *serverURL,
+		Games:          *games,
+		PlayersPerGame: *players,
+		Strategy:       *strategy,
+		Ramp:           *ramp,
+		Timeout:        *timeout,
+		StatsInterval:  *statsInterval,
+		Seed:           *seed,
+	})
+	if err != nil {
+		return usageErrorf(flags, "%v", err)
+	}
+	if *output == "json" {
+		return report.WriteJSON(os.Stdout)
+	}
+	return report.WriteText(os.Stdout)
+}
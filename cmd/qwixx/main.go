@@ -1,17 +1,126 @@
+// qwixx serves, plays, simulates and analyzes games of Qwixx, one subcommand at a time
 package main
 
 import (
-	"log"
-	"qwixx/internal/server"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
 )
 
+// command is a subcommand of qwixx
+type command struct {
+	name string
+	// args describes the arguments the subcommand takes after its flags, if any
+	args    string
+	summary string
+	run     func(args []string) error
+}
+
+// commands lists the subcommands in the order the usage shows them.
+// It is filled in by init as the subcommands look themselves up in it to print their usage.
+var commands []command
+
+func init() {
+	commands = []command{
+		{name: "serve", summary: "run the game server that clients and bots connect to", run: serve},
+		{name: "play", summary: "play a local game in this terminal against bots or taking turns at the keyboard", run: play},
+		{name: "simulate", summary: "play many silent games between computer players and report how their strategies did", run: simulate},
+		{name: "replay", args: "result.json", summary: "replay a finished game decision by decision and check it adds up", run: replay},
+		{name: "analyze", args: "result.json", summary: "list the biggest blunders of the players of a finished game", run: analyze},
+		{name: "tournament", summary: "schedule matches between computer players and rank their strategies", run: tournament},
+		{name: "bot", summary: "join a lobby on a remote server with a computer player", run: bot},
+		{name: "loadtest", summary: "play many games at once against a running server and report its latency, errors and resource usage", run: loadTest},
+		{name: "board", args: "notation", summary: "draw a board written in the board notation and check it is valid", run: drawBoard},
+	}
+}
+
+// errUsage is returned by a subcommand called the wrong way, once the mistake and the usage have been printed
+var errUsage = errors.New("usage")
+
 func main() {
-	settings := server.Settings{
-		Endpoint: "localhost:8080",
+	os.Exit(runCommand(os.Args[1:], os.Stderr))
+}
+
+// runCommand runs the subcommand named by the first argument, returning the exit code:
+// 0 on success or when help was asked for, 1 if the subcommand failed and 2 if it was called the wrong way
+func runCommand(args []string, stderr io.Writer) int {
+	if len(args) == 0 {
+		printUsage(stderr)
+		return 2
+	}
+	if name := args[0]; name == "help" || name == "-h" || name == "-help" || name == "--help" {
+		printUsage(stderr)
+		return 0
+	}
+	for _, cmd := range commands {
+		if cmd.name != args[0] {
+			continue
+		}
+		err := cmd.run(args[1:])
+		switch {
+		case err == nil, errors.Is(err, flag.ErrHelp):
+			return 0
+		case errors.Is(err, errUsage):
+			return 2
+		default:
+			fmt.Fprintf(stderr, "qwixx %v: %v\n", cmd.name, err)
+			return 1
+		}
 	}
-	s := server.New()
-	err := s.Start(settings)
-	if err != nil {
-		log.Fatal(err)
+	fmt.Fprintf(stderr, "qwixx: unknown command %q\n\n", args[0])
+	printUsage(stderr)
+	return 2
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, "usage: qwixx <command> [flags] [arguments]")
+	fmt.Fprintln(w, "\ncommands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-11s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintln(w, "\nrun qwixx <command> -h for the flags of a command")
+}
+
+// newFlagSet creates the flag set of the named subcommand, whose usage lists its arguments, summary and flags
+func newFlagSet(name string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.Usage = func() {
+		for _, cmd := range commands {
+			if cmd.name != name {
+				continue
+			}
+			usage := []string{"usage: qwixx", cmd.name, "[flags]"}
+			if cmd.args != "" {
+				usage = append(usage, cmd.args)
+			}
+			fmt.Fprintf(flags.Output(), "%s\n\n%s\n\nflags:\n", strings.Join(usage, " "), cmd.summary)
+		}
+		flags.PrintDefaults()
 	}
+	return flags
+}
+
+// parseArgs parses the flags of a subcommand, which takes the given number of arguments after them, any number if negative
+func parseArgs(flags *flag.FlagSet, args []string, arguments int) error {
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		// the flag set has already reported the mistake and printed the usage
+		return errUsage
+	}
+	if arguments >= 0 && flags.NArg() != arguments {
+		return usageErrorf(flags, "wrong number of arguments, expected %d but got %d", arguments, flags.NArg())
+	}
+	return nil
+}
+
+// usageErrorf reports a mistake in the way a subcommand was called along with its usage, returning errUsage
+func usageErrorf(flags *flag.FlagSet, format string, args ...any) error {
+	fmt.Fprintf(flags.Output(), "qwixx %v: %v\n", flags.Name(), fmt.Sprintf(format, args...))
+	flags.Usage()
+	return errUsage
 }
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"qwixx/internal/game"
+	"qwixx/internal/game/board"
+	"qwixx/internal/game/player"
+	"qwixx/internal/game/ruleset"
+	"qwixx/internal/logging"
+	"qwixx/internal/review"
+	"qwixx/internal/scoresheet"
+	"strings"
+	"time"
+)
+
+// play runs a local game in this terminal between the named humans, taking turns at the keyboard, and some computer players
+func play(args []string) error {
+	flags := newFlagSet("play")
+	humans := flags.String("humans", "you", "comma separated names of the humans playing at this terminal")
+	bots := flags.Int("bots", 1, "number of computer players to add")
+	strategyName := flags.String("strategy", player.StrategyFirst, fmt.Sprintf("strategy of the computer players, one of %v", strings.Join(player.StrategyNames(), ", ")))
+	variantName := flags.String("variant", string(board.VariantClassic), fmt.Sprintf("sheet to play on, one of %v", board.Variants()))
+	rulesetName := flags.String("ruleset", ruleset.NameClassic, fmt.Sprintf("rules for crossing off cells, one of %v", ruleset.Names()))
+	config := houseRuleFlags(flags)
+	scoresheetPath := flags.String("scoresheet", "", "file to draw the final boards to, as PNG if it ends in .png and as SVG otherwise")
+	resultPath := flags.String("result", "", "file to write the result of the game to as JSON, to replay or analyze later")
+	reviewTop := flags.Int("review", 0, "number of biggest blunders to list in a review of the game once it is over, none if 0")
+	if err := parseArgs(flags, args, 0); err != nil {
+		return err
+	}
+	variant, err := board.ParseVariant(*variantName)
+	if err != nil {
+		return usageErrorf(flags, "%v", err)
+	}
+	// a config leaves out a penalty value of zero, which would silently make penalties cost the default
+	if config.PenaltyValue == 0 {
+		return usageErrorf(flags, "penalty value must be at least 1")
+	}
+	if err := config.Validate(variant); err != nil {
+		return usageErrorf(flags, "%v", err)
+	}
+	rules, err := ruleset.New(*rulesetName)
+	if err != nil {
+		return usageErrorf(flags, "%v", err)
+	}
+	penaltyValue := player.WithPenaltyValue(config.WithDefaults().PenaltyValue)
+	if _, err := player.NewStrategy(*strategyName, nil, penaltyValue); err != nil {
+		return usageErrorf(flags, "%v", err)
+	}
+
+	var humanNames []string
+	for _, name := range strings.Split(*humans, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			humanNames = append(humanNames, name)
+		}
+	}
+
+	var terminalOptions []player.TerminalOption
+	if config.Hints {
+		terminalOptions = append(terminalOptions, player.WithHints(config.WithDefaults().PenaltyValue))
+	}
+	if config.AnyDiceOrder {
+		terminalOptions = append(terminalOptions, player.WithAnyDiceOrder())
+	}
+	var players []player.Player
+	if len(humanNames) == 1 {
+		players = append(players, player.NewTerminalPlayer(humanNames[0], os.Stdin, os.Stdout, terminalOptions...))
+	} else {
+		players = append(players, player.NewHotSeatPlayers(humanNames, os.Stdin, os.Stdout, terminalOptions...)...)
+	}
+	for i := 1; i <= *bots; i++ {
+		strategy, _ := player.NewStrategy(*strategyName, rand.New(rand.NewSource(time.Now().UnixNano()+int64(i))), penaltyValue)
+		players = append(players, player.NewStrategyPlayer(fmt.Sprintf("bot%v", i), strategy, nil))
+	}
+	if len(players) < 2 {
+		return usageErrorf(flags, "a game needs at least two players")
+	}
+
+	options := []game.Option{
+		game.WithLogger(logging.NewHuman(os.Stdout, slog.LevelDebug)), game.WithVariant(variant),
+		game.WithRuleset(rules),
+		game.WithConfig(*config),
+	}
+	// the decisions are only needed to replay or review the game
+	if *resultPath != "" || *reviewTop > 0 {
+		options = append(options, game.WithDecisions())
+	}
+	result := game.NewGameRunner(players, options...).RunGame()
+	// the final boards in the board notation, to paste into bug reports or back into qwixx board
+	for _, playerResult := range result.Players {
+		finalBoard, err := board.FromState(playerResult.Board)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%v: %v\n", playerResult.Name, board.Format(finalBoard, playerResult.Penalties))
+	}
+	if *scoresheetPath != "" {
+		if err := writeScoresheet(*scoresheetPath, result); err != nil {
+			return err
+		}
+		fmt.Printf("scoresheet written to %v\n", *scoresheetPath)
+	}
+	if *resultPath != "" {
+		if err := writeResult(*resultPath, result); err != nil {
+			return err
+		}
+		fmt.Printf("result written to %v\n", *resultPath)
+	}
+	if *reviewTop > 0 {
+		report, err := review.Game(result)
+		if err != nil {
+			return err
+		}
+		return report.Top(*reviewTop).WriteText(os.Stdout)
+	}
+	return nil
+}
+
+// houseRuleFlags defines the flags choosing the house rules of a game on the given flag set,
+// each defaulting to the rule of the original game
+func houseRuleFlags(flags *flag.FlagSet) *game.GameConfig {
+	defaults := game.DefaultGameConfig()
+	config := &game.GameConfig{}
+	flags.IntVar(&config.LocksToEnd, "locks-to-end", defaults.LocksToEnd, "number of locked rows that ends the game")
+	flags.IntVar(&config.PenaltiesToEnd, "penalties-to-end", defaults.PenaltiesToEnd, "number of penalties of one player that ends the game")
+	flags.IntVar(&config.PenaltyValue, "penalty-value", defaults.PenaltyValue, "points each penalty costs, at least 1")
+	flags.BoolVar(&config.AnyDiceOrder, "any-dice-order", false, "let the active player use the color dice before the white dice")
+	flags.IntVar(&config.MaxTurns, "max-turns", defaults.MaxTurns, "number of turns after which an unfinished game is stopped")
+	flags.BoolVar(&config.Hints, "hints", false, "let humans type hint for the recommended turn")
+	return config
+}
+
+// writeResult writes the result of a game to the file at the given path as JSON
+func writeResult(path string, result game.GameResult) error {
+	encoded, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(encoded, '\n'), 0o644)
+}
+
+// writeScoresheet draws the final boards of the given game to the file at the given path, as PNG or SVG depending on its extension
+func writeScoresheet(path string, result game.GameResult) error {
+	sheets, err := scoresheet.SheetsOf(result)
+	if err != nil {
+		return err
+	}
+	out, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+	if strings.EqualFold(filepath.Ext(path), ".png") {
+		err = scoresheet.PNG(out, sheets...)
+	} else {
+		err = scoresheet.SVG(out, sheets...)
+	}
+	if err != nil {
+		return err
+	}
+	return out.Close()
+}
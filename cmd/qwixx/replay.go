@@ -0,0 +1,62 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"qwixx/internal/game"
+	"qwixx/internal/game/board"
+	"qwixx/internal/game/player"
+	"qwixx/internal/review"
+	"strings"
+	"text/tabwriter"
+)
+
+// replay plays the decisions of the game whose result JSON is in the given file, or on stdin for "-", again,
+// printing the board of each player after each decision and failing if the result does not add up
+func replay(args []string) error {
+	flags := newFlagSet("replay")
+	quiet := flags.Bool("quiet", false, "only check the result, without printing the decisions")
+	if err := parseArgs(flags, args, 1); err != nil {
+		return err
+	}
+
+	result, err := readResult(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	if !*quiet {
+		fmt.Fprintln(tw, "turn\tplayer\trole\tdice\tchosen\tboard\tlocked\t")
+	}
+	names := make(map[player.PlayerID]string, len(result.Players))
+	for _, playerResult := range result.Players {
+		names[playerResult.ID] = playerResult.Name
+	}
+	err = game.Replay(result, func(step game.ReplayStep) {
+		if *quiet {
+			return
+		}
+		decision := step.Decision
+		role := "inactive"
+		if decision.Active {
+			role = "active"
+		}
+		var locked []string
+		for _, rowColor := range step.Locked {
+			locked = append(locked, rowColor.String())
+		}
+		fmt.Fprintf(
+			tw, "%d\t%s\t%s\t%s\t%s\t%s\t%s\t\n",
+			decision.Turn, names[decision.PlayerID], role, review.FormatDice(decision.DiceRoll, decision.Active),
+			review.FormatTurn(decision.Chosen, decision.Active), board.Format(step.Board, step.Penalties), strings.Join(locked, ", "),
+		)
+	})
+	if flushErr := tw.Flush(); flushErr != nil {
+		return flushErr
+	}
+	if err != nil {
+		return fmt.Errorf("the result does not add up:\n%w", err)
+	}
+	fmt.Printf("replayed %d decisions over %d turns, the result adds up\n", len(result.Decisions), result.Turns)
+	return nil
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"log/slog"
+	"os"
+	"qwixx/internal/logging"
+	"qwixx/internal/server"
+)
+
+// serve runs the game server until it fails
+func serve(args []string) error {
+	flags := newFlagSet("serve")
+	addr := flags.String("addr", "localhost:8080", "address to listen on")
+	turnTimeout := flags.Duration("turn-timeout", server.DefaultTurnTimeout, "how long a connected client has to submit a turn before it passes")
+	maxFinishedGames := flags.Int("max-finished-games", server.DefaultMaxFinishedGames, "how many finished games are kept for their scoresheets and reviews")
+	finishedGameTTL := flags.Duration("finished-game-ttl", server.DefaultFinishedGameTTL, "how long a finished game is kept for its scoresheets and reviews")
+	logLevel := flags.String("log-level", "info", "lowest level logged: debug, info, warn or error")
+	logFormat := flags.String("log-format", "human", "format of the log: human or json")
+	if err := parseArgs(flags, args, 0); err != nil {
+		return err
+	}
+
+	if *maxFinishedGames < 1 || *finishedGameTTL <= 0 {
+		return usageErrorf(flags, "finished games must be kept, at least one of them and for some time")
+	}
+
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(*logLevel)); err != nil {
+		return usageErrorf(flags, "unknown log level %q, expected debug, info, warn or error", *logLevel)
+	}
+	var logger *slog.Logger
+	switch *logFormat {
+	case "human":
+		logger = logging.NewHuman(os.Stderr, level)
+	case "json":
+		logger = slog.New(slog.NewJSONHandler(os.Stderr, &slog.HandlerOptions{Level: level}))
+	default:
+		return usageErrorf(flags, "unknown log format %q, expected human or json", *logFormat)
+	}
+
+	return server.New().Start(server.Settings{
+		Endpoint:         *addr,
+		Logger:           logger,
+		TurnTimeout:      *turnTimeout,
+		MaxFinishedGames: *maxFinishedGames,
+		FinishedGameTTL:  *finishedGameTTL,
+	})
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"qwixx/internal/game/player"
+	"qwixx/internal/simulation"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// simulate plays many silent games between computer players and reports how their strategies did
+func simulate(args []string) error {
+	flags := newFlagSet("simulate")
+	games := flags.Int("games", 1000, "number of games to play")
+	workers := flags.Int("workers", runtime.NumCPU(), "number of games to play at the same time")
+	strategies := flags.String(
+		"strategies", "first,greedy",
+		fmt.Sprintf("comma separated strategies of the players, one per seat, out of %v", strings.Join(player.StrategyNames(), ", ")),
+	)
+	seed := flags.Int64("seed", 0, "seed of the first game, a random seed is used if 0")
+	output := flags.String("output", "text", "output format: text, csv or json")
+	if err := parseArgs(flags, args, 0); err != nil {
+		return err
+	}
+	switch simulation.Format(*output) {
+	case simulation.FormatText, simulation.FormatCSV, simulation.FormatJSON:
+	default:
+		return usageErrorf(flags, "unknown output format %q, expected text, csv or json", *output)
+	}
+
+	config := simulation.Config{
+		Games:      *games,
+		Workers:    *workers,
+		Strategies: strings.Split(*strategies, ","),
+		Seed:       *seed,
+	}
+	if config.Seed == 0 {
+		config.Seed = time.Now().UnixNano()
+	}
+	report, err := simulation.Run(config)
+	if err != nil {
+		return err
+	}
+	return report.Write(os.Stdout, simulation.Format(*output))
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"os"
+	"qwixx/internal/game/player"
+	qwixxtournament "qwixx/internal/tournament"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// tournament schedules matches between computer players and ranks their strategies
+func tournament(args []string) error {
+	flags := newFlagSet("tournament")
+	entrants := flags.String(
+		"entrants", strings.Join(player.StrategyNames(), ","),
+		"comma separated entrants, each a strategy or name=strategy to enter a strategy more than once",
+	)
+	format := flags.String("format", string(qwixxtournament.FormatRoundRobin), "round-robin, swiss or permutations")
+	tableSize := flags.Int("table-size", 2, "players in each game, swiss tournaments are always played in pairs")
+	games := flags.Int("games", 10, "seeds each match is played with, every seed in every seat order")
+	rounds := flags.Int("rounds", 0, "rounds of a swiss tournament, enough to separate the entrants if 0")
+	seed := flags.Int64("seed", 0, "seed of the first game of each match, a random seed is used if 0")
+	workers := flags.Int("workers", runtime.NumCPU(), "number of games to play at the same time")
+	output := flags.String("output", "text", "output format: text or json")
+	if err := parseArgs(flags, args, 0); err != nil {
+		return err
+	}
+	if *output != "text" && *output != "json" {
+		return usageErrorf(flags, "unknown output format %q, expected text or json", *output)
+	}
+
+	parsedEntrants, err := qwixxtournament.ParseEntrants(*entrants)
+	if err != nil {
+		return usageErrorf(flags, "%v", err)
+	}
+	config := qwixxtournament.Config{
+		Entrants:      parsedEntrants,
+		Format:        qwixxtournament.Format(*format),
+		TableSize:     *tableSize,
+		GamesPerMatch: *games,
+		Rounds:        *rounds,
+		Seed:          *seed,
+		Workers:       *workers,
+	}
+	if config.Seed == 0 {
+		config.Seed = time.Now().UnixNano()
+	}
+	results, err := qwixxtournament.Run(config)
+	if err != nil {
+		return err
+	}
+	if *output == "json" {
+		return results.WriteJSON(os.Stdout)
+	}
+	return results.WriteText(os.Stdout)
+}
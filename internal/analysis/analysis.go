@@ -0,0 +1,282 @@
+// Package analysis works out how likely the cells of a board are to come up and what crossing them off is worth,
+// to help players learn which moves pay off.
+// Odds are exact, counting every way the dice can fall, while the score impact of a move is an estimate
+// weighing the points it adds right away against the chances it gives up by skipping cells.
+package analysis
+
+import (
+	"cmp"
+	"qwixx/internal/game/actions"
+	"qwixx/internal/game/board"
+	"qwixx/internal/game/rule_checker"
+	"slices"
+)
+
+// dieFaces are the values a die can show
+var dieFaces = []int{1, 2, 3, 4, 5, 6}
+
+// CellOdds is how likely a cell is to be crossed off on the next turn
+type CellOdds struct {
+	Move actions.Move
+	// Active is the probability that the roll lets the active player cross off the cell, with the white dice or a color die
+	Active float64
+	// Inactive is the probability that the white dice add up to the cell's number, letting the inactive players cross it off
+	Inactive float64
+}
+
+// RowOdds lists the odds of the cells of a row from left to right
+type RowOdds struct {
+	RowColor actions.RowColor
+	Cells    []CellOdds
+}
+
+// Odds works out how likely each cell of each row of the given board is to be crossed off on the next turn,
+// for the active player and for the inactive players.
+// Cells the board does not let the player cross off, because they are crossed off, skipped or in a locked row, have no chance.
+func Odds(b board.Board) []RowOdds {
+	sums := sumOdds(b)
+	rows := make([]RowOdds, 0, len(b.Variant().RowColors()))
+	for _, rowColor := range b.Variant().RowColors() {
+		row := RowOdds{RowColor: rowColor}
+		for _, cell := range b.Cells(rowColor) {
+			move := actions.NewMove(rowColor, cell.Number)
+			odds := CellOdds{Move: move}
+			if ok, _ := b.IsMoveValid(move); ok {
+				odds = sums[move]
+			}
+			row.Cells = append(row.Cells, odds)
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// sumOdds works out how likely the dice are to add up to the number of each cell of the board's sheet, whatever is crossed off.
+// A cell can only be crossed off with the white dice or with the die of its own color,
+// so rolling the two white dice and that die alone in every way covers every roll that matters to it.
+// The sums of each roll are the moves rule_checker finds for it.
+func sumOdds(b board.Board) map[actions.Move]CellOdds {
+	odds := map[actions.Move]CellOdds{}
+	rolls := float64(len(dieFaces) * len(dieFaces) * len(dieFaces))
+	for _, dieColor := range b.Variant().RowColors() {
+		for _, white1 := range dieFaces {
+			for _, white2 := range dieFaces {
+				for _, colorDie := range dieFaces {
+					diceRoll := actions.DiceRoll{WhiteDiceRoll: actions.WhiteDiceRoll{White1: white1, White2: white2}}
+					setColorDie(&diceRoll, dieColor, colorDie)
+					whiteMoves := rule_checker.PossibleWhiteDiceMoves(b, diceRoll)
+					colorMoves := rule_checker.PossibleColorDiceMoves(b, diceRoll)
+					for _, rowColor := range b.Variant().RowColors() {
+						for _, cell := range b.Cells(rowColor) {
+							if cell.Color != dieColor {
+								continue
+							}
+							move := actions.NewMove(rowColor, cell.Number)
+							cellOdds := odds[move]
+							cellOdds.Move = move
+							white := slices.Contains(whiteMoves, move)
+							if white {
+								cellOdds.Inactive += 1 / rolls
+							}
+							if white || slices.Contains(colorMoves, move) {
+								cellOdds.Active += 1 / rolls
+							}
+							odds[move] = cellOdds
+						}
+					}
+				}
+			}
+		}
+	}
+	return odds
+}
+
+// setColorDie sets the die of the given color in the roll to the given value
+func setColorDie(diceRoll *actions.DiceRoll, dieColor actions.RowColor, value int) {
+	switch dieColor {
+	case actions.RowColorRed:
+		diceRoll.Red = value
+	case actions.RowColorYellow:
+		diceRoll.Yellow = value
+	case actions.RowColorGreen:
+		diceRoll.Green = value
+	case actions.RowColorBlue:
+		diceRoll.Blue = value
+	case actions.RowColorOrange:
+		diceRoll.Orange = value
+	case actions.RowColorPurple:
+		diceRoll.Purple = value
+	}
+}
+
+// MoveAnalysis is what crossing off a cell gains and gives up
+type MoveAnalysis struct {
+	Move actions.Move `json:"move"`
+	// Skipped is the number of empty cells to the left of the move's cell, which can never be crossed off after it
+	Skipped int `json:"skipped"`
+	// ExpectedSkipped is the skipped cells weighed by how likely each was to come up for the active player on a turn,
+	// which is how many crosses the move is expected to cost a turn's worth of rolls
+	ExpectedSkipped float64 `json:"expected_skipped"`
+	// ScoreGain is the number of points the move adds to the board's score right away
+	ScoreGain int `json:"score_gain"`
+	// ScoreImpact estimates what the move is worth in the end: its score gain minus the points the expected skipped cells
+	// would have added to the row, each as much as the next cross of the row after the move
+	ScoreImpact float64 `json:"score_impact"`
+}
+
+// AnalyzeMove works out what crossing off the cell of the given move on the given board gains and gives up,
+// returning an error if the board does not allow the move
+func AnalyzeMove(b board.Board, move actions.Move) (MoveAnalysis, error) {
+	return analyzeMove(b, sumOdds(b), move)
+}
+
+// analyzeMove is AnalyzeMove with the odds of the board's sheet already worked out
+func analyzeMove(b board.Board, sums map[actions.Move]CellOdds, move actions.Move) (MoveAnalysis, error) {
+	if ok, err := b.IsMoveValid(move); !ok {
+		return MoveAnalysis{}, err
+	}
+	after := b.Copy()
+	if err := after.MakeMove(move); err != nil {
+		return MoveAnalysis{}, err
+	}
+	analysis := MoveAnalysis{Move: move, ScoreGain: after.CalculateScore() - b.CalculateScore()}
+
+	cells := b.Cells(move.RowColor)
+	moveIdx := slices.IndexFunc(cells, func(cell board.Cell) bool { return cell.Number == move.CellNumber })
+	lastCrossedIdx := -1
+	for idx, cell := range cells {
+		if b.CrossCount(move.RowColor, cell.Number) > 0 {
+			lastCrossedIdx = idx
+		}
+	}
+	for idx := lastCrossedIdx + 1; idx < moveIdx; idx++ {
+		analysis.Skipped++
+		analysis.ExpectedSkipped += sums[actions.NewMove(move.RowColor, cells[idx].Number)].Active
+	}
+
+	crosses := 0
+	for _, cell := range cells {
+		crosses += after.CrossCount(move.RowColor, cell.Number)
+	}
+	// the next cross of a row with n crosses adds n+1 points
+	analysis.ScoreImpact = float64(analysis.ScoreGain) - analysis.ExpectedSkipped*float64(crosses+1)
+	return analysis, nil
+}
+
+// TurnAnalysis is what a whole turn gains and gives up, adding up the analyses of its moves
+type TurnAnalysis struct {
+	Turn actions.ActivePlayerTurn `json:"turn"`
+	// Moves are the analyses of the moves of the turn in the order they are made,
+	// each on the board the moves before it leave, and none for a penalty or a pass
+	Moves           []MoveAnalysis `json:"moves,omitempty"`
+	Skipped         int            `json:"skipped"`
+	ExpectedSkipped float64        `json:"expected_skipped"`
+	ScoreGain       int            `json:"score_gain"`
+	ScoreImpact     float64        `json:"score_impact"`
+}
+
+// AnalyzeActiveTurns analyzes every turn the active player can legally take on the given board with the given roll,
+// best first by their estimated score impact, where taking a penalty costs the given penalty value
+func AnalyzeActiveTurns(b board.Board, diceRoll actions.DiceRoll, penaltyValue int) []TurnAnalysis {
+	sums := sumOdds(b)
+	var analyses []TurnAnalysis
+	for _, turn := range rule_checker.LegalActiveTurns(b, diceRoll) {
+		// legal turns are valid, so analyzing them cannot fail
+		analysis, _ := analyzeActiveTurn(b, sums, turn, false, penaltyValue)
+		analyses = append(analyses, analysis)
+	}
+	sortByImpact(analyses)
+	return analyses
+}
+
+// AnalyzeActiveTurn analyzes the given turn of the active player on the given board, with its color dice move made first
+// if colorFirst is set, where taking a penalty costs the given penalty value.
+// It returns an error if the board does not allow the moves of the turn, without checking they add up to any roll.
+func AnalyzeActiveTurn(b board.Board, turn actions.ActivePlayerTurn, colorFirst bool, penaltyValue int) (TurnAnalysis, error) {
+	return analyzeActiveTurn(b, sumOdds(b), turn, colorFirst, penaltyValue)
+}
+
+// analyzeActiveTurn is AnalyzeActiveTurn with the odds of the board's sheet already worked out
+func analyzeActiveTurn(
+	b board.Board, sums map[actions.Move]CellOdds, turn actions.ActivePlayerTurn, colorFirst bool, penaltyValue int,
+) (TurnAnalysis, error) {
+	ordered := []*actions.Move{turn.WhiteDiceMove, turn.ColorDiceMove}
+	if colorFirst {
+		ordered = []*actions.Move{turn.ColorDiceMove, turn.WhiteDiceMove}
+	}
+	var moves []actions.Move
+	for _, move := range ordered {
+		if move != nil {
+			moves = append(moves, *move)
+		}
+	}
+	analysis, err := analyzeTurn(b, sums, moves)
+	if err != nil {
+		return TurnAnalysis{}, err
+	}
+	analysis.Turn = turn
+	if len(moves) == 0 {
+		analysis.ScoreGain = -penaltyValue
+		analysis.ScoreImpact = float64(-penaltyValue)
+	}
+	return analysis, nil
+}
+
+// AnalyzeInactiveTurns analyzes every turn an inactive player can legally take on the given board with the given roll,
+// best first by their estimated score impact, with the white dice move of each turn as the white dice move of its analysis' turn
+func AnalyzeInactiveTurns(b board.Board, diceRoll actions.DiceRoll) []TurnAnalysis {
+	sums := sumOdds(b)
+	var analyses []TurnAnalysis
+	for _, turn := range rule_checker.LegalInactiveTurns(b, diceRoll) {
+		analysis, _ := analyzeInactiveTurn(b, sums, turn)
+		analyses = append(analyses, analysis)
+	}
+	sortByImpact(analyses)
+	return analyses
+}
+
+// AnalyzeInactiveTurn analyzes the given turn of an inactive player on the given board,
+// returning an error if the board does not allow its move
+func AnalyzeInactiveTurn(b board.Board, turn actions.InactivePlayerTurn) (TurnAnalysis, error) {
+	return analyzeInactiveTurn(b, sumOdds(b), turn)
+}
+
+// analyzeInactiveTurn is AnalyzeInactiveTurn with the odds of the board's sheet already worked out
+func analyzeInactiveTurn(b board.Board, sums map[actions.Move]CellOdds, turn actions.InactivePlayerTurn) (TurnAnalysis, error) {
+	var moves []actions.Move
+	if turn.WhiteDiceMove != nil {
+		moves = append(moves, *turn.WhiteDiceMove)
+	}
+	analysis, err := analyzeTurn(b, sums, moves)
+	if err != nil {
+		return TurnAnalysis{}, err
+	}
+	analysis.Turn = actions.ActivePlayerTurn{WhiteDiceMove: turn.WhiteDiceMove}
+	return analysis, nil
+}
+
+// analyzeTurn analyzes the given moves made one after the other on a copy of the given board, adding up their analyses
+func analyzeTurn(b board.Board, sums map[actions.Move]CellOdds, moves []actions.Move) (TurnAnalysis, error) {
+	var analysis TurnAnalysis
+	current := b.Copy()
+	for _, move := range moves {
+		moveAnalysis, err := analyzeMove(current, sums, move)
+		if err != nil {
+			return TurnAnalysis{}, err
+		}
+		_ = current.MakeMove(move)
+		analysis.Moves = append(analysis.Moves, moveAnalysis)
+		analysis.Skipped += moveAnalysis.Skipped
+		analysis.ExpectedSkipped += moveAnalysis.ExpectedSkipped
+		analysis.ScoreGain += moveAnalysis.ScoreGain
+		analysis.ScoreImpact += moveAnalysis.ScoreImpact
+	}
+	return analysis, nil
+}
+
+// sortByImpact orders the analyses best first, keeping the order they were found in for turns of the same impact
+func sortByImpact(analyses []TurnAnalysis) {
+	slices.SortStableFunc(analyses, func(a, b TurnAnalysis) int {
+		return cmp.Compare(b.ScoreImpact, a.ScoreImpact)
+	})
+}
@@ -0,0 +1,212 @@
+package analysis
+
+import (
+	"qwixx/internal/game/actions"
+	"qwixx/internal/game/board"
+	"qwixx/internal/game/rule_checker"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func parseBoard(t *testing.T, text string) board.Board {
+	t.Helper()
+	b, _, err := board.Parse(text)
+	require.NoError(t, err)
+	return b
+}
+
+// cellOdds finds the odds of the cell of the given move
+func cellOdds(t *testing.T, odds []RowOdds, move actions.Move) CellOdds {
+	t.Helper()
+	for _, row := range odds {
+		for _, cell := range row.Cells {
+			if cell.Move == move {
+				return cell
+			}
+		}
+	}
+	require.Fail(t, "no odds for the cell", "%v", move)
+	return CellOdds{}
+}
+
+func TestOdds(t *testing.T) {
+	type testCase struct {
+		name             string
+		inputBoard       string
+		inputMove        actions.Move
+		expectedActive   float64
+		expectedInactive float64
+	}
+	testCases := []testCase{
+		{
+			// both white dice showing one, or the red die and either white die showing one
+			name:             "rarest sum",
+			inputBoard:       "",
+			inputMove:        actions.NewMove(actions.RowColorRed, 2),
+			expectedActive:   16.0 / 216,
+			expectedInactive: 1.0 / 36,
+		},
+		{
+			// the white dice adding up to seven, or the green die and either white die doing so
+			name:             "most common sum",
+			inputBoard:       "",
+			inputMove:        actions.NewMove(actions.RowColorGreen, 7),
+			expectedActive:   90.0 / 216,
+			expectedInactive: 6.0 / 36,
+		},
+		{
+			name:       "crossed off cell",
+			inputBoard: "Y:4",
+			inputMove:  actions.NewMove(actions.RowColorYellow, 4),
+		},
+		{
+			name:       "skipped cell",
+			inputBoard: "B:9",
+			inputMove:  actions.NewMove(actions.RowColorBlue, 11),
+		},
+		{
+			name:       "locked row",
+			inputBoard: "R:L",
+			inputMove:  actions.NewMove(actions.RowColorRed, 7),
+		},
+		{
+			name:       "rightmost cell without five crosses",
+			inputBoard: "R:2,3,4,5",
+			inputMove:  actions.NewMove(actions.RowColorRed, 12),
+		},
+		{
+			name:             "rightmost cell after five crosses",
+			inputBoard:       "G:12,11,10,9,8",
+			inputMove:        actions.NewMove(actions.RowColorGreen, 2),
+			expectedActive:   16.0 / 216,
+			expectedInactive: 1.0 / 36,
+		},
+		{
+			name:             "cell of another color on a sheet with mixed colors",
+			inputBoard:       "sheet:mixx-colors",
+			inputMove:        actions.NewMove(actions.RowColorRed, 2),
+			expectedActive:   16.0 / 216,
+			expectedInactive: 1.0 / 36,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			odds := cellOdds(t, Odds(parseBoard(t, tc.inputBoard)), tc.inputMove)
+			require.InDelta(t, tc.expectedActive, odds.Active, 1e-9)
+			require.InDelta(t, tc.expectedInactive, odds.Inactive, 1e-9)
+		})
+	}
+}
+
+func TestOdds_WhiteSumsAddUp(t *testing.T) {
+	for _, variant := range board.Variants() {
+		t.Run(string(variant), func(t *testing.T) {
+			b, err := board.NewBoard(variant)
+			require.NoError(t, err)
+			odds := Odds(b)
+			require.Len(t, odds, len(variant.RowColors()))
+			for _, row := range odds {
+				if variant.IsBonusRow(row.RowColor) {
+					// the cells of the bonus rows need the cells around them crossed off first
+					continue
+				}
+				// the white dice add up to exactly one number, which every row has a cell for
+				total := 0.0
+				for _, cell := range row.Cells {
+					total += cell.Inactive
+					require.GreaterOrEqual(t, cell.Active, cell.Inactive)
+				}
+				// the rightmost cell needs five others crossed off first
+				lastCell := row.Cells[len(row.Cells)-1]
+				require.Zero(t, lastCell.Active)
+				total += float64(6-abs(lastCell.Move.CellNumber-7)) / 36
+				require.InDelta(t, 1, total, 1e-9)
+			}
+		})
+	}
+}
+
+func abs(value int) int {
+	if value < 0 {
+		return -value
+	}
+	return value
+}
+
+func TestAnalyzeMove(t *testing.T) {
+	b := parseBoard(t, "R:2 G:12,11,10,9,8")
+	odds := Odds(b)
+
+	analysis, err := AnalyzeMove(b, actions.NewMove(actions.RowColorRed, 5))
+	require.NoError(t, err)
+	expectedSkipped := cellOdds(t, odds, actions.NewMove(actions.RowColorRed, 3)).Active +
+		cellOdds(t, odds, actions.NewMove(actions.RowColorRed, 4)).Active
+	require.Equal(t, 2, analysis.Skipped)
+	require.InDelta(t, expectedSkipped, analysis.ExpectedSkipped, 1e-9)
+	// a second cross in a row adds two points, while each skipped cell costs the third cross's three points
+	require.Equal(t, 2, analysis.ScoreGain)
+	require.InDelta(t, 2-3*expectedSkipped, analysis.ScoreImpact, 1e-9)
+
+	// crossing off the rightmost cell crosses off the lock too, and skips nothing when the cells before it are crossed off
+	analysis, err = AnalyzeMove(parseBoard(t, "Y:2,3,4,5,6,7,8,9,10,11"), actions.NewMove(actions.RowColorYellow, 12))
+	require.NoError(t, err)
+	require.Zero(t, analysis.Skipped)
+	require.Equal(t, 78-55, analysis.ScoreGain)
+	require.InDelta(t, 23, analysis.ScoreImpact, 1e-9)
+
+	_, err = AnalyzeMove(b, actions.NewMove(actions.RowColorGreen, 11))
+	require.ErrorIs(t, err, board.ErrCellAlreadyCrossed)
+}
+
+func TestAnalyzeActiveTurns(t *testing.T) {
+	b := parseBoard(t, "R:2,3 Y:2")
+	diceRoll := actions.DiceRoll{
+		WhiteDiceRoll: actions.WhiteDiceRoll{White1: 1, White2: 3},
+		ColorDiceRoll: actions.ColorDiceRoll{Red: 2, Yellow: 6, Green: 6, Blue: 6},
+	}
+
+	analyses := AnalyzeActiveTurns(b, diceRoll, 5)
+	require.Len(t, analyses, len(rule_checker.LegalActiveTurns(b, diceRoll)))
+	for idx := 1; idx < len(analyses); idx++ {
+		require.GreaterOrEqual(t, analyses[idx-1].ScoreImpact, analyses[idx].ScoreImpact)
+	}
+
+	// red 4 with the white dice and then red 5 with the red die skip nothing and add the third and fourth cross
+	best := analyses[0]
+	require.Equal(t, actions.NewMove(actions.RowColorRed, 4), *best.Turn.WhiteDiceMove)
+	require.Equal(t, actions.NewMove(actions.RowColorRed, 5), *best.Turn.ColorDiceMove)
+	require.Len(t, best.Moves, 2)
+	require.Zero(t, best.Skipped)
+	require.Equal(t, 3+4, best.ScoreGain)
+
+	penaltyIdx := slices.IndexFunc(analyses, func(analysis TurnAnalysis) bool {
+		return analysis.Turn == actions.ActivePlayerTurn{}
+	})
+	require.NotEqual(t, -1, penaltyIdx)
+	penalty := analyses[penaltyIdx]
+	require.Empty(t, penalty.Moves)
+	require.Equal(t, -5, penalty.ScoreGain)
+	require.InDelta(t, -5, penalty.ScoreImpact, 1e-9)
+}
+
+func TestAnalyzeInactiveTurns(t *testing.T) {
+	b := parseBoard(t, "R:2,3")
+	diceRoll := actions.DiceRoll{
+		WhiteDiceRoll: actions.WhiteDiceRoll{White1: 2, White2: 2},
+		ColorDiceRoll: actions.ColorDiceRoll{Red: 1, Yellow: 1, Green: 1, Blue: 1},
+	}
+
+	analyses := AnalyzeInactiveTurns(b, diceRoll)
+	require.Len(t, analyses, 5)
+	require.Equal(t, actions.NewMove(actions.RowColorRed, 4), *analyses[0].Turn.WhiteDiceMove)
+	require.Nil(t, analyses[0].Turn.ColorDiceMove)
+
+	// skipping the two rarest cells of the yellow row is worth the cross, while skipping most of the green
+	// or blue row is not, so passing comes right after yellow 4
+	require.Equal(t, actions.NewMove(actions.RowColorYellow, 4), *analyses[1].Turn.WhiteDiceMove)
+	require.Nil(t, analyses[2].Turn.WhiteDiceMove)
+	require.Empty(t, analyses[2].Moves)
+	require.Zero(t, analyses[2].ScoreImpact)
+}
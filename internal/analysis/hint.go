@@ -0,0 +1,74 @@
+package analysis
+
+import (
+	"fmt"
+	"qwixx/internal/game/actions"
+	"qwixx/internal/game/board"
+	"strings"
+)
+
+// Hint is the turn recommended to a player, with a short explanation of why,
+// such as "crosses off Yellow 5 skipping 1 cell; avoids penalty"
+type Hint struct {
+	// Turn is the recommended turn, of which only the white dice move is set for an inactive player
+	Turn        actions.ActivePlayerTurn
+	Explanation string
+}
+
+// ActiveHint recommends the turn of the active player with the best estimated score impact on the given board with the given roll,
+// where taking a penalty costs the given penalty value
+func ActiveHint(b board.Board, diceRoll actions.DiceRoll, penaltyValue int) Hint {
+	analyses := AnalyzeActiveTurns(b, diceRoll, penaltyValue)
+	best := analyses[0]
+	if len(best.Moves) > 0 {
+		return Hint{Turn: best.Turn, Explanation: explainMoves(b, best) + "; avoids penalty"}
+	}
+	if len(analyses) == 1 {
+		return Hint{Turn: best.Turn, Explanation: "takes a penalty; nothing can be crossed off"}
+	}
+	return Hint{
+		Turn:        best.Turn,
+		Explanation: fmt.Sprintf("takes a penalty; every move gives up more than the %v points it costs", penaltyValue),
+	}
+}
+
+// InactiveHint recommends the turn of an inactive player with the best estimated score impact on the given board with the given roll
+func InactiveHint(b board.Board, diceRoll actions.DiceRoll) Hint {
+	analyses := AnalyzeInactiveTurns(b, diceRoll)
+	best := analyses[0]
+	if len(best.Moves) > 0 {
+		return Hint{Turn: best.Turn, Explanation: explainMoves(b, best)}
+	}
+	if len(analyses) == 1 {
+		return Hint{Turn: best.Turn, Explanation: "passes; nothing can be crossed off"}
+	}
+	return Hint{Turn: best.Turn, Explanation: "passes; every move skips cells worth more than it gains"}
+}
+
+// explainMoves describes the moves of the given turn on the given board, and the rows they lock
+func explainMoves(b board.Board, analysis TurnAnalysis) string {
+	crosses := make([]string, 0, len(analysis.Moves))
+	var locks []string
+	for _, moveAnalysis := range analysis.Moves {
+		move := moveAnalysis.Move
+		crosses = append(crosses, fmt.Sprintf("%v %v skipping %v", move.RowColor, move.CellNumber, countCells(moveAnalysis.Skipped)))
+		// crossing off the rightmost cell of a row locks it
+		cells := b.Cells(move.RowColor)
+		if cells[len(cells)-1].Number == move.CellNumber {
+			locks = append(locks, fmt.Sprintf("locks the %v row", move.RowColor))
+		}
+	}
+	return strings.Join(append([]string{"crosses off " + strings.Join(crosses, ", then ")}, locks...), "; ")
+}
+
+// countCells writes a number of cells in words, like "no cells" or "1 cell"
+func countCells(count int) string {
+	switch count {
+	case 0:
+		return "no cells"
+	case 1:
+		return "1 cell"
+	default:
+		return fmt.Sprintf("%v cells", count)
+	}
+}
@@ -0,0 +1,99 @@
+package analysis
+
+import (
+	"qwixx/internal/game/actions"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHint(t *testing.T) {
+	type testCase struct {
+		name                string
+		inputBoard          string
+		inputDiceRoll       actions.DiceRoll
+		inputActive         bool
+		expectedTurn        actions.ActivePlayerTurn
+		expectedExplanation string
+	}
+	rollOf := func(white1, white2, color int) actions.DiceRoll {
+		return actions.DiceRoll{
+			WhiteDiceRoll: actions.WhiteDiceRoll{White1: white1, White2: white2},
+			ColorDiceRoll: actions.ColorDiceRoll{Red: color, Yellow: color, Green: color, Blue: color},
+		}
+	}
+	moveOf := func(rowColor actions.RowColor, cellNumber int) *actions.Move {
+		move := actions.NewMove(rowColor, cellNumber)
+		return &move
+	}
+	testCases := []testCase{
+		{
+			name:          "both dice crossing off the next cells of a row",
+			inputBoard:    "R:2,3 Y:2 G:12,11,10 B:12,11,10",
+			inputDiceRoll: rollOf(1, 3, 2),
+			inputActive:   true,
+			expectedTurn: actions.ActivePlayerTurn{
+				WhiteDiceMove: moveOf(actions.RowColorRed, 4),
+				ColorDiceMove: moveOf(actions.RowColorRed, 5),
+			},
+			expectedExplanation: "crosses off Red 4 skipping no cells, then Red 5 skipping no cells; avoids penalty",
+		},
+		{
+			name:                "penalty as nothing can be crossed off",
+			inputBoard:          "R:L Y:L G:L B:L",
+			inputDiceRoll:       rollOf(3, 4, 2),
+			inputActive:         true,
+			expectedTurn:        actions.ActivePlayerTurn{},
+			expectedExplanation: "takes a penalty; nothing can be crossed off",
+		},
+		{
+			name:                "penalty as every move skips too much",
+			inputBoard:          "R:2,3 Y:2,3 G:12,11 B:12,11",
+			inputDiceRoll:       rollOf(6, 5, 6),
+			inputActive:         true,
+			expectedTurn:        actions.ActivePlayerTurn{},
+			expectedExplanation: "takes a penalty; every move gives up more than the 5 points it costs",
+		},
+		{
+			name:                "locking a row",
+			inputBoard:          "R:2,3,4,5,6,7,8,9,10,11 Y:2 G:12 B:12",
+			inputDiceRoll:       rollOf(6, 6, 6),
+			expectedTurn:        actions.ActivePlayerTurn{WhiteDiceMove: moveOf(actions.RowColorRed, 12)},
+			expectedExplanation: "crosses off Red 12 skipping no cells; locks the Red row",
+		},
+		{
+			name:                "skipping a cell",
+			inputBoard:          "R:2,3,4 Y:2,3,4 G:12,11,10 B:12,11,10",
+			inputDiceRoll:       rollOf(3, 3, 1),
+			expectedTurn:        actions.ActivePlayerTurn{WhiteDiceMove: moveOf(actions.RowColorRed, 6)},
+			expectedExplanation: "crosses off Red 6 skipping 1 cell",
+		},
+		{
+			name:                "pass as every move skips too much",
+			inputBoard:          "R:2,3 Y:2,3 G:12,11 B:12,11",
+			inputDiceRoll:       rollOf(6, 5, 6),
+			expectedTurn:        actions.ActivePlayerTurn{},
+			expectedExplanation: "passes; every move skips cells worth more than it gains",
+		},
+		{
+			name:                "pass as nothing can be crossed off",
+			inputBoard:          "R:L Y:L G:L B:L",
+			inputDiceRoll:       rollOf(3, 4, 2),
+			expectedTurn:        actions.ActivePlayerTurn{},
+			expectedExplanation: "passes; nothing can be crossed off",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			b := parseBoard(t, tc.inputBoard)
+			var hint Hint
+			if tc.inputActive {
+				hint = ActiveHint(b, tc.inputDiceRoll, 5)
+			} else {
+				hint = InactiveHint(b, tc.inputDiceRoll)
+			}
+			require.Equal(t, tc.expectedTurn, hint.Turn)
+			require.Equal(t, tc.expectedExplanation, hint.Explanation)
+		})
+	}
+}
@@ -0,0 +1,168 @@
+// Package botclient plays games hosted on a remote server with any player.Player,
+// speaking the server's websocket protocol through the client package the way the clients of humans do
+package botclient
+
+import (
+	"errors"
+	"log/slog"
+	"qwixx/client"
+	"qwixx/internal/game/actions"
+	"qwixx/internal/game/board"
+	"qwixx/internal/game/player"
+	"qwixx/internal/logging"
+	"slices"
+	"strings"
+)
+
+// Client passes the messages of the server it is connected to on to a player, and answers the prompts of the server with the player's turns
+type Client struct {
+	client *client.Client
+	player player.Player
+	logger *slog.Logger
+
+	self    player.PlayerID
+	started bool
+	// opponentBoards are the boards of the opponents as the server last sent them, to tell which cells they crossed off since
+	opponentBoards map[player.PlayerID]board.State
+}
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithLogger logs what the client hears about its lobby and game, silent by default
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *Client) {
+		c.logger = logger
+	}
+}
+
+// Dial connects a client playing with the given player to the server at the given websocket url, such as ws://localhost:8080/ws
+func Dial(serverURL string, p player.Player, options ...Option) (*Client, error) {
+	connection, err := client.Dial(serverURL)
+	if err != nil {
+		return nil, err
+	}
+	return New(connection, p, options...), nil
+}
+
+// New creates a client playing with the given player over the given connection to the server
+func New(connection *client.Client, p player.Player, options ...Option) *Client {
+	c := &Client{
+		client:         connection,
+		player:         p,
+		logger:         logging.Discard(),
+		opponentBoards: make(map[player.PlayerID]board.State),
+	}
+	for _, option := range options {
+		option(c)
+	}
+	return c
+}
+
+// Join asks to join the lobby with the given code under the name of the client's player
+func (c *Client) Join(code string) error {
+	return c.client.JoinLobby(code, c.player.GetName())
+}
+
+// Close closes the connection to the server
+func (c *Client) Close() error {
+	return c.client.Close()
+}
+
+// ErrSeatLost is the error of a client whose connection was lost and made again,
+// the server not keeping the seat of the lost connection in its lobby or game
+var ErrSeatLost = errors.New("reconnected to the server, which does not keep the seat of a lost connection")
+
+// Play plays the game of the lobby the client is in once its host starts it, returning how the game ended.
+// It fails if the server rejects a request before the game starts, such as joining a lobby that does not exist,
+// and with ErrSeatLost if the connection is lost and made again.
+func (c *Client) Play() (client.GameOver, error) {
+	for event := range c.client.Events() {
+		gameOver, done, err := c.handleEvent(event)
+		if err != nil || done {
+			return gameOver, err
+		}
+	}
+	if err := c.client.Err(); err != nil {
+		return client.GameOver{}, err
+	}
+	return client.GameOver{}, client.ErrClosed
+}
+
+// handleEvent passes the given message of the server on to the player, reporting whether it ended the game
+func (c *Client) handleEvent(event client.Event) (client.GameOver, bool, error) {
+	if event.Type == client.MessageReconnected {
+		return client.GameOver{}, false, ErrSeatLost
+	}
+	switch payload := event.Payload.(type) {
+	case client.Error:
+		if !c.started {
+			return client.GameOver{}, false, errors.New(payload.Message)
+		}
+		c.logger.Warn("the server reported an error", "error", payload.Message)
+	case client.LobbyState:
+		c.logger.Info("waiting in lobby", "code", payload.Code, "host", payload.Host, "players", strings.Join(payload.Players, ", "))
+	case client.GameStarted:
+		c.self = payload.You
+		c.started = true
+		if listener, ok := c.player.(player.PenaltyValueListener); ok {
+			listener.InformOfPenaltyValue(payload.Config.WithDefaults().PenaltyValue)
+		}
+		c.logger.Info("game started", "game_id", payload.GameID)
+	case client.PlayOrder:
+		c.player.InformOfPlayOrder(payload.Names)
+	case client.Prompt:
+		return client.GameOver{}, false, c.answer(event.Type == client.MessagePromptActive, payload)
+	case client.InvalidTurn:
+		c.player.InformInvalidTurn(&board.RuleViolation{Code: payload.Code, Message: payload.Message})
+	case client.BoardUpdate:
+		if payload.PlayerID == c.self {
+			updatedBoard, err := board.FromState(payload.Board)
+			if err != nil {
+				return client.GameOver{}, false, err
+			}
+			c.player.InformSuccessfulTurn(updatedBoard)
+			break
+		}
+		for _, move := range newMoves(c.opponentBoards[payload.PlayerID], payload.Board) {
+			c.player.InformOfOpponentMove(payload.PlayerID, move)
+		}
+		c.opponentBoards[payload.PlayerID] = payload.Board
+	case client.RowLocked:
+		c.player.InformRowLocked(payload.RowColor)
+	case client.GameOver:
+		if payload.Won {
+			c.player.InformWin()
+		} else {
+			c.player.InformLoss(payload.WinnerID)
+		}
+		return payload, true, nil
+	}
+	return client.GameOver{}, false, nil
+}
+
+// answer prompts the player for a turn on the board and with the dice of the given prompt, and submits the turn
+func (c *Client) answer(active bool, prompt client.Prompt) error {
+	playerBoard, err := board.FromState(prompt.Board)
+	if err != nil {
+		return err
+	}
+	if active {
+		turn := c.player.PromptActivePlayerTurn(playerBoard, prompt.DiceRoll)
+		return c.client.SubmitTurn(prompt.PromptID, turn.WhiteDiceMove, turn.ColorDiceMove)
+	}
+	return c.client.SubmitTurn(prompt.PromptID, c.player.PromptInactivePlayerTurn(playerBoard, prompt.DiceRoll).WhiteDiceMove, nil)
+}
+
+// newMoves lists the cells crossed off in the given updated board state that were not crossed off in the previous one
+func newMoves(previous, updated board.State) []actions.Move {
+	var moves []actions.Move
+	for rowColor, cellNumbers := range updated.Rows {
+		for _, cellNumber := range cellNumbers {
+			if !slices.Contains(previous.Rows[rowColor], cellNumber) {
+				moves = append(moves, actions.NewMove(rowColor, cellNumber))
+			}
+		}
+	}
+	return moves
+}
@@ -0,0 +1,196 @@
+package botclient
+
+import (
+	"qwixx/client"
+	"qwixx/internal/game/actions"
+	"qwixx/internal/game/board"
+	"qwixx/internal/game/player"
+	"qwixx/internal/server/servertest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// host creates a lobby on the server at the given url, returning its client and the code of the lobby
+func host(t *testing.T, url string) (*client.Client, string) {
+	t.Helper()
+	hostClient, err := client.Dial(url)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = hostClient.Close() })
+	require.NoError(t, hostClient.CreateLobby("host", "", "", client.GameConfig{}))
+	lobby := await[client.LobbyState](t, hostClient)
+	return hostClient, lobby.Code
+}
+
+// await returns the payload of the next event whose payload is of the given type, skipping other events
+func await[T any](t *testing.T, c *client.Client) T {
+	t.Helper()
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case event, ok := <-c.Events():
+			require.True(t, ok, "events closed: %v", c.Err())
+			if payload, ok := event.Payload.(T); ok {
+				return payload
+			}
+		case <-timeout:
+			var payload T
+			t.Fatalf("no %T event", payload)
+		}
+	}
+}
+
+// recordingPlayer is a strategy player that remembers what it was told
+type recordingPlayer struct {
+	player.Player
+	playOrder []string
+	prompts   int
+	turns     int
+}
+
+func (r *recordingPlayer) InformOfPlayOrder(playerNames []string) {
+	r.playOrder = playerNames
+	r.Player.InformOfPlayOrder(playerNames)
+}
+
+func (r *recordingPlayer) PromptActivePlayerTurn(playerBoard board.Board, diceRoll actions.DiceRoll) actions.ActivePlayerTurn {
+	r.prompts++
+	return r.Player.PromptActivePlayerTurn(playerBoard, diceRoll)
+}
+
+func (r *recordingPlayer) PromptInactivePlayerTurn(playerBoard board.Board, diceRoll actions.DiceRoll) actions.InactivePlayerTurn {
+	r.prompts++
+	return r.Player.PromptInactivePlayerTurn(playerBoard, diceRoll)
+}
+
+func (r *recordingPlayer) InformSuccessfulTurn(updatedBoard board.Board) {
+	r.turns++
+	r.Player.InformSuccessfulTurn(updatedBoard)
+}
+
+func TestClient_Play(t *testing.T) {
+	url := servertest.New(t).URL
+	hostClient, code := host(t, url)
+
+	strategy, err := player.NewStrategy(player.StrategyHard, nil)
+	require.NoError(t, err)
+	bot := &recordingPlayer{Player: player.NewStrategyPlayer("bot", strategy, nil)}
+	botClient, err := Dial(url, bot)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = botClient.Close() })
+	require.NoError(t, botClient.Join(code))
+
+	type outcome struct {
+		gameOver client.GameOver
+		err      error
+	}
+	played := make(chan outcome, 1)
+	go func() {
+		gameOver, err := botClient.Play()
+		played <- outcome{gameOver, err}
+	}()
+
+	var lobby client.LobbyState
+	for len(lobby.Players) < 2 {
+		lobby = await[client.LobbyState](t, hostClient)
+	}
+	require.Equal(t, []string{"host", "bot"}, lobby.Players)
+	require.NoError(t, hostClient.StartGame())
+
+	// the host passes on every prompt, so the game ends once the host has taken four penalties
+	for hostDone := false; !hostDone; {
+		event, ok := <-hostClient.Events()
+		require.True(t, ok, "events closed: %v", hostClient.Err())
+		switch payload := event.Payload.(type) {
+		case client.Prompt:
+			require.NoError(t, hostClient.SubmitTurn(payload.PromptID, nil, nil))
+		case client.GameOver:
+			hostDone = true
+		}
+	}
+
+	select {
+	case result := <-played:
+		require.NoError(t, result.err)
+		require.True(t, result.gameOver.Won)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the bot did not finish its game")
+	}
+	require.ElementsMatch(t, []string{"host", "bot"}, bot.playOrder)
+	require.Positive(t, bot.prompts)
+	require.Positive(t, bot.turns)
+}
+
+func TestClient_JoinUnknownLobby(t *testing.T) {
+	url := servertest.New(t).URL
+	botClient, err := Dial(url, player.NewComputerPlayer("bot"))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = botClient.Close() })
+
+	require.NoError(t, botClient.Join("nonsense"))
+	_, err = botClient.Play()
+	require.ErrorContains(t, err, "no lobby")
+}
+
+func TestClient_PlayLosesSeatOnReconnect(t *testing.T) {
+	testServer := servertest.New(t)
+	hostClient, code := host(t, testServer.URL)
+
+	connection, err := client.Dial(testServer.URL, client.WithReconnect(3, 10*time.Millisecond))
+	require.NoError(t, err)
+	botClient := New(connection, player.NewComputerPlayer("bot"))
+	t.Cleanup(func() { _ = botClient.Close() })
+	require.NoError(t, botClient.Join(code))
+
+	played := make(chan error, 1)
+	go func() {
+		_, err := botClient.Play()
+		played <- err
+	}()
+	for lobby := (client.LobbyState{}); len(lobby.Players) < 2; {
+		lobby = await[client.LobbyState](t, hostClient)
+	}
+	require.NoError(t, hostClient.StartGame())
+	await[client.GameStarted](t, hostClient)
+
+	testServer.DropConnections()
+	select {
+	case err := <-played:
+		require.ErrorIs(t, err, ErrSeatLost)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the bot kept waiting for a game it lost its seat in")
+	}
+}
+
+func TestNewMoves(t *testing.T) {
+	type testCase struct {
+		name          string
+		inputPrevious board.State
+		inputUpdated  board.State
+		expected      []actions.Move
+	}
+	testCases := []testCase{
+		{
+			name:         "first cells of a board",
+			inputUpdated: board.State{Rows: map[actions.RowColor][]int{actions.RowColorRed: {3}}},
+			expected:     []actions.Move{actions.NewMove(actions.RowColorRed, 3)},
+		},
+		{
+			name:          "only the cells crossed off since",
+			inputPrevious: board.State{Rows: map[actions.RowColor][]int{actions.RowColorBlue: {12, 11}}},
+			inputUpdated:  board.State{Rows: map[actions.RowColor][]int{actions.RowColorBlue: {12, 11, 8}}},
+			expected:      []actions.Move{actions.NewMove(actions.RowColorBlue, 8)},
+		},
+		{
+			name:          "unchanged board",
+			inputPrevious: board.State{Rows: map[actions.RowColor][]int{actions.RowColorBlue: {12}}},
+			inputUpdated:  board.State{Rows: map[actions.RowColor][]int{actions.RowColorBlue: {12}}},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, newMoves(tc.inputPrevious, tc.inputUpdated))
+		})
+	}
+}
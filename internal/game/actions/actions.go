@@ -3,6 +3,7 @@ package actions
 import (
 	"fmt"
 	"math/rand"
+	"strings"
 )
 
 type RowColor int
@@ -12,8 +13,16 @@ const (
 	RowColorYellow
 	RowColorGreen
 	RowColorBlue
+	// RowColorOrange and RowColorPurple are the extra rows of the Big Points sheet
+	RowColorOrange
+	RowColorPurple
 )
 
+// RowColors lists every row color, the four of the classic sheet first
+func RowColors() []RowColor {
+	return []RowColor{RowColorRed, RowColorYellow, RowColorGreen, RowColorBlue, RowColorOrange, RowColorPurple}
+}
+
 func (m RowColor) String() string {
 	switch m {
 	case RowColorRed:
@@ -24,15 +33,47 @@ func (m RowColor) String() string {
 		return "Green"
 	case RowColorBlue:
 		return "Blue"
+	case RowColorOrange:
+		return "Orange"
+	case RowColorPurple:
+		return "Purple"
 	default:
 		return ""
 	}
 }
 
+// ParseRowColor parses the name of a row color as produced by String, ignoring case
+func ParseRowColor(name string) (RowColor, error) {
+	for _, color := range RowColors() {
+		if strings.EqualFold(name, color.String()) {
+			return color, nil
+		}
+	}
+	return -1, fmt.Errorf("invalid row color: %q", name)
+}
+
+// MarshalText encodes the row color as its name so that it is readable on the wire and usable as a JSON map key
+func (m RowColor) MarshalText() ([]byte, error) {
+	name := m.String()
+	if name == "" {
+		return nil, fmt.Errorf("invalid row color: %d", m)
+	}
+	return []byte(name), nil
+}
+
+func (m *RowColor) UnmarshalText(text []byte) error {
+	color, err := ParseRowColor(string(text))
+	if err != nil {
+		return err
+	}
+	*m = color
+	return nil
+}
+
 // a Move represents crossing off the square with the given number on the row with the given color
 type Move struct {
-	RowColor   RowColor
-	CellNumber int
+	RowColor   RowColor `json:"row_color"`
+	CellNumber int      `json:"cell_number"`
 }
 
 func (m Move) String() string {
@@ -44,46 +85,75 @@ func NewMove(rowColor RowColor, cellNumber int) Move {
 }
 
 // DiceRoll represents the roll of the six Qwixx dice, where two are white
-// and the other four are one of each row color from the Qwixx board (red, yellow, green, blue)
+// and the other four are one of each row color from the Qwixx board (red, yellow, green, blue).
+// The Big Points edition rolls eight dice, adding an orange and a purple die, which are zero when they were not rolled.
 type DiceRoll struct {
 	WhiteDiceRoll
 	ColorDiceRoll
 }
 
 type WhiteDiceRoll struct {
-	White1 int
-	White2 int
+	White1 int `json:"white1"`
+	White2 int `json:"white2"`
 }
 
 type ColorDiceRoll struct {
-	Red    int
-	Blue   int
-	Green  int
-	Yellow int
+	Red    int `json:"red"`
+	Blue   int `json:"blue"`
+	Green  int `json:"green"`
+	Yellow int `json:"yellow"`
+	Orange int `json:"orange,omitempty"`
+	Purple int `json:"purple,omitempty"`
 }
 
 func RollQwixxDice() DiceRoll {
+	return rollQwixxDice(rand.Intn)
+}
+
+// RollQwixxDiceWith rolls the six Qwixx dice using the given source of randomness, so games can be replayed from a seed
+func RollQwixxDiceWith(rng *rand.Rand) DiceRoll {
+	return rollQwixxDice(rng.Intn)
+}
+
+// RollBigPointsDice rolls the eight dice of the Big Points edition
+func RollBigPointsDice() DiceRoll {
+	return rollBigPointsDice(rand.Intn)
+}
+
+// RollBigPointsDiceWith rolls the eight dice of the Big Points edition using the given source of randomness
+func RollBigPointsDiceWith(rng *rand.Rand) DiceRoll {
+	return rollBigPointsDice(rng.Intn)
+}
+
+// rollBigPointsDice rolls the six classic dice like rollQwixxDice before the two extra dice
+func rollBigPointsDice(intn func(n int) int) DiceRoll {
+	diceRoll := rollQwixxDice(intn)
+	diceRoll.Orange = intn(6) + 1
+	diceRoll.Purple = intn(6) + 1
+	return diceRoll
+}
+
+func rollQwixxDice(intn func(n int) int) DiceRoll {
 	return DiceRoll{
 		WhiteDiceRoll: WhiteDiceRoll{
-			White1: rand.Intn(6) + 1,
-			White2: rand.Intn(6) + 1,
+			White1: intn(6) + 1,
+			White2: intn(6) + 1,
 		},
 		ColorDiceRoll: ColorDiceRoll{
-			Red:    rand.Intn(6) + 1,
-			Yellow: rand.Intn(6) + 1,
-			Green:  rand.Intn(6) + 1,
-			Blue:   rand.Intn(6) + 1,
+			Red:    intn(6) + 1,
+			Yellow: intn(6) + 1,
+			Green:  intn(6) + 1,
+			Blue:   intn(6) + 1,
 		},
 	}
-
 }
 
 // ActivePlayerTurn represents the turn of an active player, where they can cross off a cell both with the sum of
 // the white dice and the sum of one white die with one color die
 // If both moves are nil, a penalty is taken
 type ActivePlayerTurn struct {
-	WhiteDiceMove *Move
-	ColorDiceMove *Move
+	WhiteDiceMove *Move `json:"white_dice_move"`
+	ColorDiceMove *Move `json:"color_dice_move"`
 }
 
 func (apt ActivePlayerTurn) String() string {
@@ -102,5 +172,5 @@ func (apt ActivePlayerTurn) String() string {
 // with the sum of the white dice
 // If the move is nil, nothing happens and no penalty is taken.
 type InactivePlayerTurn struct {
-	WhiteDiceMove *Move
+	WhiteDiceMove *Move `json:"white_dice_move"`
 }
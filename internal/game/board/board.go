@@ -3,22 +3,37 @@ package board
 import (
 	"fmt"
 	"qwixx/internal/game/actions"
+	"qwixx/internal/game/ruleset"
+	"slices"
 )
 
 // Board represents the Qwixx board for a single player.
 // The Qwixx board consists of four rows of eleven cells. The rows have a color: Red, Yellow, Green, Blue from top down.
 // Cells can be empty or crossed off.
-// Each row's cells are numbered from 2 to 12:
+// On the classic sheet each row's cells are numbered from 2 to 12:
 // - Red and Yellow rows are numbers in ascending order from 2-12.
 // - Green ad Blue rows are numbered in descending order from 12-2.
+// The Qwixx Mixx variants mix up the colors or the order of the numbers,
+// and the Big Points sheet adds an orange and a purple bonus row, see Variant.
 type Board interface {
 	Print() string
 	Copy() Board
-	IsMoveValid(move actions.Move) (ok bool, reason string)
+	IsMoveValid(move actions.Move) (ok bool, err error)
 	MakeMove(move actions.Move) error
 	IsCellMarked(rowColor actions.RowColor, cellNumber int) bool
+	IsRowLocked(rowColor actions.RowColor) bool
 	LockRow(color actions.RowColor)
 	CalculateScore() int
+	// RowScore is the score of the row with the given color alone, zero for rows not on the board's sheet
+	RowScore(rowColor actions.RowColor) int
+	// Variant is the sheet this board is laid out like
+	Variant() Variant
+	// Cells lists the cells of the row with the given color from left to right
+	Cells(rowColor actions.RowColor) []Cell
+	// Ruleset is the rules this board is crossed off by
+	Ruleset() ruleset.Ruleset
+	// CrossCount is how many times the given cell has been crossed off, which the ruleset may allow more than once
+	CrossCount(rowColor actions.RowColor, cellNumber int) int
 }
 
 type boardImpl struct {
@@ -26,7 +41,25 @@ type boardImpl struct {
 	yellowRow Row
 	greenRow  Row
 	blueRow   Row
-	locks     map[actions.RowColor]bool
+	// orangeRow and purpleRow are the bonus rows of the Big Points sheet, nil on the other sheets
+	orangeRow Row
+	purpleRow Row
+	// variant is the sheet of the board, the zero value being the classic sheet
+	variant Variant
+	// ruleset is the rules of the board, nil meaning the classic rules
+	ruleset ruleset.Ruleset
+}
+
+// Option changes how a board created by NewBoard is played
+type Option func(b *boardImpl)
+
+// WithRuleset makes the board follow the given rules for crossing off cells instead of the classic ones
+func WithRuleset(rules ruleset.Ruleset) Option {
+	return func(b *boardImpl) {
+		if rules != nil && rules.Name() != ruleset.NameClassic {
+			b.ruleset = rules
+		}
+	}
 }
 
 func NewGameBoard() Board {
@@ -38,90 +71,193 @@ func NewGameBoard() Board {
 	}
 }
 
-func (b *boardImpl) Copy() Board {
-	locksCopy := make(map[actions.RowColor]bool)
-	for k, v := range b.locks {
-		locksCopy[k] = v
+// NewBoard creates an empty board laid out like the sheet of the given variant
+func NewBoard(variant Variant, options ...Option) (Board, error) {
+	if variant == "" {
+		variant = VariantClassic
 	}
-	return &boardImpl{
-		redRow:    b.redRow.Copy(),
-		yellowRow: b.redRow.Copy(),
-		greenRow:  b.redRow.Copy(),
-		blueRow:   b.redRow.Copy(),
-		locks:     locksCopy,
+	if !slices.Contains(Variants(), variant) {
+		return nil, fmt.Errorf("unknown variant %q", variant)
+	}
+	b := &boardImpl{variant: variant}
+	for _, option := range options {
+		option(b)
+	}
+	if variant == VariantClassic && b.ruleset == nil {
+		return NewGameBoard(), nil
+	}
+	if variant == VariantClassic {
+		// the classic sheet is the zero value of the variant, so boards compare equal however they were made
+		b.variant = ""
 	}
+	for _, rowColor := range variant.RowColors() {
+		*b.rowField(rowColor) = newVariantRow(rowTypeOf(rowColor), rowColor, variant, variant.Cells(rowColor), b.ruleset)
+	}
+	return b, nil
 }
 
-func (b *boardImpl) Print() string {
-	var textRepresentation string
+// rowField points at the field holding the row with the given color, which must be one of actions.RowColors
+func (b *boardImpl) rowField(rowColor actions.RowColor) *Row {
+	switch rowColor {
+	case actions.RowColorRed:
+		return &b.redRow
+	case actions.RowColorYellow:
+		return &b.yellowRow
+	case actions.RowColorGreen:
+		return &b.greenRow
+	case actions.RowColorBlue:
+		return &b.blueRow
+	case actions.RowColorOrange:
+		return &b.orangeRow
+	default:
+		return &b.purpleRow
+	}
+}
 
-	textRepresentation += "Red: "
-	textRepresentation += b.redRow.Print()
-	textRepresentation += "\n"
+// row returns the row with the given color, or nil if the board has no such row
+func (b *boardImpl) row(rowColor actions.RowColor) Row {
+	if !slices.Contains(actions.RowColors(), rowColor) {
+		return nil
+	}
+	return *b.rowField(rowColor)
+}
 
-	textRepresentation += "Yellow: "
-	textRepresentation += b.yellowRow.Print()
-	textRepresentation += "\n"
+func (b *boardImpl) Copy() Board {
+	copied := &boardImpl{variant: b.variant, ruleset: b.ruleset}
+	for _, rowColor := range b.Variant().RowColors() {
+		*copied.rowField(rowColor) = b.row(rowColor).Copy()
+	}
+	return copied
+}
+
+func (b *boardImpl) Variant() Variant {
+	if b.variant == "" {
+		return VariantClassic
+	}
+	return b.variant
+}
 
-	textRepresentation += "Green: "
-	textRepresentation += b.greenRow.Print()
-	textRepresentation += "\n"
+func (b *boardImpl) Cells(rowColor actions.RowColor) []Cell {
+	return b.Variant().Cells(rowColor)
+}
 
-	textRepresentation += "Blue: "
-	textRepresentation += b.blueRow.Print()
+func (b *boardImpl) Ruleset() ruleset.Ruleset {
+	if b.ruleset == nil {
+		return ruleset.Classic()
+	}
+	return b.ruleset
+}
 
+func (b *boardImpl) Print() string {
+	var textRepresentation string
+	for idx, rowColor := range b.Variant().RowColors() {
+		if idx > 0 {
+			textRepresentation += "\n"
+		}
+		textRepresentation += rowColor.String() + ": "
+		textRepresentation += b.row(rowColor).Print()
+	}
 	return textRepresentation
 }
 
-func (b *boardImpl) IsMoveValid(move actions.Move) (ok bool, reason string) {
-	switch move.RowColor {
-	case actions.RowColorRed:
-		return b.redRow.IsMoveValid(move.CellNumber)
-	case actions.RowColorYellow:
-		return b.yellowRow.IsMoveValid(move.CellNumber)
-	case actions.RowColorGreen:
-		return b.greenRow.IsMoveValid(move.CellNumber)
-	case actions.RowColorBlue:
-		return b.blueRow.IsMoveValid(move.CellNumber)
-	default:
-		return false, fmt.Sprintf("invalid move row color: %d", move.RowColor)
+func (b *boardImpl) IsMoveValid(move actions.Move) (ok bool, err error) {
+	row := b.row(move.RowColor)
+	if row == nil {
+		return false, NewRuleViolation(CodeInvalidColor, "invalid move row color: %d", move.RowColor)
+	}
+	if ok, err := row.IsMoveValid(move.CellNumber); !ok {
+		return false, err
+	}
+	if err := b.checkBonusNeighbor(move); err != nil {
+		return false, err
 	}
+	return true, nil
 }
 
+// MakeMove crosses off the cell of the given move, along with the cells the ruleset links to it that can be crossed off
 func (b *boardImpl) MakeMove(move actions.Move) error {
-	switch move.RowColor {
-	case actions.RowColorRed:
-		return b.redRow.MakeMove(move.CellNumber)
-	case actions.RowColorYellow:
-		return b.yellowRow.MakeMove(move.CellNumber)
-	case actions.RowColorGreen:
-		return b.greenRow.MakeMove(move.CellNumber)
-	case actions.RowColorBlue:
-		return b.blueRow.MakeMove(move.CellNumber)
-	default:
-		return fmt.Errorf("invalid move row color: %d", move.RowColor)
+	if err := b.makeMove(move); err != nil {
+		return err
+	}
+	for _, linked := range b.Ruleset().LinkedMoves(move) {
+		if ok, _ := b.IsMoveValid(linked); ok {
+			_ = b.makeMove(linked)
+		}
 	}
+	return nil
 }
 
+// makeMove crosses off the cell of the given move without following links
+func (b *boardImpl) makeMove(move actions.Move) error {
+	row := b.row(move.RowColor)
+	if row == nil {
+		return NewRuleViolation(CodeInvalidColor, "invalid move row color: %d", move.RowColor)
+	}
+	if ok, err := row.IsMoveValid(move.CellNumber); !ok {
+		return err
+	}
+	if err := b.checkBonusNeighbor(move); err != nil {
+		return err
+	}
+	return row.MakeMove(move.CellNumber)
+}
+
+// checkBonusNeighbor checks a move in a bonus row has a crossed off cell of the same number directly above or below it
+func (b *boardImpl) checkBonusNeighbor(move actions.Move) error {
+	if !b.Variant().IsBonusRow(move.RowColor) {
+		return nil
+	}
+	neighbors := b.Variant().Neighbors(move.RowColor)
+	for _, neighbor := range neighbors {
+		if b.IsCellMarked(neighbor, move.CellNumber) {
+			return nil
+		}
+	}
+	return NewRuleViolation(
+		CodeBonusWithoutNeighbor,
+		"cell %v of the %v bonus row needs cell %v of the %v or %v row crossed off",
+		move.CellNumber, move.RowColor, move.CellNumber, neighbors[0], neighbors[len(neighbors)-1],
+	)
+}
+
+// LockRow locks the row of the given color so no further cells can be crossed off in it
 func (b *boardImpl) LockRow(color actions.RowColor) {
-	b.locks[color] = true
+	if row := b.row(color); row != nil {
+		row.Lock()
+	}
+}
+
+func (b *boardImpl) IsRowLocked(rowColor actions.RowColor) bool {
+	row := b.row(rowColor)
+	return row != nil && row.IsLocked()
 }
 
+// CalculateScore adds up the scores of every row, bonus rows included
 func (b *boardImpl) CalculateScore() int {
-	return b.redRow.CalculateScore() + b.yellowRow.CalculateScore() + b.greenRow.CalculateScore() + b.blueRow.CalculateScore()
+	score := 0
+	for _, rowColor := range b.Variant().RowColors() {
+		score += b.RowScore(rowColor)
+	}
+	return score
+}
+
+func (b *boardImpl) RowScore(rowColor actions.RowColor) int {
+	row := b.row(rowColor)
+	if row == nil {
+		return 0
+	}
+	return row.CalculateScore()
 }
 
 func (b *boardImpl) IsCellMarked(rowColor actions.RowColor, cellNumber int) bool {
-	switch rowColor {
-	case actions.RowColorRed:
-		return b.redRow.IsCellMarked(cellNumber)
-	case actions.RowColorYellow:
-		return b.yellowRow.IsCellMarked(cellNumber)
-	case actions.RowColorGreen:
-		return b.greenRow.IsCellMarked(cellNumber)
-	case actions.RowColorBlue:
-		return b.blueRow.IsCellMarked(cellNumber)
-	default:
-		return false
+	row := b.row(rowColor)
+	return row != nil && row.IsCellMarked(cellNumber)
+}
+
+func (b *boardImpl) CrossCount(rowColor actions.RowColor, cellNumber int) int {
+	row := b.row(rowColor)
+	if row == nil {
+		return 0
 	}
+	return row.CrossCount(cellNumber)
 }
@@ -101,13 +101,8 @@ func TestBoardImpl_MakeMove(t *testing.T) {
 				RowColor:   actions.RowColorRed,
 				CellNumber: 3,
 			},
-			expectedOk: true,
-			expectedGameBoardState: &boardImpl{
-				redRow:    newRedRowFromCells([]int{0, 1, 0, 0, 0, 0, 0, 0, 0, 0, 0}, false),
-				yellowRow: NewYellowRow(),
-				greenRow:  NewGreenRow(),
-				blueRow:   NewBlueRow(),
-			},
+			expectedOk:             true,
+			expectedGameBoardState: parseBoard(t, "R:3"),
 		},
 		{
 			name:           "brand new game, invalid move should leave board unchanged",
@@ -121,45 +116,25 @@ func TestBoardImpl_MakeMove(t *testing.T) {
 			expectedGameBoardState: NewGameBoard(),
 		},
 		{
-			name: "mid game, valid move should leave board with a cell crossed off",
-			inputGameBoard: &boardImpl{
-				redRow:    newRedRowFromCells([]int{0, 1, 0, 0, 1, 1, 0, 0, 0, 0, 0}, false),
-				yellowRow: newYellowRowFromCells([]int{1, 0, 1, 0, 0, 0, 1, 0, 0, 0, 0}, false),
-				greenRow:  newGreenRowFromCells([]int{0, 1, 0, 1, 1, 1, 0, 0, 0, 0, 0}, false),
-				blueRow:   newBlueRowFromCells([]int{0, 1, 0, 1, 0, 0, 0, 0, 0, 0, 0}, false),
-			},
+			name:           "mid game, valid move should leave board with a cell crossed off",
+			inputGameBoard: parseBoard(t, "R:3,6,7 Y:2,4,8 G:11,9,8,7 B:11,9"),
 			inputMove: actions.Move{
 				RowColor:   actions.RowColorGreen,
 				CellNumber: 4,
 			},
-			expectedOk: true,
-			expectedGameBoardState: &boardImpl{
-				redRow:    newRedRowFromCells([]int{0, 1, 0, 0, 1, 1, 0, 0, 0, 0, 0}, false),
-				yellowRow: newYellowRowFromCells([]int{1, 0, 1, 0, 0, 0, 1, 0, 0, 0, 0}, false),
-				greenRow:  newGreenRowFromCells([]int{0, 1, 0, 1, 1, 1, 0, 0, 1, 0, 0}, false),
-				blueRow:   newBlueRowFromCells([]int{0, 1, 0, 1, 0, 0, 0, 0, 0, 0, 0}, false),
-			},
+			expectedOk:             true,
+			expectedGameBoardState: parseBoard(t, "R:3,6,7 Y:2,4,8 G:11,9,8,7,4 B:11,9"),
 		},
 		{
-			name: "mid game, invalid move should leave board unchanged",
-			inputGameBoard: &boardImpl{
-				redRow:    newRedRowFromCells([]int{0, 1, 0, 0, 1, 1, 0, 0, 0, 0, 0}, false),
-				yellowRow: newYellowRowFromCells([]int{1, 0, 1, 0, 0, 0, 1, 0, 0, 0, 0}, false),
-				greenRow:  newGreenRowFromCells([]int{0, 1, 0, 1, 1, 1, 0, 0, 0, 0, 0}, false),
-				blueRow:   newBlueRowFromCells([]int{0, 1, 0, 1, 0, 0, 0, 0, 0, 0, 0}, false),
-			},
+			name:           "mid game, invalid move should leave board unchanged",
+			inputGameBoard: parseBoard(t, "R:3,6,7 Y:2,4,8 G:11,9,8,7 B:11,9"),
 			inputMove: actions.Move{
 				RowColor:   actions.RowColorGreen,
 				CellNumber: 10,
 			},
-			expectedOk:  false,
-			expectedErr: errors.New("cell 10 is to the left of already crossed off cells"),
-			expectedGameBoardState: &boardImpl{
-				redRow:    newRedRowFromCells([]int{0, 1, 0, 0, 1, 1, 0, 0, 0, 0, 0}, false),
-				yellowRow: newYellowRowFromCells([]int{1, 0, 1, 0, 0, 0, 1, 0, 0, 0, 0}, false),
-				greenRow:  newGreenRowFromCells([]int{0, 1, 0, 1, 1, 1, 0, 0, 0, 0, 0}, false),
-				blueRow:   newBlueRowFromCells([]int{0, 1, 0, 1, 0, 0, 0, 0, 0, 0, 0}, false),
-			},
+			expectedOk:             false,
+			expectedErr:            NewRuleViolation(CodeLeftOfCrossedCell, "cell 10 is to the left of already crossed off cells"),
+			expectedGameBoardState: parseBoard(t, "R:3,6,7 Y:2,4,8 G:11,9,8,7 B:11,9"),
 		},
 	}
 
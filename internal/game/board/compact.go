@@ -0,0 +1,215 @@
+package board
+
+import (
+	"fmt"
+	"math/bits"
+	"qwixx/internal/game/actions"
+	"qwixx/internal/game/ruleset"
+)
+
+// The bits of a row of a CompactBoard
+const (
+	// compactCells are the eleven cells of the row from left to right, the lowest bit being the leftmost cell
+	compactCells uint16 = 1<<11 - 1
+	// compactLastCell is the rightmost cell, the one crossed off to lock the row
+	compactLastCell uint16 = 1 << 10
+	// compactLocked is set once the row is locked
+	compactLocked uint16 = 1 << 11
+	// compactPenalty is one box of the penalty track, each of the four rows holding one of them
+	compactPenalty uint16 = 1 << 12
+)
+
+// CompactBoard is a board laid out like the classic sheet and played by the classic rules,
+// packing each row into 16 bits so that it can be copied and checked without allocating.
+// It is meant for simulations running through many boards, and behaves exactly like the board NewGameBoard creates,
+// validating and scoring moves the same way and returning the same errors.
+// On top of that it keeps the four boxes of the penalty track, one in each row.
+type CompactBoard struct {
+	rows [4]uint16
+}
+
+var _ Board = &CompactBoard{}
+
+// NewCompactBoard creates an empty compact board
+func NewCompactBoard() *CompactBoard {
+	return &CompactBoard{}
+}
+
+// CompactBoardOf creates a compact board with the same cells crossed off and rows locked as the given board,
+// which must be laid out like the classic sheet and played by the classic rules
+func CompactBoardOf(b Board) (*CompactBoard, error) {
+	if b.Variant() != VariantClassic {
+		return nil, fmt.Errorf("compact boards only have the classic sheet, not the %v sheet", b.Variant())
+	}
+	if b.Ruleset().Name() != ruleset.NameClassic {
+		return nil, fmt.Errorf("compact boards only follow the classic rules, not the %v rules", b.Ruleset().Name())
+	}
+	compact := NewCompactBoard()
+	for _, rowColor := range rowColors {
+		for idx, cell := range b.Cells(rowColor) {
+			if b.IsCellMarked(rowColor, cell.Number) {
+				compact.rows[rowColor] |= 1 << idx
+			}
+		}
+		if b.IsRowLocked(rowColor) {
+			compact.rows[rowColor] |= compactLocked
+		}
+	}
+	return compact, nil
+}
+
+// cellIndex returns the index of the cell with the given number in the row with the given color, counted from the left
+func cellIndex(rowColor actions.RowColor, cellNumber int) (int, error) {
+	if cellNumber < 2 || cellNumber > 12 {
+		return -1, fmt.Errorf("invalid cell number: %v. must be between 2 and 12", cellNumber)
+	}
+	if rowColor == actions.RowColorGreen || rowColor == actions.RowColorBlue {
+		return 12 - cellNumber, nil
+	}
+	return cellNumber - 2, nil
+}
+
+// hasRow determines if the board has a row of the given color
+func (b *CompactBoard) hasRow(rowColor actions.RowColor) bool {
+	return rowColor >= actions.RowColorRed && rowColor <= actions.RowColorBlue
+}
+
+func (b *CompactBoard) Print() string {
+	var textRepresentation string
+	for _, rowColor := range rowColors {
+		if rowColor != actions.RowColorRed {
+			textRepresentation += "\n"
+		}
+		textRepresentation += rowColor.String() + ": "
+		for idx, cell := range VariantClassic.Cells(rowColor) {
+			value := int(b.rows[rowColor] >> idx & 1)
+			textRepresentation += printCell(cell.Number, value)
+			if idx < 10 {
+				textRepresentation += " "
+			} else {
+				textRepresentation += printLockCell(value)
+			}
+		}
+	}
+	return textRepresentation
+}
+
+func (b *CompactBoard) Copy() Board {
+	copied := *b
+	return &copied
+}
+
+func (b *CompactBoard) IsMoveValid(move actions.Move) (ok bool, err error) {
+	if !b.hasRow(move.RowColor) {
+		return false, NewRuleViolation(CodeInvalidColor, "invalid move row color: %d", move.RowColor)
+	}
+	row := b.rows[move.RowColor]
+	if row&compactLocked != 0 {
+		return false, ErrRowLocked
+	}
+	idx, err := cellIndex(move.RowColor, move.CellNumber)
+	if err != nil {
+		return false, NewRuleViolation(CodeInvalidCellNumber, "%v", err)
+	}
+	cell := uint16(1) << idx
+	if row&cell != 0 {
+		return false, NewRuleViolation(CodeCellAlreadyCrossed, "cell %v is already crossed off", move.CellNumber)
+	}
+	if row&compactCells&^(cell<<1-1) != 0 {
+		return false, NewRuleViolation(CodeLeftOfCrossedCell, "cell %v is to the left of already crossed off cells", move.CellNumber)
+	}
+	if cell == compactLastCell && bits.OnesCount16(row&compactCells) < ruleset.Classic().CrossesToLock() {
+		return false, ErrNotEnoughForLock
+	}
+	return true, nil
+}
+
+func (b *CompactBoard) MakeMove(move actions.Move) error {
+	if ok, err := b.IsMoveValid(move); !ok {
+		return err
+	}
+	idx, _ := cellIndex(move.RowColor, move.CellNumber)
+	b.rows[move.RowColor] |= 1 << idx
+	return nil
+}
+
+func (b *CompactBoard) IsCellMarked(rowColor actions.RowColor, cellNumber int) bool {
+	return b.CrossCount(rowColor, cellNumber) > 0
+}
+
+func (b *CompactBoard) IsRowLocked(rowColor actions.RowColor) bool {
+	return b.hasRow(rowColor) && b.rows[rowColor]&compactLocked != 0
+}
+
+// LockRow locks the row of the given color so no further cells can be crossed off in it
+func (b *CompactBoard) LockRow(color actions.RowColor) {
+	if b.hasRow(color) {
+		b.rows[color] |= compactLocked
+	}
+}
+
+func (b *CompactBoard) CalculateScore() int {
+	score := 0
+	for _, rowColor := range rowColors {
+		score += b.RowScore(rowColor)
+	}
+	return score
+}
+
+func (b *CompactBoard) RowScore(rowColor actions.RowColor) int {
+	if !b.hasRow(rowColor) {
+		return 0
+	}
+	row := b.rows[rowColor]
+	crossOffCellCount := bits.OnesCount16(row & compactCells)
+	// crossing off the last cell crosses off the lock cell too
+	if row&compactLastCell != 0 {
+		crossOffCellCount++
+	}
+	return scoreTable[crossOffCellCount]
+}
+
+func (b *CompactBoard) Variant() Variant {
+	return VariantClassic
+}
+
+func (b *CompactBoard) Cells(rowColor actions.RowColor) []Cell {
+	return VariantClassic.Cells(rowColor)
+}
+
+func (b *CompactBoard) Ruleset() ruleset.Ruleset {
+	return ruleset.Classic()
+}
+
+func (b *CompactBoard) CrossCount(rowColor actions.RowColor, cellNumber int) int {
+	if !b.hasRow(rowColor) {
+		return 0
+	}
+	idx, err := cellIndex(rowColor, cellNumber)
+	if err != nil {
+		return 0
+	}
+	return int(b.rows[rowColor] >> idx & 1)
+}
+
+// Penalties is the number of boxes of the penalty track crossed off
+func (b *CompactBoard) Penalties() int {
+	penalties := 0
+	for _, row := range b.rows {
+		if row&compactPenalty != 0 {
+			penalties++
+		}
+	}
+	return penalties
+}
+
+// AddPenalty crosses off the next box of the penalty track, returning false if all four are already crossed off
+func (b *CompactBoard) AddPenalty() bool {
+	for idx := range b.rows {
+		if b.rows[idx]&compactPenalty == 0 {
+			b.rows[idx] |= compactPenalty
+			return true
+		}
+	}
+	return false
+}
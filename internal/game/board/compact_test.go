@@ -0,0 +1,225 @@
+package board
+
+import (
+	"math/rand"
+	"qwixx/internal/game/actions"
+	"qwixx/internal/game/ruleset"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// allMoves lists every move on the classic sheet, along with a few that are off the sheet
+func allMoves() []actions.Move {
+	var moves []actions.Move
+	for _, rowColor := range []actions.RowColor{
+		actions.RowColorRed, actions.RowColorYellow, actions.RowColorGreen, actions.RowColorBlue, actions.RowColorOrange,
+	} {
+		for cellNumber := 1; cellNumber <= 13; cellNumber++ {
+			moves = append(moves, actions.NewMove(rowColor, cellNumber))
+		}
+	}
+	return moves
+}
+
+// requireEquivalent checks the compact board looks and answers exactly like the other board
+func requireEquivalent(t *testing.T, expected Board, actual *CompactBoard) {
+	t.Helper()
+	require.Equal(t, expected.Print(), actual.Print())
+	require.Equal(t, expected.CalculateScore(), actual.CalculateScore())
+	require.Equal(t, StateOf(expected), StateOf(actual))
+	for _, rowColor := range actions.RowColors() {
+		require.Equal(t, expected.RowScore(rowColor), actual.RowScore(rowColor))
+		require.Equal(t, expected.IsRowLocked(rowColor), actual.IsRowLocked(rowColor))
+	}
+	for _, move := range allMoves() {
+		expectedOK, expectedErr := expected.IsMoveValid(move)
+		actualOK, actualErr := actual.IsMoveValid(move)
+		require.Equal(t, expectedOK, actualOK, "move %v", move)
+		require.Equal(t, expectedErr, actualErr, "move %v", move)
+		require.Equal(t, expected.IsCellMarked(move.RowColor, move.CellNumber), actual.IsCellMarked(move.RowColor, move.CellNumber))
+		require.Equal(t, expected.CrossCount(move.RowColor, move.CellNumber), actual.CrossCount(move.RowColor, move.CellNumber))
+	}
+}
+
+func TestCompactBoard_Equivalence(t *testing.T) {
+	moves := allMoves()
+	for seed := int64(0); seed < 50; seed++ {
+		rng := rand.New(rand.NewSource(seed))
+		expected := NewGameBoard()
+		actual := NewCompactBoard()
+		requireEquivalent(t, expected, actual)
+		for step := 0; step < 60; step++ {
+			if rng.Intn(20) == 0 {
+				rowColor := actions.RowColor(rng.Intn(4))
+				expected.LockRow(rowColor)
+				actual.LockRow(rowColor)
+			} else {
+				move := moves[rng.Intn(len(moves))]
+				require.Equal(t, expected.MakeMove(move), actual.MakeMove(move), "move %v", move)
+			}
+			requireEquivalent(t, expected, actual)
+		}
+	}
+
+	// random moves seldom cross off five cells of a row, so lock one by hand
+	expected := NewGameBoard()
+	actual := NewCompactBoard()
+	for _, cellNumber := range []int{12, 2, 3, 4, 5, 12, 6, 12, 7} {
+		move := actions.NewMove(actions.RowColorRed, cellNumber)
+		require.Equal(t, expected.MakeMove(move), actual.MakeMove(move), "move %v", move)
+		requireEquivalent(t, expected, actual)
+	}
+}
+
+func TestCompactBoard_Copy(t *testing.T) {
+	original := NewCompactBoard()
+	require.NoError(t, original.MakeMove(actions.NewMove(actions.RowColorRed, 4)))
+	copied := original.Copy()
+	require.NoError(t, copied.MakeMove(actions.NewMove(actions.RowColorRed, 5)))
+	copied.LockRow(actions.RowColorBlue)
+
+	require.False(t, original.IsCellMarked(actions.RowColorRed, 5))
+	require.False(t, original.IsRowLocked(actions.RowColorBlue))
+	require.True(t, copied.IsCellMarked(actions.RowColorRed, 4))
+}
+
+func TestCompactBoard_Penalties(t *testing.T) {
+	b := NewCompactBoard()
+	require.NoError(t, b.MakeMove(actions.NewMove(actions.RowColorGreen, 12)))
+	for penalty := 1; penalty <= 4; penalty++ {
+		require.True(t, b.AddPenalty())
+		require.Equal(t, penalty, b.Penalties())
+	}
+	require.False(t, b.AddPenalty())
+	require.Equal(t, 4, b.Penalties())
+
+	// the penalty bits do not show up as crossed off cells or locked rows
+	require.Equal(t, 1, b.CalculateScore())
+	require.Equal(t, NewGameBoard().Print()[:len("Red: [2| ]")], b.Print()[:len("Red: [2| ]")])
+	for _, rowColor := range rowColors {
+		require.False(t, b.IsRowLocked(rowColor))
+	}
+	ok, err := b.IsMoveValid(actions.NewMove(actions.RowColorRed, 12))
+	require.False(t, ok)
+	require.ErrorIs(t, err, ErrNotEnoughForLock)
+	require.Equal(t, 4, b.Copy().(*CompactBoard).Penalties())
+}
+
+func TestCompactBoardOf(t *testing.T) {
+	type testCase struct {
+		name          string
+		input         func(t *testing.T) Board
+		expectedError string
+	}
+	testCases := []testCase{
+		{
+			name: "classic board",
+			input: func(t *testing.T) Board {
+				b := NewGameBoard()
+				for _, cellNumber := range []int{2, 4, 5, 6, 8, 12} {
+					require.NoError(t, b.MakeMove(actions.NewMove(actions.RowColorYellow, cellNumber)))
+				}
+				require.NoError(t, b.MakeMove(actions.NewMove(actions.RowColorBlue, 9)))
+				b.LockRow(actions.RowColorYellow)
+				return b
+			},
+		},
+		{
+			name: "other sheet",
+			input: func(t *testing.T) Board {
+				b, err := NewBoard(VariantBigPoints)
+				require.NoError(t, err)
+				return b
+			},
+			expectedError: "compact boards only have the classic sheet",
+		},
+		{
+			name: "other rules",
+			input: func(t *testing.T) Board {
+				b, err := NewBoard(VariantClassic, WithRuleset(ruleset.Double()))
+				require.NoError(t, err)
+				return b
+			},
+			expectedError: "compact boards only follow the classic rules",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			input := tc.input(t)
+			compact, err := CompactBoardOf(input)
+			if tc.expectedError != "" {
+				require.ErrorContains(t, err, tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			requireEquivalent(t, input, compact)
+		})
+	}
+}
+
+// benchmarkBoard is a board in the middle of a game, with a few cells crossed off in every row
+func benchmarkBoard(b *testing.B, newBoard func() Board) Board {
+	b.Helper()
+	board := newBoard()
+	for _, move := range []actions.Move{
+		actions.NewMove(actions.RowColorRed, 3), actions.NewMove(actions.RowColorRed, 6),
+		actions.NewMove(actions.RowColorYellow, 5), actions.NewMove(actions.RowColorGreen, 11),
+		actions.NewMove(actions.RowColorGreen, 8), actions.NewMove(actions.RowColorBlue, 7),
+	} {
+		require.NoError(b, board.MakeMove(move))
+	}
+	return board
+}
+
+var benchmarkBoards = []struct {
+	name     string
+	newBoard func() Board
+}{
+	{name: "rows", newBoard: NewGameBoard},
+	{name: "compact", newBoard: func() Board { return NewCompactBoard() }},
+}
+
+func BenchmarkBoard_Copy(b *testing.B) {
+	for _, bb := range benchmarkBoards {
+		b.Run(bb.name, func(b *testing.B) {
+			board := benchmarkBoard(b, bb.newBoard)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				_ = board.Copy()
+			}
+		})
+	}
+}
+
+func BenchmarkBoard_IsMoveValid(b *testing.B) {
+	moves := allMoves()
+	for _, bb := range benchmarkBoards {
+		b.Run(bb.name, func(b *testing.B) {
+			board := benchmarkBoard(b, bb.newBoard)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				_, _ = board.IsMoveValid(moves[i%len(moves)])
+			}
+		})
+	}
+}
+
+// BenchmarkBoard_CopyAndMove copies the board and makes a move on the copy, as strategies do to weigh each option
+func BenchmarkBoard_CopyAndMove(b *testing.B) {
+	move := actions.NewMove(actions.RowColorYellow, 9)
+	for _, bb := range benchmarkBoards {
+		b.Run(bb.name, func(b *testing.B) {
+			board := benchmarkBoard(b, bb.newBoard)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				copied := board.Copy()
+				_ = copied.MakeMove(move)
+				_ = copied.CalculateScore()
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package board
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Code identifies the rule a move breaks, so that clients can react to or translate specific violations
+// without parsing error messages
+type Code string
+
+const (
+	// CodeCellAlreadyCrossed is the code of a move crossing off a cell that is already crossed off
+	CodeCellAlreadyCrossed Code = "cell_already_crossed"
+	// CodeLeftOfCrossedCell is the code of a move crossing off a cell to the left of a crossed off cell in its row
+	CodeLeftOfCrossedCell Code = "left_of_crossed_cell"
+	// CodeRowLocked is the code of a move in a locked row
+	CodeRowLocked Code = "row_locked"
+	// CodeNotEnoughForLock is the code of a move crossing off the rightmost cell of a row with fewer than five cells crossed off
+	CodeNotEnoughForLock Code = "not_enough_for_lock"
+	// CodeSumMismatch is the code of a move whose cell number is not a sum the dice allow
+	CodeSumMismatch Code = "sum_mismatch"
+	// CodeInvalidCellNumber is the code of a move with a cell number outside of 2-12
+	CodeInvalidCellNumber Code = "invalid_cell_number"
+	// CodeInvalidColor is the code of a move in a row color that does not exist
+	CodeInvalidColor Code = "invalid_color"
+	// CodeColorMoveWithoutColorDie is the code of a color dice move in a row whose die is not part of the roll
+	CodeColorMoveWithoutColorDie Code = "color_move_without_color_die"
+	// CodeBonusWithoutNeighbor is the code of a move in a bonus row of the Big Points sheet
+	// whose cell has no crossed off cell of the same number directly above or below it
+	CodeBonusWithoutNeighbor Code = "bonus_without_neighbor"
+	// CodeLockWithoutLastCell is the code of a board whose row is locked
+	// though neither its player nor another player crossed off the rightmost cell of that row
+	CodeLockWithoutLastCell Code = "lock_without_last_cell"
+	// CodeTooManyPenalties is the code of a player with more penalties than a game can go on for
+	CodeTooManyPenalties Code = "too_many_penalties"
+)
+
+// RuleViolation is the error for a move that breaks a rule of Qwixx.
+// Violations match the sentinel errors below with errors.Is by their code, whatever their message,
+// and errors.As gives access to the code of a violation wrapped in another error.
+type RuleViolation struct {
+	Code    Code   `json:"code"`
+	Message string `json:"message"`
+}
+
+var (
+	ErrCellAlreadyCrossed       = &RuleViolation{Code: CodeCellAlreadyCrossed, Message: "cell is already crossed off"}
+	ErrLeftOfCrossedCell        = &RuleViolation{Code: CodeLeftOfCrossedCell, Message: "cell is to the left of already crossed off cells"}
+	ErrRowLocked                = &RuleViolation{Code: CodeRowLocked, Message: "row is locked"}
+	ErrNotEnoughForLock         = &RuleViolation{Code: CodeNotEnoughForLock, Message: "cannot cross off rightmost cell of row unless 5 cells have been crossed off in that row"}
+	ErrSumMismatch              = &RuleViolation{Code: CodeSumMismatch, Message: "cell number does not match the dice"}
+	ErrInvalidCellNumber        = &RuleViolation{Code: CodeInvalidCellNumber, Message: "cell number must be between 2 and 12"}
+	ErrInvalidColor             = &RuleViolation{Code: CodeInvalidColor, Message: "invalid row color"}
+	ErrColorMoveWithoutColorDie = &RuleViolation{Code: CodeColorMoveWithoutColorDie, Message: "the die of that color was not rolled"}
+	ErrBonusWithoutNeighbor     = &RuleViolation{Code: CodeBonusWithoutNeighbor, Message: "bonus cell needs the cell above or below it crossed off"}
+	ErrLockWithoutLastCell      = &RuleViolation{Code: CodeLockWithoutLastCell, Message: "row is locked without its rightmost cell crossed off"}
+	ErrTooManyPenalties         = &RuleViolation{Code: CodeTooManyPenalties, Message: "more penalties than the game allows"}
+)
+
+// NewRuleViolation creates a violation of the rule with the given code, describing it with the formatted message
+func NewRuleViolation(code Code, format string, args ...any) *RuleViolation {
+	return &RuleViolation{Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+func (v *RuleViolation) Error() string {
+	return v.Message
+}
+
+// Is reports whether the target is a violation of the same rule
+func (v *RuleViolation) Is(target error) bool {
+	other, ok := target.(*RuleViolation)
+	return ok && other.Code == v.Code
+}
+
+// ViolationCode returns the code of the rule violation in the given error's chain, or an empty code if there is none
+func ViolationCode(err error) Code {
+	var violation *RuleViolation
+	if errors.As(err, &violation) {
+		return violation.Code
+	}
+	return ""
+}
+
+// ViolationOf describes the given error as a rule violation to send to players, keeping the code of the violation in its chain
+// along with the message of the whole error, which says more than the violation alone, like which move of a turn broke the rule
+func ViolationOf(err error) RuleViolation {
+	return RuleViolation{Code: ViolationCode(err), Message: err.Error()}
+}
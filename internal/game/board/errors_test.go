@@ -0,0 +1,87 @@
+package board
+
+import (
+	"errors"
+	"fmt"
+	"qwixx/internal/game/actions"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRuleViolation(t *testing.T) {
+	type testCase struct {
+		name         string
+		inputBoard   Board
+		inputMove    actions.Move
+		expectedErr  error
+		expectedCode Code
+	}
+	lockedBoard := NewGameBoard()
+	lockedBoard.LockRow(actions.RowColorYellow)
+	crossedBoard := NewGameBoard()
+	require.NoError(t, crossedBoard.MakeMove(actions.NewMove(actions.RowColorGreen, 9)))
+
+	testCases := []testCase{
+		{
+			name:         "cell already crossed off",
+			inputBoard:   crossedBoard,
+			inputMove:    actions.NewMove(actions.RowColorGreen, 9),
+			expectedErr:  ErrCellAlreadyCrossed,
+			expectedCode: CodeCellAlreadyCrossed,
+		},
+		{
+			name:         "cell left of a crossed off cell",
+			inputBoard:   crossedBoard,
+			inputMove:    actions.NewMove(actions.RowColorGreen, 11),
+			expectedErr:  ErrLeftOfCrossedCell,
+			expectedCode: CodeLeftOfCrossedCell,
+		},
+		{
+			name:         "locked row",
+			inputBoard:   lockedBoard,
+			inputMove:    actions.NewMove(actions.RowColorYellow, 2),
+			expectedErr:  ErrRowLocked,
+			expectedCode: CodeRowLocked,
+		},
+		{
+			name:         "rightmost cell too early",
+			inputBoard:   NewGameBoard(),
+			inputMove:    actions.NewMove(actions.RowColorRed, 12),
+			expectedErr:  ErrNotEnoughForLock,
+			expectedCode: CodeNotEnoughForLock,
+		},
+		{
+			name:         "cell number off the board",
+			inputBoard:   NewGameBoard(),
+			inputMove:    actions.NewMove(actions.RowColorBlue, 13),
+			expectedErr:  ErrInvalidCellNumber,
+			expectedCode: CodeInvalidCellNumber,
+		},
+		{
+			name:         "unknown row color",
+			inputBoard:   NewGameBoard(),
+			inputMove:    actions.NewMove(actions.RowColor(42), 5),
+			expectedErr:  ErrInvalidColor,
+			expectedCode: CodeInvalidColor,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ok, err := tc.inputBoard.IsMoveValid(tc.inputMove)
+			require.False(t, ok)
+			require.ErrorIs(t, err, tc.expectedErr)
+			require.Equal(t, tc.expectedCode, ViolationCode(err))
+
+			// the code survives wrapping, and MakeMove fails with the same violation
+			wrapped := fmt.Errorf("turn rejected: %w", tc.inputBoard.MakeMove(tc.inputMove))
+			require.ErrorIs(t, wrapped, tc.expectedErr)
+			var violation *RuleViolation
+			require.True(t, errors.As(wrapped, &violation))
+			require.Equal(t, tc.expectedCode, violation.Code)
+		})
+	}
+
+	require.NotErrorIs(t, ErrRowLocked, ErrCellAlreadyCrossed)
+	require.Empty(t, ViolationCode(errors.New("not a violation")))
+}
@@ -0,0 +1,169 @@
+package board
+
+import (
+	"errors"
+	"fmt"
+	"qwixx/internal/game/actions"
+	"qwixx/internal/game/ruleset"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// notationRow is the name of a row in the board notation
+type notationRow struct {
+	name     string
+	rowColor actions.RowColor
+}
+
+// notationRows lists the rows of the board notation in the order they are written in, from the top of the sheet down
+var notationRows = []notationRow{
+	{"R", actions.RowColorRed},
+	{"O", actions.RowColorOrange},
+	{"Y", actions.RowColorYellow},
+	{"G", actions.RowColorGreen},
+	{"Pu", actions.RowColorPurple},
+	{"B", actions.RowColorBlue},
+}
+
+const (
+	notationPenalties = "P"
+	notationSheet     = "sheet"
+	notationRules     = "rules"
+	notationLocked    = "L"
+	notationNoCells   = "-"
+)
+
+// Parse reads a board and the number of penalties of its player written in the board notation,
+// which writes them on a single line such as
+//
+//	R:2,3,5 Y:- G:12,10 B:12,11,9,7,6,2L P:1
+//
+// The notation is a list of fields separated by spaces, each a name and a value separated by a colon:
+//   - R, Y, G and B are the red, yellow, green and blue rows, O and Pu the orange and purple bonus rows of the Big Points sheet.
+//     Their value lists the crossed off cell numbers separated by commas, or is a dash for a row without crossed off cells.
+//     A cell crossed off more than once is listed once for each cross, and a row that is locked ends in an L.
+//   - P is the number of penalties taken.
+//   - sheet is the variant the board is laid out like and rules the ruleset it is played by.
+//
+// Rows left out have no crossed off cells, and penalties, sheet and rules left out are zero, the classic sheet and the classic rules.
+// The cells are crossed off following the usual rules, so a board that could not come about in a game is an error,
+// as are more penalties than end a game by the original rules. A locked row may have been locked by another player.
+func Parse(text string) (Board, int, error) {
+	state := State{Rows: map[actions.RowColor][]int{}}
+	penalties := 0
+	seen := map[string]bool{}
+	for _, field := range strings.Fields(text) {
+		name, value, ok := strings.Cut(field, ":")
+		if !ok {
+			return nil, 0, fmt.Errorf("field %q is not of the form name:value", field)
+		}
+		if seen[name] {
+			return nil, 0, fmt.Errorf("field %v is given more than once", name)
+		}
+		seen[name] = true
+
+		switch name {
+		case notationPenalties:
+			count, err := strconv.Atoi(value)
+			if err != nil || count < 0 {
+				return nil, 0, fmt.Errorf("penalties must be a number of at least 0, got %q", value)
+			}
+			penalties = count
+		case notationSheet:
+			variant, err := ParseVariant(value)
+			if err != nil {
+				return nil, 0, err
+			}
+			state.Variant = variant
+		case notationRules:
+			state.Ruleset = value
+		default:
+			idx := slices.IndexFunc(notationRows, func(row notationRow) bool {
+				return row.name == name
+			})
+			if idx < 0 {
+				return nil, 0, fmt.Errorf("unknown field %q, expected a row (R, Y, G, B, O, Pu), P, sheet or rules", name)
+			}
+			rowColor := notationRows[idx].rowColor
+			cells, locked, err := parseNotationRow(value)
+			if err != nil {
+				return nil, 0, fmt.Errorf("%v row: %w", rowColor, err)
+			}
+			state.Rows[rowColor] = cells
+			if locked {
+				state.Locked = append(state.Locked, rowColor)
+			}
+		}
+	}
+	if state.Variant == VariantClassic {
+		state.Variant = ""
+	}
+	if state.Ruleset == ruleset.NameClassic {
+		state.Ruleset = ""
+	}
+
+	b, err := FromState(state)
+	if err != nil {
+		return nil, 0, err
+	}
+	if err := Validate(state, penalties, WithLockedByOthers(state.Locked...)); err != nil {
+		return nil, 0, err
+	}
+	return b, penalties, nil
+}
+
+// parseNotationRow reads the value of a row field, the cell numbers it lists and whether the row is locked
+func parseNotationRow(value string) (cells []int, locked bool, err error) {
+	value, locked = strings.CutSuffix(value, notationLocked)
+	if value == notationNoCells || (locked && value == "") {
+		return []int{}, locked, nil
+	}
+	if value == "" {
+		return nil, false, errors.New("no cells listed, write - for a row without crossed off cells")
+	}
+	for _, cell := range strings.Split(value, ",") {
+		cellNumber, err := strconv.Atoi(cell)
+		if err != nil {
+			return nil, false, fmt.Errorf("%q is not a cell number", cell)
+		}
+		cells = append(cells, cellNumber)
+	}
+	return cells, locked, nil
+}
+
+// Format writes the given board and the number of penalties of its player in the board notation.
+// Every row of the board's sheet is written, with its cells from left to right, while the sheet and rules are only written
+// if they are not the classic ones, and the penalties only if there are any.
+func Format(b Board, penalties int) string {
+	state := StateOf(b)
+	var fields []string
+	if state.Variant != "" {
+		fields = append(fields, notationSheet+":"+string(state.Variant))
+	}
+	if state.Ruleset != "" {
+		fields = append(fields, notationRules+":"+state.Ruleset)
+	}
+	for _, row := range notationRows {
+		cells, ok := state.Rows[row.rowColor]
+		if !ok {
+			continue
+		}
+		value := notationNoCells
+		if len(cells) > 0 {
+			numbers := make([]string, 0, len(cells))
+			for _, cellNumber := range cells {
+				numbers = append(numbers, strconv.Itoa(cellNumber))
+			}
+			value = strings.Join(numbers, ",")
+		}
+		if slices.Contains(state.Locked, row.rowColor) {
+			value += notationLocked
+		}
+		fields = append(fields, row.name+":"+value)
+	}
+	if penalties > 0 {
+		fields = append(fields, notationPenalties+":"+strconv.Itoa(penalties))
+	}
+	return strings.Join(fields, " ")
+}
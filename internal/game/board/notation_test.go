@@ -0,0 +1,182 @@
+package board
+
+import (
+	"qwixx/internal/game/actions"
+	"qwixx/internal/game/ruleset"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// parseBoard reads a board written in the board notation, failing the test if it cannot
+func parseBoard(t *testing.T, text string) Board {
+	t.Helper()
+	b, _, err := Parse(text)
+	require.NoError(t, err)
+	return b
+}
+
+func TestParse(t *testing.T) {
+	type testCase struct {
+		name              string
+		input             string
+		expectedBoard     func(t *testing.T) Board
+		expectedPenalties int
+		expectedError     string
+	}
+	testCases := []testCase{
+		{
+			name:          "empty text is a new board",
+			input:         "",
+			expectedBoard: func(t *testing.T) Board { return NewGameBoard() },
+		},
+		{
+			name:  "every row, locked rows and penalties",
+			input: "R:2,3,5 Y:- G:12,10 B:12,11,9,7,6,2L P:1",
+			expectedBoard: func(t *testing.T) Board {
+				return &boardImpl{
+					redRow:    newRedRowFromCells([]int{1, 1, 0, 1, 0, 0, 0, 0, 0, 0, 0}, false),
+					yellowRow: NewYellowRow(),
+					greenRow:  newGreenRowFromCells([]int{1, 0, 1, 0, 0, 0, 0, 0, 0, 0, 0}, false),
+					blueRow:   newBlueRowFromCells([]int{1, 1, 0, 1, 0, 1, 1, 0, 0, 0, 1}, true),
+				}
+			},
+			expectedPenalties: 1,
+		},
+		{
+			name:  "rows left out, cells in any order and a locked row without crosses",
+			input: "  G:8,11  Y:-L ",
+			expectedBoard: func(t *testing.T) Board {
+				return &boardImpl{
+					redRow:    NewRedRow(),
+					yellowRow: newYellowRowFromCells([]int{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}, true),
+					greenRow:  newGreenRowFromCells([]int{0, 1, 0, 0, 1, 0, 0, 0, 0, 0, 0}, false),
+					blueRow:   NewBlueRow(),
+				}
+			},
+		},
+		{
+			name:  "sheet and rules",
+			input: "sheet:big-points rules:double R:4,4 O:4",
+			expectedBoard: func(t *testing.T) Board {
+				b, err := NewBoard(VariantBigPoints, WithRuleset(ruleset.Double()))
+				require.NoError(t, err)
+				require.NoError(t, b.MakeMove(actions.NewMove(actions.RowColorRed, 4)))
+				require.NoError(t, b.MakeMove(actions.NewMove(actions.RowColorRed, 4)))
+				require.NoError(t, b.MakeMove(actions.NewMove(actions.RowColorOrange, 4)))
+				return b
+			},
+		},
+		{
+			name:          "field without a value",
+			input:         "R:2 Y",
+			expectedError: `field "Y" is not of the form name:value`,
+		},
+		{
+			name:          "unknown field",
+			input:         "X:2",
+			expectedError: `unknown field "X"`,
+		},
+		{
+			name:          "field given twice",
+			input:         "R:2 R:3",
+			expectedError: "field R is given more than once",
+		},
+		{
+			name:          "row without cells or dash",
+			input:         "R:",
+			expectedError: "Red row: no cells listed",
+		},
+		{
+			name:          "cell that is not a number",
+			input:         "G:12,x",
+			expectedError: `Green row: "x" is not a cell number`,
+		},
+		{
+			name:          "cell that is not on the sheet",
+			input:         "B:13",
+			expectedError: "Blue row: invalid cell number: 13",
+		},
+		{
+			name:          "board that cannot come about in a game",
+			input:         "Y:2,12",
+			expectedError: "Yellow row: cannot cross off rightmost cell",
+		},
+		{
+			name:          "negative penalties",
+			input:         "P:-1",
+			expectedError: `penalties must be a number of at least 0, got "-1"`,
+		},
+		{
+			name:          "more penalties than end a game",
+			input:         "P:5",
+			expectedError: "5 penalties taken, but the game ends at 4",
+		},
+		{
+			name:          "bonus row on the classic sheet",
+			input:         "O:4",
+			expectedError: "invalid row color for the classic sheet",
+		},
+		{
+			name:          "unknown sheet",
+			input:         "sheet:nonsense",
+			expectedError: `unknown variant "nonsense"`,
+		},
+		{
+			name:          "unknown rules",
+			input:         "rules:nonsense",
+			expectedError: "nonsense",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			b, penalties, err := Parse(tc.input)
+			if tc.expectedError != "" {
+				require.ErrorContains(t, err, tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedBoard(t), b)
+			require.Equal(t, tc.expectedPenalties, penalties)
+		})
+	}
+}
+
+func TestFormat(t *testing.T) {
+	type testCase struct {
+		name      string
+		input     string
+		penalties int
+		expected  string
+	}
+	testCases := []testCase{
+		{
+			name:     "new board",
+			input:    "",
+			expected: "R:- Y:- G:- B:-",
+		},
+		{
+			name:      "cells from left to right, locked rows and penalties",
+			input:     "R:5,2,3 G:10,12 B:2,12,11,9,7,6L Y:L",
+			penalties: 1,
+			expected:  "R:2,3,5 Y:-L G:12,10 B:12,11,9,7,6,2L P:1",
+		},
+		{
+			name:     "sheet and rules",
+			input:    "rules:double sheet:big-points Pu:8 G:8,8",
+			expected: "sheet:big-points rules:double R:- O:- Y:- G:8,8 Pu:8 B:-",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			formatted := Format(parseBoard(t, tc.input), tc.penalties)
+			require.Equal(t, tc.expected, formatted)
+
+			// whatever is formatted parses back to the same board
+			parsed, penalties, err := Parse(formatted)
+			require.NoError(t, err)
+			require.Equal(t, parseBoard(t, tc.input), parsed)
+			require.Equal(t, tc.penalties, penalties)
+		})
+	}
+}
@@ -1,9 +1,12 @@
 package board
 
 import (
-	"errors"
 	"fmt"
+	"qwixx/internal/game/actions"
+	"qwixx/internal/game/ruleset"
+	"slices"
 	"strconv"
+	"strings"
 )
 
 // scoreTable represents the scores a row receives based on the number of crossed off cells at the end of the game
@@ -25,8 +28,8 @@ type Row interface {
 	// Copy copies this row into a new struct to prevent mutation of the original
 	Copy() Row
 
-	// IsMoveValid determines if the given move is valid for this row, returning the reason as an error if it was not valid
-	IsMoveValid(cellNumber int) (ok bool, reason string)
+	// IsMoveValid determines if the given move is valid for this row, returning the rule it breaks as a *RuleViolation if it is not valid
+	IsMoveValid(cellNumber int) (ok bool, err error)
 
 	// MakeMove attempts to cross off the given cell number in this row,
 	// mutating the row with the new state if the move is valid and returning an error if the move is invalid
@@ -35,10 +38,17 @@ type Row interface {
 	// IsCellMarked determines if the given cell number has been crossed off in this row
 	IsCellMarked(cellNumber int) bool
 
+	// CrossCount is how many times the given cell number has been crossed off in this row,
+	// which can be more than once under rulesets such as ruleset.Double
+	CrossCount(cellNumber int) int
+
 	// IsLocked determines if this row is locked.
 	// A row is locked for all players when any player has crossed off the rightmost cell in their row of that color.
 	// Further cells cannot be crossed off once a row is locked.
 	IsLocked() bool
+
+	// Lock locks this row, preventing any further cells from being crossed off
+	Lock()
 	// TODO there is a difference between a row that is locked, and a row that WAS LOCKED ON THIS BOARD
 	// the former just means the row cant be played on anymore, the latter influences the score of this row because you
 	// get to cross off an extra cell for the lock
@@ -56,15 +66,29 @@ const (
 
 type rowImpl struct {
 	rowType rowType
-	cells   []int
-	locked  bool
+	// numbers are the cell numbers from left to right of a row whose numbers are in a mixed order,
+	// and nil for the ascending and descending rows of the classic sheet numbered by rowType
+	numbers []int
+	// colors are the initials of the colors of the cells from left to right on a sheet with mixed colors,
+	// and empty when every cell has the color of the row
+	colors string
+	// rowColor and ruleset are the row's color and the rules it is crossed off by, a nil ruleset meaning the classic rules
+	rowColor actions.RowColor
+	ruleset  ruleset.Ruleset
+	// cells count the crosses of each cell from left to right
+	cells  []int
+	locked bool
 }
 
 func (r *rowImpl) Print() string {
 	var textRepresentation string
 	for idx, value := range r.cells {
-		cellNumber, _ := indexToCellNumber(r.rowType, idx)
-		textRepresentation += printCell(cellNumber, value)
+		cellNumber, _ := r.indexToCellNumber(idx)
+		if r.colors != "" {
+			textRepresentation += printColoredCell(r.colors[idx], cellNumber, value)
+		} else {
+			textRepresentation += printCell(cellNumber, value)
+		}
 		if idx < len(r.cells)-1 {
 			textRepresentation += " "
 		} else {
@@ -78,98 +102,169 @@ func (r *rowImpl) Copy() Row {
 	newCells := make([]int, len(r.cells))
 	copy(newCells, r.cells)
 	return &rowImpl{
-		rowType: r.rowType,
-		cells:   newCells,
-		locked:  r.locked,
+		rowType:  r.rowType,
+		numbers:  r.numbers,
+		colors:   r.colors,
+		rowColor: r.rowColor,
+		ruleset:  r.ruleset,
+		cells:    newCells,
+		locked:   r.locked,
 	}
 }
 
-func (r *rowImpl) IsMoveValid(cellNumber int) (ok bool, reason string) {
-	return isMoveValid(r.cells, r.rowType, r.locked, cellNumber)
+func (r *rowImpl) IsMoveValid(cellNumber int) (ok bool, err error) {
+	return isMoveValid(r.cells, r.locked, cellNumber, r.cellNumberToIndex, r.rules())
 }
 
 // MakeMove crosses off the given cell in this row, returning the new row
 func (r *rowImpl) MakeMove(cellNumber int) error {
-	ok, reason := isMoveValid(r.cells, r.rowType, r.locked, cellNumber)
-	if !ok {
-		return errors.New(reason)
+	if ok, err := isMoveValid(r.cells, r.locked, cellNumber, r.cellNumberToIndex, r.rules()); !ok {
+		return err
 	}
-	index, err := cellNumberToIndex(r.rowType, cellNumber)
+	index, err := r.cellNumberToIndex(cellNumber)
 	if err != nil {
 		return err
 	}
-	r.cells[index] = 1
+	r.cells[index]++
 	return nil
 }
 
 func (r *rowImpl) IsCellMarked(cellNumber int) bool {
-	index, err := cellNumberToIndex(r.rowType, cellNumber)
+	return r.CrossCount(cellNumber) > 0
+}
+
+func (r *rowImpl) CrossCount(cellNumber int) int {
+	index, err := r.cellNumberToIndex(cellNumber)
 	if err != nil {
-		return false
+		return 0
+	}
+	return r.cells[index]
+}
+
+// rowRules are the parts of a ruleset a row needs to validate a move
+type rowRules struct {
+	// maxCrosses is how many times the cell with the given number can be crossed off
+	maxCrosses    func(cellNumber int) int
+	crossesToLock int
+}
+
+// rules looks up the row's rules in its ruleset
+func (r *rowImpl) rules() rowRules {
+	rules := r.ruleset
+	if rules == nil {
+		rules = ruleset.Classic()
+	}
+	return rowRules{
+		maxCrosses: func(cellNumber int) int {
+			return rules.MaxCrosses(r.rowColor, cellNumber)
+		},
+		crossesToLock: rules.CrossesToLock(),
 	}
-	return r.cells[index] == 1
 }
 
 func (r *rowImpl) IsLocked() bool {
 	return r.locked
 }
 
+func (r *rowImpl) Lock() {
+	r.locked = true
+}
+
 func (r *rowImpl) CalculateScore() int {
 	// TODO include locked row? probably should add a twelfth cell
 	crossOffCellCount := 0
 	for _, value := range r.cells {
-		if value == 1 {
-			crossOffCellCount++
-		}
+		crossOffCellCount += value
 	}
 	// if the last cell is crossed off, that means this row was locked by this player so they also get to cross off the lock cell
-	if r.cells[10] == 1 {
+	if r.cells[len(r.cells)-1] > 0 {
 		crossOffCellCount++
 	}
+	if crossOffCellCount >= len(scoreTable) {
+		// cells crossed off twice can take a row past the end of the table, which keeps growing the same way
+		return crossOffCellCount * (crossOffCellCount + 1) / 2
+	}
 	return scoreTable[crossOffCellCount]
 }
 
-// isMoveValid determines if the cell of the given number for the given row and row type can be crossed off.
+// isMoveValid determines if the cell of the given number for the given row can be crossed off,
+// using toIndex to find the cell of that number and the given rules to know how often cells can be crossed off.
 // Cells can only be crossed off from left to right.
-// To cross off a cell in a row, the cell must be unoccupied and there must be no crossed off cells to its right.
-func isMoveValid(cells []int, rowType rowType, isLocked bool, cellNumber int) (ok bool, reason string) {
+// To cross off a cell in a row, the cell must not be crossed off as often as the rules allow,
+// and there must be no crossed off cells to its right.
+func isMoveValid(cells []int, isLocked bool, cellNumber int, toIndex func(cellNumber int) (int, error), rules rowRules) (ok bool, err error) {
 	if isLocked {
-		return false, "row is locked"
+		return false, ErrRowLocked
 	}
 
-	moveIndex, err := cellNumberToIndex(rowType, cellNumber)
+	moveIndex, err := toIndex(cellNumber)
 	if err != nil {
-		return false, err.Error()
+		return false, NewRuleViolation(CodeInvalidCellNumber, "%v", err)
 	}
 
 	// cell cannot be crossed off if it is already crossed off
 
-	if cells[moveIndex] == 1 {
-		return false, fmt.Sprintf("cell %v is already crossed off", cellNumber)
+	lockIndex := len(cells) - 1
+	maxCrosses := 1
+	if moveIndex != lockIndex {
+		maxCrosses = rules.maxCrosses(cellNumber)
+	}
+	if cells[moveIndex] >= maxCrosses {
+		if maxCrosses > 1 {
+			return false, NewRuleViolation(CodeCellAlreadyCrossed, "cell %v is already crossed off %v times", cellNumber, cells[moveIndex])
+		}
+		return false, NewRuleViolation(CodeCellAlreadyCrossed, "cell %v is already crossed off", cellNumber)
 	}
 
 	countCrossedOff := 0
 	countCrossedOffToRightOfIndex := 0
 	for idx, value := range cells {
-		if value == 1 {
-			countCrossedOff++
-			if idx > moveIndex {
-				countCrossedOffToRightOfIndex++
-			}
+		countCrossedOff += value
+		if value > 0 && idx > moveIndex {
+			countCrossedOffToRightOfIndex++
 		}
 	}
 
 	// cell cannot be crossed off if there are crossed off cells to its right
 	if countCrossedOffToRightOfIndex > 0 {
-		return false, fmt.Sprintf("cell %v is to the left of already crossed off cells", cellNumber)
+		return false, NewRuleViolation(CodeLeftOfCrossedCell, "cell %v is to the left of already crossed off cells", cellNumber)
 	}
 
-	// 5 other cells in row must be crossed off in order to cross off rightmost cell
-	if moveIndex == 10 && countCrossedOff < 5 {
-		return false, "cannot cross off rightmost cell of row unless 5 cells have been crossed off in that row"
+	// 5 other cells in row must be crossed off in order to cross off rightmost cell, or however many the rules ask for
+	if moveIndex == lockIndex && countCrossedOff < rules.crossesToLock {
+		if rules.crossesToLock != 5 {
+			return false, NewRuleViolation(
+				CodeNotEnoughForLock,
+				"cannot cross off rightmost cell of row unless %v cells have been crossed off in that row", rules.crossesToLock,
+			)
+		}
+		return false, ErrNotEnoughForLock
 	}
 
-	return true, ""
+	return true, nil
+}
+
+// indexToCellNumber turns the index of one of this row's cells into its cell number
+func (r *rowImpl) indexToCellNumber(index int) (int, error) {
+	if r.numbers == nil {
+		return indexToCellNumber(r.rowType, index)
+	}
+	if index < 0 || index >= len(r.numbers) {
+		return -1, fmt.Errorf("invalid index: %v. must be between 0 and %v", index, len(r.numbers)-1)
+	}
+	return r.numbers[index], nil
+}
+
+// cellNumberToIndex turns one of this row's cell numbers into the index of its cell
+func (r *rowImpl) cellNumberToIndex(cellNumber int) (int, error) {
+	if r.numbers == nil {
+		return cellNumberToIndex(r.rowType, cellNumber)
+	}
+	index := slices.Index(r.numbers, cellNumber)
+	if index < 0 {
+		return -1, fmt.Errorf("invalid cell number: %v. must be between 2 and 12", cellNumber)
+	}
+	return index, nil
 }
 
 // cellNumberToIndex turns a cell number into the index of a slice containing the value of the row's cells
@@ -211,15 +306,23 @@ func printCell(cellNumber int, value int) string {
 	return fmt.Sprintf("[%v|%v]", cellNumberText, valueAsText(value))
 }
 
+func printColoredCell(colorInitial byte, cellNumber int, value int) string {
+	return fmt.Sprintf("[%c%v|%v]", colorInitial, cellNumber, valueAsText(value))
+}
+
 func printLockCell(value int) string {
 	return fmt.Sprintf(" [L|%v]", valueAsText(value))
 }
 
 func valueAsText(value int) string {
-	if value == 1 {
+	switch {
+	case value == 1:
 		return "X"
+	case value > 1:
+		return strings.Repeat("X", value)
+	default:
+		return " "
 	}
-	return " "
 }
 
 func NewRedRow() Row {
@@ -256,3 +359,23 @@ func newDescendingRowFromCells(cells []int, locked bool) Row {
 func newRowFromCells(rowType rowType, cells []int, locked bool) Row {
 	return &rowImpl{rowType: rowType, cells: cells, locked: locked}
 }
+
+// newVariantRow creates an empty row of the given row type and color laid out like the given cells of a variant's sheet,
+// crossed off by the given rules
+func newVariantRow(rowType rowType, rowColor actions.RowColor, variant Variant, cells []Cell, rules ruleset.Ruleset) Row {
+	row := &rowImpl{rowType: rowType, rowColor: rowColor, ruleset: rules, cells: make([]int, len(cells))}
+	if variant == VariantMixxNumbers {
+		row.numbers = make([]int, 0, len(cells))
+		for _, cell := range cells {
+			row.numbers = append(row.numbers, cell.Number)
+		}
+	}
+	if variant.MixedColors() {
+		initials := make([]byte, 0, len(cells))
+		for _, cell := range cells {
+			initials = append(initials, cell.Color.String()[0])
+		}
+		row.colors = string(initials)
+	}
+	return row
+}
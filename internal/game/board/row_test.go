@@ -69,6 +69,7 @@ func TestIsMoveValid(t *testing.T) {
 		input           Row
 		inputCellNumber int
 		expectedReason  string
+		expectedCode    Code
 	}
 	validMoveCases := []testCase{
 		{
@@ -113,6 +114,7 @@ func TestIsMoveValid(t *testing.T) {
 			input:           newValidatedAscendingRowFromCells(t, []int{0, 0, 1, 0, 0, 0, 0, 0, 0, 0, 0}, false),
 			inputCellNumber: 4,
 			expectedReason:  "cell 4 is already crossed off",
+			expectedCode:    CodeCellAlreadyCrossed,
 		},
 
 		{
@@ -120,6 +122,7 @@ func TestIsMoveValid(t *testing.T) {
 			input:           newValidatedAscendingRowFromCells(t, []int{0, 0, 1, 1, 0, 1, 0, 0, 0, 0, 0}, false),
 			inputCellNumber: 6,
 			expectedReason:  "cell 6 is to the left of already crossed off cells",
+			expectedCode:    CodeLeftOfCrossedCell,
 		},
 
 		{
@@ -127,6 +130,7 @@ func TestIsMoveValid(t *testing.T) {
 			input:           newValidatedAscendingRowFromCells(t, []int{0, 1, 1, 0, 0, 0, 0, 0, 0, 0, 0}, false),
 			inputCellNumber: 12,
 			expectedReason:  "cannot cross off rightmost cell of row unless 5 cells have been crossed off in that row",
+			expectedCode:    CodeNotEnoughForLock,
 		},
 
 		{
@@ -134,6 +138,7 @@ func TestIsMoveValid(t *testing.T) {
 			input:           newValidatedDescendingRowFromCells(t, []int{0, 0, 1, 0, 0, 0, 0, 0, 0, 0, 0}, false),
 			inputCellNumber: 10,
 			expectedReason:  "cell 10 is already crossed off",
+			expectedCode:    CodeCellAlreadyCrossed,
 		},
 
 		{
@@ -141,6 +146,7 @@ func TestIsMoveValid(t *testing.T) {
 			input:           newValidatedDescendingRowFromCells(t, []int{0, 1, 1, 0, 0, 0, 0, 0, 0, 0, 0}, false),
 			inputCellNumber: 2,
 			expectedReason:  "cannot cross off rightmost cell of row unless 5 cells have been crossed off in that row",
+			expectedCode:    CodeNotEnoughForLock,
 		},
 
 		{
@@ -148,31 +154,35 @@ func TestIsMoveValid(t *testing.T) {
 			input:           newValidatedDescendingRowFromCells(t, []int{0, 0, 1, 1, 0, 1, 0, 0, 0, 0, 0}, false),
 			inputCellNumber: 8,
 			expectedReason:  "cell 8 is to the left of already crossed off cells",
+			expectedCode:    CodeLeftOfCrossedCell,
 		},
 		{
 			name:            "invalid move: locked non-empty row regardless of actual move",
 			input:           newValidatedAscendingRowFromCells(t, []int{0, 0, 1, 1, 0, 1, 0, 0, 0, 0, 0}, true),
 			inputCellNumber: 8,
 			expectedReason:  "row is locked",
+			expectedCode:    CodeRowLocked,
 		},
 		{
 			name:            "invalid move: locked empty row regardless of actual move",
 			input:           newValidatedDescendingRowFromCells(t, []int{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}, true),
 			inputCellNumber: 8,
 			expectedReason:  "row is locked",
+			expectedCode:    CodeRowLocked,
 		},
 	}
 	for _, tc := range validMoveCases {
 		t.Run(tc.name, func(t *testing.T) {
-			ok, reason := tc.input.IsMoveValid(tc.inputCellNumber)
-			require.Equal(t, "", reason)
+			ok, err := tc.input.IsMoveValid(tc.inputCellNumber)
+			require.NoError(t, err)
 			require.True(t, ok)
 		})
 	}
 	for _, tc := range invalidMoveCases {
 		t.Run(tc.name, func(t *testing.T) {
-			ok, reason := tc.input.IsMoveValid(tc.inputCellNumber)
-			require.Equal(t, tc.expectedReason, reason)
+			ok, err := tc.input.IsMoveValid(tc.inputCellNumber)
+			require.EqualError(t, err, tc.expectedReason)
+			require.Equal(t, tc.expectedCode, ViolationCode(err))
 			require.False(t, ok)
 		})
 	}
@@ -201,7 +211,7 @@ func TestMakeMove(t *testing.T) {
 			inputRow:        newValidatedAscendingRowFromCells(t, []int{0, 0, 1, 0, 0, 0, 0, 0, 0, 0, 0}, false),
 			inputCellNumber: 4,
 			expectedOk:      false,
-			expectedErr:     errors.New("cell 4 is already crossed off"),
+			expectedErr:     NewRuleViolation(CodeCellAlreadyCrossed, "cell 4 is already crossed off"),
 			expectedRow:     newValidatedAscendingRowFromCells(t, []int{0, 0, 1, 0, 0, 0, 0, 0, 0, 0, 0}, false),
 		},
 		{
@@ -217,7 +227,7 @@ func TestMakeMove(t *testing.T) {
 			inputRow:        newValidatedAscendingRowFromCells(t, []int{0, 0, 1, 1, 0, 1, 0, 0, 0, 0, 0}, false),
 			inputCellNumber: 6,
 			expectedOk:      false,
-			expectedErr:     errors.New("cell 6 is to the left of already crossed off cells"),
+			expectedErr:     NewRuleViolation(CodeLeftOfCrossedCell, "cell 6 is to the left of already crossed off cells"),
 			expectedRow:     newValidatedAscendingRowFromCells(t, []int{0, 0, 1, 1, 0, 1, 0, 0, 0, 0, 0}, false),
 		},
 		{
@@ -233,7 +243,7 @@ func TestMakeMove(t *testing.T) {
 			inputRow:        newValidatedDescendingRowFromCells(t, []int{0, 0, 1, 0, 0, 0, 0, 0, 0, 0, 0}, false),
 			inputCellNumber: 10,
 			expectedOk:      false,
-			expectedErr:     errors.New("cell 10 is already crossed off"),
+			expectedErr:     NewRuleViolation(CodeCellAlreadyCrossed, "cell 10 is already crossed off"),
 			expectedRow:     newValidatedDescendingRowFromCells(t, []int{0, 0, 1, 0, 0, 0, 0, 0, 0, 0, 0}, false),
 		},
 		{
@@ -249,7 +259,7 @@ func TestMakeMove(t *testing.T) {
 			inputRow:        newValidatedDescendingRowFromCells(t, []int{0, 0, 1, 1, 0, 1, 0, 0, 0, 0, 0}, false),
 			inputCellNumber: 8,
 			expectedOk:      false,
-			expectedErr:     errors.New("cell 8 is to the left of already crossed off cells"),
+			expectedErr:     NewRuleViolation(CodeLeftOfCrossedCell, "cell 8 is to the left of already crossed off cells"),
 			expectedRow:     newValidatedDescendingRowFromCells(t, []int{0, 0, 1, 1, 0, 1, 0, 0, 0, 0, 0}, false),
 		},
 		{
@@ -257,7 +267,7 @@ func TestMakeMove(t *testing.T) {
 			inputRow:        newValidatedAscendingRowFromCells(t, []int{0, 0, 1, 1, 0, 1, 0, 0, 0, 0, 0}, true),
 			inputCellNumber: 8,
 			expectedOk:      false,
-			expectedErr:     errors.New("row is locked"),
+			expectedErr:     NewRuleViolation(CodeRowLocked, "row is locked"),
 			expectedRow:     newValidatedAscendingRowFromCells(t, []int{0, 0, 1, 1, 0, 1, 0, 0, 0, 0, 0}, true),
 		},
 		{
@@ -265,7 +275,7 @@ func TestMakeMove(t *testing.T) {
 			inputRow:        newValidatedDescendingRowFromCells(t, []int{0, 0, 1, 1, 0, 1, 0, 0, 0, 0, 0}, true),
 			inputCellNumber: 6,
 			expectedOk:      false,
-			expectedErr:     errors.New("row is locked"),
+			expectedErr:     NewRuleViolation(CodeRowLocked, "row is locked"),
 			expectedRow:     newValidatedDescendingRowFromCells(t, []int{0, 0, 1, 1, 0, 1, 0, 0, 0, 0, 0}, true),
 		},
 	}
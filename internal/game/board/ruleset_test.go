@@ -0,0 +1,94 @@
+package board
+
+import (
+	"encoding/json"
+	"qwixx/internal/game/actions"
+	"qwixx/internal/game/ruleset"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewBoard_Double(t *testing.T) {
+	doubleBoard, err := NewBoard(VariantClassic, WithRuleset(ruleset.Double()))
+	require.NoError(t, err)
+	require.Equal(t, ruleset.NameDouble, doubleBoard.Ruleset().Name())
+
+	require.NoError(t, doubleBoard.MakeMove(actions.NewMove(actions.RowColorRed, 2)))
+	require.NoError(t, doubleBoard.MakeMove(actions.NewMove(actions.RowColorRed, 2)))
+	require.Equal(t, 2, doubleBoard.CrossCount(actions.RowColorRed, 2))
+	require.True(t, doubleBoard.IsCellMarked(actions.RowColorRed, 2))
+
+	ok, err := doubleBoard.IsMoveValid(actions.NewMove(actions.RowColorRed, 2))
+	require.False(t, ok)
+	require.ErrorIs(t, err, ErrCellAlreadyCrossed)
+	require.EqualError(t, err, "cell 2 is already crossed off 2 times")
+
+	// a cell can only be crossed off again while no cell to its right is crossed off
+	require.NoError(t, doubleBoard.MakeMove(actions.NewMove(actions.RowColorRed, 3)))
+	require.NoError(t, doubleBoard.MakeMove(actions.NewMove(actions.RowColorRed, 4)))
+	ok, err = doubleBoard.IsMoveValid(actions.NewMove(actions.RowColorRed, 3))
+	require.False(t, ok)
+	require.ErrorIs(t, err, ErrLeftOfCrossedCell)
+
+	// every cross counts towards locking the row, so five crosses in three cells are enough
+	ok, _ = doubleBoard.IsMoveValid(actions.NewMove(actions.RowColorRed, 12))
+	require.False(t, ok)
+	require.NoError(t, doubleBoard.MakeMove(actions.NewMove(actions.RowColorRed, 4)))
+	require.NoError(t, doubleBoard.MakeMove(actions.NewMove(actions.RowColorRed, 12)))
+	require.Equal(t, 28, doubleBoard.CalculateScore())
+	require.True(t, strings.HasPrefix(doubleBoard.Print(), "Red: [2|XX] [3|X] [4|XX]"))
+
+	state := StateOf(doubleBoard)
+	require.Equal(t, ruleset.NameDouble, state.Ruleset)
+	require.Equal(t, []int{2, 2, 3, 4, 4, 12}, state.Rows[actions.RowColorRed])
+	encoded, err := json.Marshal(state)
+	require.NoError(t, err)
+	var decoded State
+	require.NoError(t, json.Unmarshal(encoded, &decoded))
+	rebuilt, err := FromState(decoded)
+	require.NoError(t, err)
+	require.Equal(t, doubleBoard, rebuilt)
+
+	// the classic rules still allow a single cross, and classic states leave the ruleset out
+	_, err = FromState(State{Rows: map[actions.RowColor][]int{actions.RowColorRed: {2, 2}}})
+	require.ErrorIs(t, err, ErrCellAlreadyCrossed)
+	encoded, err = json.Marshal(StateOf(NewGameBoard()))
+	require.NoError(t, err)
+	require.NotContains(t, string(encoded), "ruleset")
+	_, err = FromState(State{Ruleset: "triple"})
+	require.Error(t, err)
+}
+
+func TestNewBoard_Connected(t *testing.T) {
+	connectedBoard, err := NewBoard(VariantClassic, WithRuleset(ruleset.Connected()))
+	require.NoError(t, err)
+
+	// red 4 is chained to yellow 4
+	require.NoError(t, connectedBoard.MakeMove(actions.NewMove(actions.RowColorRed, 4)))
+	require.True(t, connectedBoard.IsCellMarked(actions.RowColorYellow, 4))
+
+	// the chained cell is skipped when the usual rules do not allow crossing it off
+	require.NoError(t, connectedBoard.MakeMove(actions.NewMove(actions.RowColorYellow, 10)))
+	require.NoError(t, connectedBoard.MakeMove(actions.NewMove(actions.RowColorRed, 9)))
+	require.False(t, connectedBoard.IsCellMarked(actions.RowColorYellow, 9))
+
+	// chains work both ways, here from green 10 down to blue 10, and chained cells score like any other
+	require.NoError(t, connectedBoard.MakeMove(actions.NewMove(actions.RowColorGreen, 10)))
+	require.True(t, connectedBoard.IsCellMarked(actions.RowColorBlue, 10))
+	require.Equal(t, 8, connectedBoard.CalculateScore())
+
+	copied := connectedBoard.Copy()
+	require.Equal(t, ruleset.NameConnected, copied.Ruleset().Name())
+
+	// rebuilding a board from its state does not cross off the chained cells again
+	state := StateOf(connectedBoard)
+	require.Equal(t, ruleset.NameConnected, state.Ruleset)
+	rebuilt, err := FromState(State{Ruleset: ruleset.NameConnected, Rows: map[actions.RowColor][]int{actions.RowColorRed: {4}}})
+	require.NoError(t, err)
+	require.False(t, rebuilt.IsCellMarked(actions.RowColorYellow, 4))
+	rebuilt, err = FromState(state)
+	require.NoError(t, err)
+	require.Equal(t, connectedBoard, rebuilt)
+}
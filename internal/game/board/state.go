@@ -0,0 +1,128 @@
+package board
+
+import (
+	"fmt"
+	"qwixx/internal/game/actions"
+	"qwixx/internal/game/ruleset"
+	"slices"
+)
+
+// State is a plain, serializable description of a board, used to send boards over the wire.
+// Rows maps each row color to the cell numbers crossed off in that row, in the order they appear from left to right,
+// with a cell crossed off more than once listed once for each cross.
+// Variant and Ruleset are left out for boards laid out like the classic sheet and played by the classic rules.
+type State struct {
+	Variant Variant                    `json:"variant,omitempty"`
+	Ruleset string                     `json:"ruleset,omitempty"`
+	Rows    map[actions.RowColor][]int `json:"rows"`
+	Locked  []actions.RowColor         `json:"locked,omitempty"`
+}
+
+var rowColors = []actions.RowColor{
+	actions.RowColorRed,
+	actions.RowColorYellow,
+	actions.RowColorGreen,
+	actions.RowColorBlue,
+}
+
+// rowCellNumbers lists the cell numbers of the row with the given color from left to right
+func rowCellNumbers(rowColor actions.RowColor) []int {
+	rowType := rowTypeOf(rowColor)
+	cellNumbers := make([]int, 0, 11)
+	for idx := 0; idx < 11; idx++ {
+		cellNumber, _ := indexToCellNumber(rowType, idx)
+		cellNumbers = append(cellNumbers, cellNumber)
+	}
+	return cellNumbers
+}
+
+// rowTypeOf returns whether the row with the given color is numbered in ascending or descending order.
+// Bonus rows are numbered like the rows around them.
+func rowTypeOf(rowColor actions.RowColor) rowType {
+	switch rowColor {
+	case actions.RowColorGreen, actions.RowColorBlue, actions.RowColorPurple:
+		return RowTypeDescending
+	default:
+		return RowTypeAscending
+	}
+}
+
+// StateOf captures the current state of the given board
+func StateOf(b Board) State {
+	state := State{Rows: make(map[actions.RowColor][]int, len(b.Variant().RowColors()))}
+	if b.Variant() != VariantClassic {
+		state.Variant = b.Variant()
+	}
+	if b.Ruleset().Name() != ruleset.NameClassic {
+		state.Ruleset = b.Ruleset().Name()
+	}
+	for _, rowColor := range b.Variant().RowColors() {
+		marked := []int{}
+		for _, cell := range b.Cells(rowColor) {
+			for cross := 0; cross < b.CrossCount(rowColor, cell.Number); cross++ {
+				marked = append(marked, cell.Number)
+			}
+		}
+		state.Rows[rowColor] = marked
+		if b.IsRowLocked(rowColor) {
+			state.Locked = append(state.Locked, rowColor)
+		}
+	}
+	return state
+}
+
+// FromState builds a board matching the given state.
+// The state is checked with Validate first, returning every invariant it breaks if it is not reachable.
+// Since a state carries no penalties or opponents, rows may be locked without their rightmost cell crossed off,
+// as another player may have locked them.
+// Cells are then crossed off from left to right so the usual move rules apply.
+// Bonus rows are filled in last, once the rows around them are,
+// and the cells the ruleset links are not crossed off along with them since the state already lists them.
+func FromState(state State) (Board, error) {
+	if err := Validate(state, 0, WithLockedByOthers(state.Locked...)); err != nil {
+		return nil, err
+	}
+	rules, err := ruleset.New(state.Ruleset)
+	if err != nil {
+		return nil, err
+	}
+	created, err := NewBoard(state.Variant, WithRuleset(rules))
+	if err != nil {
+		return nil, err
+	}
+	b := created.(*boardImpl)
+	rowOrder := b.Variant().RowColors()
+	slices.SortStableFunc(rowOrder, func(x, y actions.RowColor) int {
+		return boolToInt(b.Variant().IsBonusRow(x)) - boolToInt(b.Variant().IsBonusRow(y))
+	})
+	for _, rowColor := range rowOrder {
+		marked, ok := state.Rows[rowColor]
+		if !ok {
+			continue
+		}
+		cellNumbers := make([]int, 0, len(b.Cells(rowColor)))
+		for _, cell := range b.Cells(rowColor) {
+			cellNumbers = append(cellNumbers, cell.Number)
+		}
+		ordered := slices.Clone(marked)
+		slices.SortFunc(ordered, func(a, b int) int {
+			return slices.Index(cellNumbers, a) - slices.Index(cellNumbers, b)
+		})
+		for _, cellNumber := range ordered {
+			if err := b.makeMove(actions.NewMove(rowColor, cellNumber)); err != nil {
+				return nil, fmt.Errorf("%v row: %w", rowColor, err)
+			}
+		}
+	}
+	for _, rowColor := range state.Locked {
+		b.LockRow(rowColor)
+	}
+	return b, nil
+}
+
+func boolToInt(value bool) int {
+	if value {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,102 @@
+package board
+
+import (
+	"encoding/json"
+	"qwixx/internal/game/actions"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStateOf(t *testing.T) {
+	b := &boardImpl{
+		redRow:    newRedRowFromCells([]int{1, 1, 0, 1, 0, 0, 0, 0, 0, 0, 0}, false),
+		yellowRow: newYellowRowFromCells([]int{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}, true),
+		greenRow:  newGreenRowFromCells([]int{0, 0, 0, 0, 1, 1, 1, 0, 0, 0, 0}, false),
+		blueRow:   newBlueRowFromCells([]int{1, 1, 1, 1, 1, 0, 0, 0, 0, 0, 1}, true),
+	}
+	expectedState := State{
+		Rows: map[actions.RowColor][]int{
+			actions.RowColorRed:    {2, 3, 5},
+			actions.RowColorYellow: {},
+			actions.RowColorGreen:  {8, 7, 6},
+			actions.RowColorBlue:   {12, 11, 10, 9, 8, 2},
+		},
+		Locked: []actions.RowColor{actions.RowColorYellow, actions.RowColorBlue},
+	}
+	require.Equal(t, expectedState, StateOf(b))
+}
+
+func TestFromState(t *testing.T) {
+	type testCase struct {
+		name          string
+		input         State
+		expectedBoard Board
+		expectedError bool
+	}
+	testCases := []testCase{
+		{
+			name:          "empty state is a new board",
+			input:         State{},
+			expectedBoard: NewGameBoard(),
+		},
+		{
+			name: "cells are crossed off regardless of the order they are listed in",
+			input: State{
+				Rows: map[actions.RowColor][]int{
+					actions.RowColorRed:  {5, 2, 3},
+					actions.RowColorBlue: {2, 12, 11, 10, 9, 8},
+				},
+				Locked: []actions.RowColor{actions.RowColorBlue},
+			},
+			expectedBoard: &boardImpl{
+				redRow:    newRedRowFromCells([]int{1, 1, 0, 1, 0, 0, 0, 0, 0, 0, 0}, false),
+				yellowRow: NewYellowRow(),
+				greenRow:  NewGreenRow(),
+				blueRow:   newBlueRowFromCells([]int{1, 1, 1, 1, 1, 0, 0, 0, 0, 0, 1}, true),
+			},
+		},
+		{
+			name: "invalid cell number is an error",
+			input: State{
+				Rows: map[actions.RowColor][]int{actions.RowColorRed: {13}},
+			},
+			expectedError: true,
+		},
+		{
+			name: "rightmost cell without five others is an error",
+			input: State{
+				Rows: map[actions.RowColor][]int{actions.RowColorYellow: {2, 12}},
+			},
+			expectedError: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			b, err := FromState(tc.input)
+			if tc.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedBoard, b)
+		})
+	}
+}
+
+func TestStateJSONRoundTrip(t *testing.T) {
+	b := NewGameBoard()
+	require.NoError(t, b.MakeMove(actions.NewMove(actions.RowColorGreen, 11)))
+	require.NoError(t, b.MakeMove(actions.NewMove(actions.RowColorYellow, 4)))
+	b.LockRow(actions.RowColorRed)
+
+	encoded, err := json.Marshal(StateOf(b))
+	require.NoError(t, err)
+	require.JSONEq(t, `{"rows":{"Red":[],"Yellow":[4],"Green":[11],"Blue":[]},"locked":["Red"]}`, string(encoded))
+
+	var decoded State
+	require.NoError(t, json.Unmarshal(encoded, &decoded))
+	decodedBoard, err := FromState(decoded)
+	require.NoError(t, err)
+	require.Equal(t, b, decodedBoard)
+}
@@ -25,12 +25,7 @@ func TestApplyActivePlayerTurn(t *testing.T) {
 			inputTurn: actions.ActivePlayerTurn{
 				WhiteDiceMove: &actions.Move{RowColor: actions.RowColorRed, CellNumber: 5},
 			},
-			expectedBoard: &boardImpl{
-				redRow:    newRedRowFromCells([]int{0, 0, 0, 1, 0, 0, 0, 0, 0, 0, 0}, false),
-				yellowRow: newYellowRowFromCells([]int{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}, false),
-				greenRow:  newGreenRowFromCells([]int{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}, false),
-				blueRow:   newBlueRowFromCells([]int{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}, false),
-			},
+			expectedBoard: parseBoard(t, "R:5"),
 		},
 		{
 			name:       "Apply color dice move only",
@@ -38,12 +33,7 @@ func TestApplyActivePlayerTurn(t *testing.T) {
 			inputTurn: actions.ActivePlayerTurn{
 				ColorDiceMove: &actions.Move{RowColor: actions.RowColorBlue, CellNumber: 8},
 			},
-			expectedBoard: &boardImpl{
-				redRow:    newRedRowFromCells([]int{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}, false),
-				yellowRow: newYellowRowFromCells([]int{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}, false),
-				greenRow:  newGreenRowFromCells([]int{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}, false),
-				blueRow:   newBlueRowFromCells([]int{0, 0, 0, 0, 1, 0, 0, 0, 0, 0, 0}, false),
-			},
+			expectedBoard: parseBoard(t, "B:8"),
 		},
 		{
 			name:       "Apply both white and color dice moves",
@@ -52,12 +42,7 @@ func TestApplyActivePlayerTurn(t *testing.T) {
 				WhiteDiceMove: &actions.Move{RowColor: actions.RowColorYellow, CellNumber: 7},
 				ColorDiceMove: &actions.Move{RowColor: actions.RowColorGreen, CellNumber: 10},
 			},
-			expectedBoard: &boardImpl{
-				redRow:    newRedRowFromCells([]int{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}, false),
-				yellowRow: newYellowRowFromCells([]int{0, 0, 0, 0, 0, 1, 0, 0, 0, 0, 0}, false),
-				greenRow:  newGreenRowFromCells([]int{0, 0, 1, 0, 0, 0, 0, 0, 0, 0, 0}, false),
-				blueRow:   newBlueRowFromCells([]int{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}, false),
-			},
+			expectedBoard: parseBoard(t, "Y:7 G:10"),
 		},
 		{
 			name: "Error on invalid white dice move",
@@ -0,0 +1,155 @@
+package board
+
+import (
+	"errors"
+	"fmt"
+	"qwixx/internal/game/actions"
+	"qwixx/internal/game/ruleset"
+	"slices"
+)
+
+// defaultMaxPenalties is the number of penalties that ends a game by the original rules, so no player takes more
+const defaultMaxPenalties = 4
+
+// validation is what Validate accepts beyond what a board can come to by itself
+type validation struct {
+	maxPenalties   int
+	lockedByOthers []actions.RowColor
+}
+
+// ValidationOption changes what Validate accepts
+type ValidationOption func(v *validation)
+
+// WithMaxPenalties accepts up to the given number of penalties instead of four, for house rules that end the game later
+func WithMaxPenalties(maxPenalties int) ValidationOption {
+	return func(v *validation) {
+		v.maxPenalties = maxPenalties
+	}
+}
+
+// WithLockedByOthers accepts the rows of the given colors being locked without their rightmost cell crossed off,
+// as they are when another player locked them
+func WithLockedByOthers(rowColors ...actions.RowColor) ValidationOption {
+	return func(v *validation) {
+		v.lockedByOthers = append(v.lockedByOthers, rowColors...)
+	}
+}
+
+// Validate checks the given state, and the number of penalties of its player, could have come about in a game,
+// returning every invariant it breaks joined together, or nil if it breaks none. The invariants are that
+//   - the sheet and the ruleset exist, and every row is on the sheet,
+//   - every crossed off cell is on its row and crossed off no more often than the ruleset allows,
+//   - the rightmost cell of a row is only crossed off once enough other cells of the row are,
+//   - a cell of a bonus row is only crossed off if the cell of the same number above or below it is,
+//   - a locked row has its rightmost cell crossed off, unless another player locked it,
+//   - the player has taken no more penalties than it takes to end the game.
+//
+// Each broken invariant of the board is a RuleViolation with its own code.
+func Validate(state State, penalties int, options ...ValidationOption) error {
+	v := validation{maxPenalties: defaultMaxPenalties}
+	for _, option := range options {
+		option(&v)
+	}
+
+	variant := state.Variant
+	if variant == "" {
+		variant = VariantClassic
+	}
+	if !slices.Contains(Variants(), variant) {
+		return fmt.Errorf("unknown variant %q", state.Variant)
+	}
+	rules, err := ruleset.New(state.Ruleset)
+	if err != nil {
+		return err
+	}
+
+	var violations []error
+	rowColorsOfState := slices.Clone(state.Locked)
+	for rowColor := range state.Rows {
+		rowColorsOfState = append(rowColorsOfState, rowColor)
+	}
+	slices.Sort(rowColorsOfState)
+	for _, rowColor := range slices.Compact(rowColorsOfState) {
+		if !slices.Contains(variant.RowColors(), rowColor) {
+			violations = append(violations, NewRuleViolation(CodeInvalidColor, "invalid row color for the %v sheet: %d", variant, rowColor))
+		}
+	}
+	for _, rowColor := range variant.RowColors() {
+		violations = append(violations, validateRow(state, variant, rules, rowColor, v)...)
+	}
+
+	if penalties < 0 {
+		violations = append(violations, fmt.Errorf("penalties must not be negative, got %v", penalties))
+	}
+	if penalties > v.maxPenalties {
+		violations = append(violations, NewRuleViolation(
+			CodeTooManyPenalties, "%v penalties taken, but the game ends at %v", penalties, v.maxPenalties,
+		))
+	}
+	return errors.Join(violations...)
+}
+
+// validateRow returns the invariants the row with the given color of the given state breaks
+func validateRow(state State, variant Variant, rules ruleset.Ruleset, rowColor actions.RowColor, v validation) []error {
+	var violations []error
+	cells := variant.Cells(rowColor)
+	cellNumbers := make([]int, 0, len(cells))
+	for _, cell := range cells {
+		cellNumbers = append(cellNumbers, cell.Number)
+	}
+	lastCellNumber := cellNumbers[len(cellNumbers)-1]
+
+	crosses := map[int]int{}
+	crossCount := 0
+	for _, cellNumber := range state.Rows[rowColor] {
+		if !slices.Contains(cellNumbers, cellNumber) {
+			violations = append(violations, NewRuleViolation(CodeInvalidCellNumber, "%v row: invalid cell number: %v", rowColor, cellNumber))
+			continue
+		}
+		crosses[cellNumber]++
+		crossCount++
+	}
+
+	for _, cellNumber := range cellNumbers {
+		count := crosses[cellNumber]
+		if count == 0 {
+			continue
+		}
+		maxCrosses := 1
+		if cellNumber != lastCellNumber {
+			maxCrosses = rules.MaxCrosses(rowColor, cellNumber)
+		}
+		if count > maxCrosses {
+			violations = append(violations, NewRuleViolation(
+				CodeCellAlreadyCrossed, "%v row: cell %v is crossed off %v times, but at most %v are allowed", rowColor, cellNumber, count, maxCrosses,
+			))
+		}
+		if variant.IsBonusRow(rowColor) {
+			neighbors := variant.Neighbors(rowColor)
+			if !slices.ContainsFunc(neighbors, func(neighbor actions.RowColor) bool {
+				return slices.Contains(state.Rows[neighbor], cellNumber)
+			}) {
+				violations = append(violations, NewRuleViolation(
+					CodeBonusWithoutNeighbor,
+					"%v row: cell %v of the bonus row needs cell %v of the %v or %v row crossed off",
+					rowColor, cellNumber, cellNumber, neighbors[0], neighbors[len(neighbors)-1],
+				))
+			}
+		}
+	}
+
+	lastCrossed := crosses[lastCellNumber] > 0
+	if others := crossCount - crosses[lastCellNumber]; lastCrossed && others < rules.CrossesToLock() {
+		violations = append(violations, NewRuleViolation(
+			CodeNotEnoughForLock,
+			"%v row: cannot cross off rightmost cell %v unless %v cells have been crossed off in that row, but only %v are",
+			rowColor, lastCellNumber, rules.CrossesToLock(), others,
+		))
+	}
+	if slices.Contains(state.Locked, rowColor) && !lastCrossed && !slices.Contains(v.lockedByOthers, rowColor) {
+		violations = append(violations, NewRuleViolation(
+			CodeLockWithoutLastCell, "%v row is locked, but its rightmost cell %v is not crossed off", rowColor, lastCellNumber,
+		))
+	}
+	return violations
+}
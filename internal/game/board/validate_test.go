@@ -0,0 +1,156 @@
+package board
+
+import (
+	"errors"
+	"qwixx/internal/game/actions"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	type testCase struct {
+		name           string
+		input          State
+		inputPenalties int
+		inputOptions   []ValidationOption
+		expectedCodes  []Code
+		expectedError  string
+	}
+	testCases := []testCase{
+		{
+			name: "reachable board",
+			input: State{
+				Rows: map[actions.RowColor][]int{
+					actions.RowColorRed:  {2, 3, 5},
+					actions.RowColorBlue: {12, 11, 9, 7, 6, 2},
+				},
+				Locked: []actions.RowColor{actions.RowColorBlue},
+			},
+			inputPenalties: 4,
+		},
+		{
+			name: "rightmost cell crossed with fewer than five others",
+			input: State{
+				Rows: map[actions.RowColor][]int{actions.RowColorYellow: {2, 3, 12}},
+			},
+			expectedCodes: []Code{CodeNotEnoughForLock},
+			expectedError: "Yellow row: cannot cross off rightmost cell 12 unless 5 cells have been crossed off in that row, but only 2 are",
+		},
+		{
+			name: "lock without the rightmost cell crossed",
+			input: State{
+				Rows:   map[actions.RowColor][]int{actions.RowColorGreen: {12, 11, 10, 9, 8}},
+				Locked: []actions.RowColor{actions.RowColorGreen},
+			},
+			expectedCodes: []Code{CodeLockWithoutLastCell},
+			expectedError: "Green row is locked, but its rightmost cell 2 is not crossed off",
+		},
+		{
+			name: "lock of another player",
+			input: State{
+				Rows:   map[actions.RowColor][]int{actions.RowColorGreen: {12, 11}},
+				Locked: []actions.RowColor{actions.RowColorGreen},
+			},
+			inputOptions: []ValidationOption{WithLockedByOthers(actions.RowColorGreen)},
+		},
+		{
+			name:           "more than four penalties",
+			input:          State{},
+			inputPenalties: 5,
+			expectedCodes:  []Code{CodeTooManyPenalties},
+			expectedError:  "5 penalties taken, but the game ends at 4",
+		},
+		{
+			name:           "penalties of house rules",
+			input:          State{},
+			inputPenalties: 5,
+			inputOptions:   []ValidationOption{WithMaxPenalties(6)},
+		},
+		{
+			name:           "negative penalties",
+			input:          State{},
+			inputPenalties: -1,
+			expectedError:  "penalties must not be negative, got -1",
+		},
+		{
+			name: "every violation is returned",
+			input: State{
+				Rows: map[actions.RowColor][]int{
+					actions.RowColorRed:    {2, 2, 13},
+					actions.RowColorYellow: {12},
+					actions.RowColorOrange: {4},
+				},
+				Locked: []actions.RowColor{actions.RowColorBlue},
+			},
+			inputPenalties: 5,
+			expectedCodes: []Code{
+				CodeInvalidColor, CodeInvalidCellNumber, CodeCellAlreadyCrossed, CodeNotEnoughForLock,
+				CodeLockWithoutLastCell, CodeTooManyPenalties,
+			},
+		},
+		{
+			name: "cells crossed off twice by the rules",
+			input: State{
+				Ruleset: "double",
+				Rows:    map[actions.RowColor][]int{actions.RowColorRed: {2, 2, 3, 3, 3}},
+			},
+			expectedCodes: []Code{CodeCellAlreadyCrossed},
+			expectedError: "Red row: cell 3 is crossed off 3 times, but at most 2 are allowed",
+		},
+		{
+			name: "bonus cell without a neighbor",
+			input: State{
+				Variant: VariantBigPoints,
+				Rows: map[actions.RowColor][]int{
+					actions.RowColorRed:    {4},
+					actions.RowColorOrange: {4, 5},
+				},
+			},
+			expectedCodes: []Code{CodeBonusWithoutNeighbor},
+			expectedError: "Orange row: cell 5 of the bonus row needs cell 5 of the Red or Yellow row crossed off",
+		},
+		{
+			name:          "unknown sheet",
+			input:         State{Variant: "qwinto"},
+			expectedError: `unknown variant "qwinto"`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := Validate(tc.input, tc.inputPenalties, tc.inputOptions...)
+			if len(tc.expectedCodes) == 0 && tc.expectedError == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			if tc.expectedError != "" {
+				require.ErrorContains(t, err, tc.expectedError)
+			}
+			var codes []Code
+			if joined, ok := err.(interface{ Unwrap() []error }); ok {
+				for _, violation := range joined.Unwrap() {
+					var ruleViolation *RuleViolation
+					if errors.As(violation, &ruleViolation) {
+						codes = append(codes, ruleViolation.Code)
+					}
+				}
+			}
+			require.Equal(t, tc.expectedCodes, codes)
+		})
+	}
+}
+
+func TestFromState_ReturnsEveryViolation(t *testing.T) {
+	_, err := FromState(State{
+		Rows: map[actions.RowColor][]int{
+			actions.RowColorRed:  {13},
+			actions.RowColorBlue: {12, 2},
+		},
+		Locked: []actions.RowColor{actions.RowColorGreen},
+	})
+	require.ErrorIs(t, err, ErrInvalidCellNumber)
+	require.ErrorIs(t, err, ErrNotEnoughForLock)
+	// the green row may have been locked by another player
+	require.NotErrorIs(t, err, ErrLockWithoutLastCell)
+}
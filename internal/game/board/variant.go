@@ -0,0 +1,159 @@
+package board
+
+import (
+	"fmt"
+	"qwixx/internal/game/actions"
+	"slices"
+	"strings"
+)
+
+// Variant is the layout of the score sheet a game is played on
+type Variant string
+
+const (
+	// VariantClassic is the original sheet, where every row has one color and is numbered 2-12 or 12-2
+	VariantClassic Variant = "classic"
+	// VariantMixxColors is the Qwixx Mixx sheet whose rows are numbered like the classic rows,
+	// but whose cells are of mixed colors, so each cell is crossed off with the color die of its own color
+	VariantMixxColors Variant = "mixx-colors"
+	// VariantMixxNumbers is the Qwixx Mixx sheet whose rows have one color each, but whose numbers are in a mixed order
+	VariantMixxNumbers Variant = "mixx-numbers"
+	// VariantBigPoints is the sheet of the Big Points edition, played with eight dice.
+	// An orange bonus row sits between the red and yellow rows, and a purple bonus row between the green and blue rows.
+	// A cell of a bonus row can only be crossed off once the cell of the same number directly above or below it is.
+	VariantBigPoints Variant = "big-points"
+)
+
+// Variants lists the variants NewBoard knows
+func Variants() []Variant {
+	return []Variant{VariantClassic, VariantMixxColors, VariantMixxNumbers, VariantBigPoints}
+}
+
+// RowColors lists the colors of the rows of this variant's sheet from top to bottom
+func (v Variant) RowColors() []actions.RowColor {
+	if v == VariantBigPoints {
+		return []actions.RowColor{
+			actions.RowColorRed,
+			actions.RowColorOrange,
+			actions.RowColorYellow,
+			actions.RowColorGreen,
+			actions.RowColorPurple,
+			actions.RowColorBlue,
+		}
+	}
+	return slices.Clone(rowColors)
+}
+
+// IsBonusRow reports whether the row with the given color is a bonus row on this variant's sheet
+func (v Variant) IsBonusRow(rowColor actions.RowColor) bool {
+	return v == VariantBigPoints && (rowColor == actions.RowColorOrange || rowColor == actions.RowColorPurple)
+}
+
+// Neighbors lists the colors of the rows directly above and below the row with the given color on this variant's sheet
+func (v Variant) Neighbors(rowColor actions.RowColor) []actions.RowColor {
+	sheet := v.RowColors()
+	idx := slices.Index(sheet, rowColor)
+	if idx < 0 {
+		return nil
+	}
+	var neighbors []actions.RowColor
+	if idx > 0 {
+		neighbors = append(neighbors, sheet[idx-1])
+	}
+	if idx < len(sheet)-1 {
+		neighbors = append(neighbors, sheet[idx+1])
+	}
+	return neighbors
+}
+
+// ParseVariant parses the name of a variant, an empty name meaning the classic sheet
+func ParseVariant(name string) (Variant, error) {
+	variant := Variant(strings.ToLower(strings.TrimSpace(name)))
+	if variant == "" {
+		return VariantClassic, nil
+	}
+	if !slices.Contains(Variants(), variant) {
+		names := make([]string, 0, len(Variants()))
+		for _, known := range Variants() {
+			names = append(names, string(known))
+		}
+		return "", fmt.Errorf("unknown variant %q, expected one of %v", name, strings.Join(names, ", "))
+	}
+	return variant, nil
+}
+
+// Cell is one cell of a row, with the number that crosses it off
+// and the color of the die that can be added to a white die to make that number
+type Cell struct {
+	Number int              `json:"number"`
+	Color  actions.RowColor `json:"color"`
+}
+
+// mixxColors are the colors of the cells of each row of the Mixx colors sheet from left to right.
+// Every color makes up a block of each row, and the blocks are shifted from row to row so each color is spread over the sheet.
+var mixxColors = map[actions.RowColor]string{
+	actions.RowColorRed:    "YYGGGBBBRRR",
+	actions.RowColorYellow: "GGBBBRRRYYY",
+	actions.RowColorGreen:  "BBRRRYYYGGG",
+	actions.RowColorBlue:   "RRYYYGGGBBB",
+}
+
+// mixxNumbers are the numbers of the cells of each row of the Mixx numbers sheet from left to right
+var mixxNumbers = map[actions.RowColor][]int{
+	actions.RowColorRed:    {10, 6, 2, 8, 3, 4, 12, 5, 9, 7, 11},
+	actions.RowColorYellow: {9, 12, 4, 6, 7, 2, 5, 8, 11, 3, 10},
+	actions.RowColorGreen:  {8, 2, 10, 12, 6, 9, 7, 4, 5, 11, 3},
+	actions.RowColorBlue:   {5, 7, 11, 9, 12, 3, 8, 10, 2, 6, 4},
+}
+
+// Cells lists the cells of the row with the given color on this variant's sheet from left to right,
+// the last of which locks the row, and nothing if the sheet has no such row.
+// The color of a row names its position on the sheet, see RowColors,
+// while the colors of its cells say which die crosses them off.
+func (v Variant) Cells(rowColor actions.RowColor) []Cell {
+	if !slices.Contains(v.RowColors(), rowColor) {
+		return nil
+	}
+	numbers := rowCellNumbers(rowColor)
+	if v == VariantMixxNumbers {
+		numbers = mixxNumbers[rowColor]
+	}
+	cells := make([]Cell, 0, len(numbers))
+	for idx, number := range numbers {
+		color := rowColor
+		if v == VariantMixxColors {
+			color = colorOfInitial(mixxColors[rowColor][idx])
+		}
+		cells = append(cells, Cell{Number: number, Color: color})
+	}
+	return cells
+}
+
+// CellColor returns the color of the cell with the given number in the row with the given color,
+// returning false if the row has no such cell
+func (v Variant) CellColor(rowColor actions.RowColor, cellNumber int) (actions.RowColor, bool) {
+	for _, cell := range v.Cells(rowColor) {
+		if cell.Number == cellNumber {
+			return cell.Color, true
+		}
+	}
+	return -1, false
+}
+
+// MixedColors reports whether the cells of a row can have different colors on this variant's sheet
+func (v Variant) MixedColors() bool {
+	return v == VariantMixxColors
+}
+
+func colorOfInitial(initial byte) actions.RowColor {
+	switch initial {
+	case 'R':
+		return actions.RowColorRed
+	case 'Y':
+		return actions.RowColorYellow
+	case 'G':
+		return actions.RowColorGreen
+	default:
+		return actions.RowColorBlue
+	}
+}
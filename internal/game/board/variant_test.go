@@ -0,0 +1,194 @@
+package board
+
+import (
+	"encoding/json"
+	"qwixx/internal/game/actions"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseVariant(t *testing.T) {
+	type testCase struct {
+		name            string
+		input           string
+		expectedVariant Variant
+		expectedErr     bool
+	}
+	testCases := []testCase{
+		{name: "empty is classic", input: "", expectedVariant: VariantClassic},
+		{name: "classic", input: "classic", expectedVariant: VariantClassic},
+		{name: "mixx colors ignoring case and spaces", input: " Mixx-Colors ", expectedVariant: VariantMixxColors},
+		{name: "mixx numbers", input: "mixx-numbers", expectedVariant: VariantMixxNumbers},
+		{name: "big points", input: "big-points", expectedVariant: VariantBigPoints},
+		{name: "unknown", input: "qwinto", expectedErr: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			variant, err := ParseVariant(tc.input)
+			if tc.expectedErr {
+				require.ErrorContains(t, err, "big-points")
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedVariant, variant)
+		})
+	}
+}
+
+func TestVariant_Cells(t *testing.T) {
+	for _, variant := range Variants() {
+		t.Run(string(variant), func(t *testing.T) {
+			variantBoard, err := NewBoard(variant)
+			require.NoError(t, err)
+			require.Equal(t, variant, variantBoard.Variant())
+
+			colorCounts := make(map[actions.RowColor]int)
+			for _, rowColor := range variant.RowColors() {
+				cells := variantBoard.Cells(rowColor)
+				require.Equal(t, variant.Cells(rowColor), cells)
+
+				// every row has one cell for each number
+				numbers := make([]int, 0, len(cells))
+				for _, cell := range cells {
+					numbers = append(numbers, cell.Number)
+					colorCounts[cell.Color]++
+					if !variant.MixedColors() {
+						require.Equal(t, rowColor, cell.Color)
+					}
+					cellColor, ok := variant.CellColor(rowColor, cell.Number)
+					require.True(t, ok)
+					require.Equal(t, cell.Color, cellColor)
+				}
+				slices.Sort(numbers)
+				require.Equal(t, []int{2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12}, numbers)
+			}
+			// and every color covers one row's worth of the sheet
+			for _, rowColor := range variant.RowColors() {
+				require.Equal(t, 11, colorCounts[rowColor])
+			}
+		})
+	}
+
+	_, ok := VariantClassic.CellColor(actions.RowColorRed, 13)
+	require.False(t, ok)
+	require.Equal(t, VariantClassic, NewGameBoard().Variant())
+	require.Empty(t, VariantClassic.Cells(actions.RowColorOrange))
+	_, err := NewBoard("qwinto")
+	require.Error(t, err)
+}
+
+func TestNewBoard_MixxNumbers(t *testing.T) {
+	mixxBoard, err := NewBoard(VariantMixxNumbers)
+	require.NoError(t, err)
+
+	// the red row reads 10 6 2 8 3 4 12 5 9 7 11
+	require.NoError(t, mixxBoard.MakeMove(actions.NewMove(actions.RowColorRed, 6)))
+	require.True(t, mixxBoard.IsCellMarked(actions.RowColorRed, 6))
+	require.NoError(t, mixxBoard.MakeMove(actions.NewMove(actions.RowColorRed, 3)))
+
+	ok, err := mixxBoard.IsMoveValid(actions.NewMove(actions.RowColorRed, 10))
+	require.False(t, ok)
+	require.ErrorIs(t, err, ErrLeftOfCrossedCell)
+	ok, err = mixxBoard.IsMoveValid(actions.NewMove(actions.RowColorRed, 3))
+	require.False(t, ok)
+	require.ErrorIs(t, err, ErrCellAlreadyCrossed)
+	ok, err = mixxBoard.IsMoveValid(actions.NewMove(actions.RowColorRed, 11))
+	require.False(t, ok)
+	require.ErrorIs(t, err, ErrNotEnoughForLock)
+	ok, err = mixxBoard.IsMoveValid(actions.NewMove(actions.RowColorRed, 12))
+	require.True(t, ok)
+	require.NoError(t, err)
+
+	copied := mixxBoard.Copy()
+	require.Equal(t, VariantMixxNumbers, copied.Variant())
+	require.NoError(t, copied.MakeMove(actions.NewMove(actions.RowColorRed, 12)))
+	require.False(t, mixxBoard.IsCellMarked(actions.RowColorRed, 12))
+
+	require.True(t, strings.HasPrefix(mixxBoard.Print(), "Red: [10| ] [6|X] [2| ] [8| ] [3|X]"))
+}
+
+func TestNewBoard_MixxColors(t *testing.T) {
+	mixxBoard, err := NewBoard(VariantMixxColors)
+	require.NoError(t, err)
+	require.NoError(t, mixxBoard.MakeMove(actions.NewMove(actions.RowColorRed, 3)))
+
+	// the rows are numbered like the classic rows, with the color of each cell printed before its number
+	require.True(t, strings.HasPrefix(mixxBoard.Print(), "Red: [Y2| ] [Y3|X] [G4| ]"))
+	require.Equal(t, 1, mixxBoard.CalculateScore())
+}
+
+func TestState_Variant(t *testing.T) {
+	mixxBoard, err := NewBoard(VariantMixxNumbers)
+	require.NoError(t, err)
+	require.NoError(t, mixxBoard.MakeMove(actions.NewMove(actions.RowColorBlue, 11)))
+	require.NoError(t, mixxBoard.MakeMove(actions.NewMove(actions.RowColorBlue, 12)))
+
+	state := StateOf(mixxBoard)
+	require.Equal(t, VariantMixxNumbers, state.Variant)
+	require.Equal(t, []int{11, 12}, state.Rows[actions.RowColorBlue])
+
+	encoded, err := json.Marshal(state)
+	require.NoError(t, err)
+	var decoded State
+	require.NoError(t, json.Unmarshal(encoded, &decoded))
+	rebuilt, err := FromState(decoded)
+	require.NoError(t, err)
+	require.Equal(t, mixxBoard, rebuilt)
+
+	// classic states leave the variant out so they read like they always did
+	encoded, err = json.Marshal(StateOf(NewGameBoard()))
+	require.NoError(t, err)
+	require.NotContains(t, string(encoded), "variant")
+}
+
+func TestNewBoard_BigPoints(t *testing.T) {
+	bigPoints, err := NewBoard(VariantBigPoints)
+	require.NoError(t, err)
+
+	// a bonus cell needs the cell of the same number above or below it crossed off
+	ok, err := bigPoints.IsMoveValid(actions.NewMove(actions.RowColorOrange, 5))
+	require.False(t, ok)
+	require.ErrorIs(t, err, ErrBonusWithoutNeighbor)
+	require.EqualError(t, err, "cell 5 of the Orange bonus row needs cell 5 of the Red or Yellow row crossed off")
+	require.ErrorIs(t, bigPoints.MakeMove(actions.NewMove(actions.RowColorOrange, 5)), ErrBonusWithoutNeighbor)
+	require.False(t, bigPoints.IsCellMarked(actions.RowColorOrange, 5))
+
+	require.NoError(t, bigPoints.MakeMove(actions.NewMove(actions.RowColorYellow, 5)))
+	require.NoError(t, bigPoints.MakeMove(actions.NewMove(actions.RowColorOrange, 5)))
+	require.NoError(t, bigPoints.MakeMove(actions.NewMove(actions.RowColorBlue, 9)))
+	require.NoError(t, bigPoints.MakeMove(actions.NewMove(actions.RowColorPurple, 9)))
+
+	// bonus rows still go from left to right and score like any other row
+	ok, err = bigPoints.IsMoveValid(actions.NewMove(actions.RowColorPurple, 10))
+	require.False(t, ok)
+	require.ErrorIs(t, err, ErrLeftOfCrossedCell)
+	require.Equal(t, 4, bigPoints.CalculateScore())
+
+	require.Equal(t, []string{"Red", "Orange", "Yellow", "Green", "Purple", "Blue"}, printedRowNames(bigPoints))
+	require.Equal(t, []string{"Red", "Yellow", "Green", "Blue"}, printedRowNames(NewGameBoard()))
+	require.False(t, NewGameBoard().IsCellMarked(actions.RowColorOrange, 5))
+	ok, err = NewGameBoard().IsMoveValid(actions.NewMove(actions.RowColorPurple, 5))
+	require.False(t, ok)
+	require.ErrorIs(t, err, ErrInvalidColor)
+
+	// bonus rows are rebuilt after the rows around them, whatever order the state lists them in
+	state := StateOf(bigPoints)
+	require.Equal(t, []int{5}, state.Rows[actions.RowColorOrange])
+	rebuilt, err := FromState(state)
+	require.NoError(t, err)
+	require.Equal(t, bigPoints, rebuilt)
+
+	_, err = FromState(State{Rows: map[actions.RowColor][]int{actions.RowColorOrange: {5}}})
+	require.Error(t, err)
+}
+
+func printedRowNames(b Board) []string {
+	var names []string
+	for _, line := range strings.Split(b.Print(), "\n") {
+		names = append(names, strings.SplitN(line, ":", 2)[0])
+	}
+	return names
+}
@@ -0,0 +1,94 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"qwixx/internal/game/board"
+)
+
+// GameConfig holds the house rules a game is played by.
+// The zero value of every field stands for the rule of the original game, see DefaultGameConfig,
+// so a config only needs to set the rules a group plays differently.
+type GameConfig struct {
+	// LocksToEnd is how many locked rows end the game
+	LocksToEnd int `json:"locks_to_end,omitempty"`
+	// PenaltiesToEnd is how many penalties a single player takes before the game ends
+	PenaltiesToEnd int `json:"penalties_to_end,omitempty"`
+	// PenaltyValue is the number of points each penalty costs at the end of the game
+	PenaltyValue int `json:"penalty_value,omitempty"`
+	// AnyDiceOrder lets the active player use the color dice before the white dice,
+	// rather than always crossing off the white dice sum first
+	AnyDiceOrder bool `json:"any_dice_order,omitempty"`
+	// MaxTurns stops a game that has not ended on its own after this many turns
+	MaxTurns int `json:"max_turns,omitempty"`
+	// Hints lets human players ask for the turn the analysis package recommends whenever they are prompted
+	Hints bool `json:"hints,omitempty"`
+}
+
+// DefaultGameConfig returns the rules of the original game,
+// with a turn limit guarding against eternal games
+func DefaultGameConfig() GameConfig {
+	return GameConfig{
+		LocksToEnd:     2,
+		PenaltiesToEnd: 4,
+		PenaltyValue:   5,
+		MaxTurns:       1000,
+	}
+}
+
+// WithDefaults returns the config with the rules it leaves out filled in from DefaultGameConfig
+func (c GameConfig) WithDefaults() GameConfig {
+	defaults := DefaultGameConfig()
+	if c.LocksToEnd == 0 {
+		c.LocksToEnd = defaults.LocksToEnd
+	}
+	if c.PenaltiesToEnd == 0 {
+		c.PenaltiesToEnd = defaults.PenaltiesToEnd
+	}
+	if c.PenaltyValue == 0 {
+		c.PenaltyValue = defaults.PenaltyValue
+	}
+	if c.MaxTurns == 0 {
+		c.MaxTurns = defaults.MaxTurns
+	}
+	return c
+}
+
+// Validate returns every rule of the config that no game on the sheet of the given variant can be played by,
+// the classic sheet if empty, or nil if the config is playable. Rules left out of the config, which are zero, are always playable.
+func (c GameConfig) Validate(variant board.Variant) error {
+	if variant == "" {
+		variant = board.VariantClassic
+	}
+	var errs []error
+	rows := len(variant.RowColors())
+	if c.LocksToEnd < 0 || c.LocksToEnd > rows {
+		errs = append(errs, fmt.Errorf("locks to end must be between 0 and %v on the %v sheet, got %v", rows, variant, c.LocksToEnd))
+	}
+	if c.PenaltiesToEnd < 0 {
+		errs = append(errs, fmt.Errorf("penalties to end must not be negative, got %v", c.PenaltiesToEnd))
+	}
+	if c.PenaltyValue < 0 {
+		errs = append(errs, fmt.Errorf("penalty value must not be negative, got %v", c.PenaltyValue))
+	}
+	if c.MaxTurns < 0 {
+		errs = append(errs, fmt.Errorf("max turns must not be negative, got %v", c.MaxTurns))
+	}
+	return errors.Join(errs...)
+}
+
+// String describes the rules of the config in a single line, for the game log
+func (c GameConfig) String() string {
+	order := "white dice first"
+	if c.AnyDiceOrder {
+		order = "dice in any order"
+	}
+	description := fmt.Sprintf(
+		"game ends at %v locked rows or %v penalties, penalties cost %v points, %v, at most %v turns",
+		c.LocksToEnd, c.PenaltiesToEnd, c.PenaltyValue, order, c.MaxTurns,
+	)
+	if c.Hints {
+		description += ", hints allowed"
+	}
+	return description
+}
@@ -0,0 +1,70 @@
+package game
+
+import (
+	"qwixx/internal/game/board"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGameConfig_Validate(t *testing.T) {
+	type testCase struct {
+		name          string
+		input         GameConfig
+		inputVariant  board.Variant
+		expectedError []string
+	}
+	testCases := []testCase{
+		{name: "zero value", input: GameConfig{}},
+		{name: "default", input: DefaultGameConfig()},
+		{name: "house rules", input: GameConfig{LocksToEnd: 3, PenaltiesToEnd: 5, PenaltyValue: 10, AnyDiceOrder: true, MaxTurns: 200}},
+		{
+			name:          "more locks than rows",
+			input:         GameConfig{LocksToEnd: 5},
+			expectedError: []string{"locks to end must be between 0 and 4 on the classic sheet, got 5"},
+		},
+		{
+			name:          "more locks than rows of the mixx sheet",
+			input:         GameConfig{LocksToEnd: 5},
+			inputVariant:  board.VariantMixxColors,
+			expectedError: []string{"locks to end must be between 0 and 4 on the mixx-colors sheet, got 5"},
+		},
+		{name: "locks of the bonus rows", input: GameConfig{LocksToEnd: 6}, inputVariant: board.VariantBigPoints},
+		{
+			name:          "more locks than rows of the big points sheet",
+			input:         GameConfig{LocksToEnd: 7},
+			inputVariant:  board.VariantBigPoints,
+			expectedError: []string{"locks to end must be between 0 and 6 on the big-points sheet, got 7"},
+		},
+		{
+			name:  "every negative rule",
+			input: GameConfig{LocksToEnd: -1, PenaltiesToEnd: -1, PenaltyValue: -1, MaxTurns: -1},
+			expectedError: []string{
+				"locks to end must be between 0 and 4 on the classic sheet, got -1",
+				"penalties to end must not be negative, got -1",
+				"penalty value must not be negative, got -1",
+				"max turns must not be negative, got -1",
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.input.Validate(tc.inputVariant)
+			if len(tc.expectedError) == 0 {
+				require.NoError(t, err)
+				return
+			}
+			for _, expected := range tc.expectedError {
+				require.ErrorContains(t, err, expected)
+			}
+		})
+	}
+}
+
+func TestGameConfig_WithDefaults(t *testing.T) {
+	require.Equal(t, DefaultGameConfig(), GameConfig{}.WithDefaults())
+	require.Equal(t,
+		GameConfig{LocksToEnd: 3, PenaltiesToEnd: 4, PenaltyValue: 5, AnyDiceOrder: true, MaxTurns: 1000},
+		GameConfig{LocksToEnd: 3, AnyDiceOrder: true}.WithDefaults(),
+	)
+}
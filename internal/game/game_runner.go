@@ -2,82 +2,214 @@ package game
 
 import (
 	"fmt"
+	"log/slog"
 	"math/rand"
 	"qwixx/internal/game/actions"
 	"qwixx/internal/game/board"
 	"qwixx/internal/game/player"
 	"qwixx/internal/game/rule_checker"
+	"qwixx/internal/game/ruleset"
+	"qwixx/internal/logging"
+	"slices"
 	"strings"
 
 	"github.com/google/uuid"
 )
 
 type GameRunner interface {
-	RunGame()
+	// RunGame plays the game until it ends and returns how it ended
+	RunGame() GameResult
+	// Players returns the players of this game by the IDs they are known by during the game
+	Players() map[player.PlayerID]player.Player
 }
 
 type gameRunnerImpl struct {
 	playersByID map[player.PlayerID]player.Player
-	boards      map[player.PlayerID]board.Board
-	penalties   map[player.PlayerID]int
-	locks       map[actions.RowColor]bool
+	// seating is the order the players were given in, which the play order is shuffled from
+	seating   []player.PlayerID
+	boards    map[player.PlayerID]board.Board
+	penalties map[player.PlayerID]int
+	locks     map[actions.RowColor]bool
+	logger    *slog.Logger
+	gameID    string
+	rng       *rand.Rand
+	variant   board.Variant
+	ruleset   ruleset.Ruleset
+	config    GameConfig
+	// recordDecisions keeps the turns the players choose, along with their boards, in the result
+	recordDecisions bool
+	// decisions are the turns the players have chosen so far
+	decisions []Decision
 }
 
-func NewGameRunner(players []player.Player) GameRunner {
-	playersByID := makePlayersByID(players)
-	return &gameRunnerImpl{
+// Option changes how a game is run
+type Option func(gr *gameRunnerImpl)
+
+// WithLogger narrates the game to the given logger, games are silent without one.
+// The game is narrated at the debug level, apart from its start, its end, locked rows and invalid turns.
+func WithLogger(logger *slog.Logger) Option {
+	return func(gr *gameRunnerImpl) {
+		gr.logger = logger
+	}
+}
+
+// WithGameID identifies the game in its log records with the given ID instead of a random one
+func WithGameID(gameID string) Option {
+	return func(gr *gameRunnerImpl) {
+		gr.gameID = gameID
+	}
+}
+
+// WithRand makes the play order and dice rolls come from the given source of randomness,
+// so a game between deterministic players can be reproduced from its seed
+func WithRand(rng *rand.Rand) Option {
+	return func(gr *gameRunnerImpl) {
+		gr.rng = rng
+	}
+}
+
+// WithVariant plays the game on the sheet of the given variant instead of the classic one,
+// falling back to the classic sheet with a warning if the variant is unknown
+func WithVariant(variant board.Variant) Option {
+	return func(gr *gameRunnerImpl) {
+		gr.variant = variant
+	}
+}
+
+// WithRuleset plays the game by the given rules for crossing off cells instead of the classic ones
+func WithRuleset(rules ruleset.Ruleset) Option {
+	return func(gr *gameRunnerImpl) {
+		gr.ruleset = rules
+	}
+}
+
+// WithConfig plays the game by the given house rules, with the rules the config leaves out taken from DefaultGameConfig.
+// An invalid config falls back to the default rules with a warning.
+func WithConfig(config GameConfig) Option {
+	return func(gr *gameRunnerImpl) {
+		gr.config = config
+	}
+}
+
+// WithDecisions records every turn the players choose, along with the board and dice they chose it for,
+// in the Decisions of the result to replay and review the game with. Games run without it leave them out,
+// as a copy of the board for every decision adds up over many games.
+func WithDecisions() Option {
+	return func(gr *gameRunnerImpl) {
+		gr.recordDecisions = true
+	}
+}
+
+func NewGameRunner(players []player.Player, options ...Option) GameRunner {
+	playersByID, seating := makePlayersByID(players)
+	gr := &gameRunnerImpl{
 		playersByID: playersByID,
-		boards:      initializeBoards(playersByID),
+		seating:     seating,
 		penalties:   make(map[player.PlayerID]int),
 		locks:       make(map[actions.RowColor]bool),
+		logger:      logging.Discard(),
+		gameID:      uuid.New().String(),
+		variant:     board.VariantClassic,
+		ruleset:     ruleset.Classic(),
+	}
+	for _, option := range options {
+		option(gr)
+	}
+	gr.logger = gr.logger.With("game_id", gr.gameID)
+	if _, err := board.NewBoard(gr.variant); err != nil {
+		gr.logger.Warn(fmt.Sprintf("playing the classic sheet: %v", err), "error", err)
+		gr.variant = board.VariantClassic
 	}
+	if err := gr.config.Validate(gr.variant); err != nil {
+		gr.logger.Warn(fmt.Sprintf("playing the default house rules: %v", err), "error", err)
+		gr.config = GameConfig{}
+	}
+	gr.config = gr.config.WithDefaults()
+	gr.boards = initializeBoards(playersByID, gr.variant, gr.ruleset)
+	return gr
 }
 
-func (gr *gameRunnerImpl) RunGame() {
-	playOrder := establishPlayOrder(gr.playersByID)
+func (gr *gameRunnerImpl) RunGame() GameResult {
+	playOrder := gr.establishPlayOrder()
 	gr.notifyPlayersOfPlayOrder(playOrder)
+	gr.logger.Info(fmt.Sprintf("house rules: %v", gr.config), "config", gr.config)
 
 	turnCount := 0
-	for !gr.isGameWon() {
-		if turnCount > 1000 { // guard against eternal games
-			break
-		}
+	endReason := EndReasonTurnLimit
+	for turnCount < gr.config.MaxTurns {
 		currentPlayer := playOrder[turnCount%len(playOrder)]
-		err := gr.runSingleTurn(currentPlayer)
+		turnLogger := gr.logger.With("turn", turnCount+1)
+		err := gr.runSingleTurn(turnLogger, turnCount+1, currentPlayer)
 		if err != nil {
 			// TODO do something better
-			fmt.Printf("error: %v", err.Error())
+			turnLogger.Error(fmt.Sprintf("error: %v", err), "error", err)
 		}
 		turnCount++
+		if reason, over := gr.endReason(); over {
+			endReason = reason
+			break
+		}
+	}
+	return gr.endGame(playOrder, turnCount, endReason)
+}
+
+// rollDice rolls the dice from the runner's source of randomness, if it has one,
+// adding the orange and purple dice on the Big Points sheet
+func (gr *gameRunnerImpl) rollDice() actions.DiceRoll {
+	if gr.variant == board.VariantBigPoints {
+		if gr.rng == nil {
+			return actions.RollBigPointsDice()
+		}
+		return actions.RollBigPointsDiceWith(gr.rng)
+	}
+	if gr.rng == nil {
+		return actions.RollQwixxDice()
 	}
-	gr.endGame()
+	return actions.RollQwixxDiceWith(gr.rng)
 }
 
-func makePlayersByID(players []player.Player) map[player.PlayerID]player.Player {
+func (gr *gameRunnerImpl) Players() map[player.PlayerID]player.Player {
+	playersByID := make(map[player.PlayerID]player.Player, len(gr.playersByID))
+	for playerID, pl := range gr.playersByID {
+		playersByID[playerID] = pl
+	}
+	return playersByID
+}
+
+// makePlayersByID gives each player an ID, returning the IDs in the order the players were given too
+func makePlayersByID(players []player.Player) (map[player.PlayerID]player.Player, []player.PlayerID) {
 	playersByID := make(map[player.PlayerID]player.Player, len(players))
+	seating := make([]player.PlayerID, 0, len(players))
 	for _, pl := range players {
 		id := player.PlayerID(uuid.New().String())
 		playersByID[id] = pl
+		seating = append(seating, id)
 	}
-	return playersByID
+	return playersByID, seating
 }
 
-// establishPlayOrder establishes the play order of a game comprised of the given list of players
-func establishPlayOrder(playersByID map[player.PlayerID]player.Player) []player.PlayerID {
-	playOrder := make([]player.PlayerID, 0, len(playersByID))
-	for playerID, _ := range playersByID {
-		playOrder = append(playOrder, playerID)
+// establishPlayOrder establishes the play order of the game by shuffling its players
+func (gr *gameRunnerImpl) establishPlayOrder() []player.PlayerID {
+	playOrder := slices.Clone(gr.seating)
+	shuffle := rand.Shuffle
+	if gr.rng != nil {
+		shuffle = gr.rng.Shuffle
 	}
-	rand.Shuffle(len(playOrder), func(i, j int) {
+	shuffle(len(playOrder), func(i, j int) {
 		playOrder[i], playOrder[j] = playOrder[j], playOrder[i]
 	})
 	return playOrder
 }
 
-func initializeBoards(playOrder map[player.PlayerID]player.Player) map[player.PlayerID]board.Board {
+// initializeBoards gives every player an empty board of the given variant, which must be known, played by the given rules
+func initializeBoards(
+	playOrder map[player.PlayerID]player.Player,
+	variant board.Variant,
+	rules ruleset.Ruleset,
+) map[player.PlayerID]board.Board {
 	boards := make(map[player.PlayerID]board.Board, len(playOrder))
 	for playerID, _ := range playOrder {
-		boards[playerID] = board.NewGameBoard()
+		boards[playerID], _ = board.NewBoard(variant, board.WithRuleset(rules))
 	}
 	return boards
 }
@@ -87,112 +219,199 @@ func (gr *gameRunnerImpl) notifyPlayersOfPlayOrder(playOrder []player.PlayerID)
 	for _, playerID := range playOrder {
 		orderNames = append(orderNames, gr.playersByID[playerID].GetName())
 	}
-	fmt.Printf("play order: %v\n", strings.Join(orderNames, ", "))
+	gr.logger.Info(fmt.Sprintf("play order: %v", strings.Join(orderNames, ", ")), "play_order", orderNames)
 
 	for _, p := range gr.playersByID {
 		p.InformOfPlayOrder(orderNames)
 	}
 }
 
-// isGameWon determines if the currently running game is won
-// a game is won if either
-// - two rows are locked
-// - a player has taken four penalties
-func (gr *gameRunnerImpl) isGameWon() bool {
-	anyPlayerHasFourPenalties := func() bool {
-		for _, count := range gr.penalties {
-			if count >= 4 {
-				return true
-			}
+// endReason determines if the currently running game is over and why
+// a game is over if either
+// - as many rows as the house rules say are locked, two by default
+// - a player has taken as many penalties as the house rules say, four by default
+func (gr *gameRunnerImpl) endReason() (EndReason, bool) {
+	if len(gr.locks) >= gr.config.LocksToEnd {
+		return EndReasonRowsLocked, true
+	}
+	for _, count := range gr.penalties {
+		if count >= gr.config.PenaltiesToEnd {
+			return EndReasonPenalties, true
 		}
-		return false
 	}
-	return len(gr.locks) >= 2 || anyPlayerHasFourPenalties()
+	return "", false
 }
 
-func (gr *gameRunnerImpl) runSingleTurn(currentPlayerID player.PlayerID) error {
+func (gr *gameRunnerImpl) runSingleTurn(logger *slog.Logger, turn int, currentPlayerID player.PlayerID) error {
 	// Each turn, there is one active player and the rest of the players are inactive.
-	// all six dice are rolled (two white and one of each row color)
+	// all six dice are rolled (two white and one of each row color), or eight on the Big Points sheet
 	// the active player can cross off a cell in any color row with the sum of the white dice
 	// the active player can then cross off a cell in a color row with the sum of that color die and one white die
-	// the active player must cross off a cell with the sum of the white die before they cross off the sum of a color die.
+	// the active player must cross off a cell with the sum of the white die before they cross off the sum of a color die,
+	// unless the house rules let them use the dice in any order.
 	// they can choose to cross of only the white die sum or a color die sum if they desire
 	// if the active player cannot cross of any cells or does not want to cross off any cells, they must take a penalty
 	// each inactive player can cross off a cell in any color row with the sum of the white dice as well, if they like.
 	// they cannot do anything with the color dice when they are not the active player, and they do not need to take a penalty if they do not make a move.
 
 	currentPlayer := gr.playersByID[currentPlayerID]
-	fmt.Printf("it's player %v's turn\n", currentPlayer.GetName())
+	logger = logger.With("player", currentPlayer.GetName())
+	logger.Debug(fmt.Sprintf("it's player %v's turn", currentPlayer.GetName()))
 
-	diceRoll := actions.RollQwixxDice()
-	printDiceRoll(diceRoll)
+	diceRoll := gr.rollDice()
+	logDiceRoll(logger, diceRoll)
 
 	currentPlayerBoard := gr.boards[currentPlayerID]
 
 	// pass another copy so any mutations in prompting don't affect the board we're going to apply real changes to
-	activePlayerTurn := promptActivePlayerTurn(currentPlayer, currentPlayerBoard.Copy(), diceRoll)
+	activePlayerTurn, colorFirst := promptActivePlayerTurn(logger, currentPlayer, currentPlayerBoard.Copy(), diceRoll, gr.config)
+	if gr.recordDecisions {
+		gr.decisions = append(gr.decisions, Decision{
+			Turn: turn, PlayerID: currentPlayerID, Active: true, Board: board.StateOf(currentPlayerBoard), DiceRoll: diceRoll,
+			Chosen: activePlayerTurn, ColorFirst: colorFirst,
+		})
+	}
 
 	if isActiveTurnPenalty(activePlayerTurn) {
 		gr.penalties[currentPlayerID] += 1
-		printPenalty(currentPlayer.GetName(), gr.penalties[currentPlayerID])
+		logPenalty(logger, currentPlayer.GetName(), gr.penalties[currentPlayerID])
 	} else {
 
-		updatedBoard, err := board.ApplyActivePlayerTurn(currentPlayerBoard.Copy(), activePlayerTurn)
+		appliedTurn := activePlayerTurn
+		if colorFirst {
+			// ApplyActivePlayerTurn makes the white dice move first, so swap the moves to make them in the order they were validated in
+			appliedTurn = actions.ActivePlayerTurn{WhiteDiceMove: activePlayerTurn.ColorDiceMove, ColorDiceMove: activePlayerTurn.WhiteDiceMove}
+		}
+		logLinkedCrosses(logger, currentPlayer.GetName(), currentPlayerBoard, appliedTurn)
+		updatedBoard, err := board.ApplyActivePlayerTurn(currentPlayerBoard.Copy(), appliedTurn)
 		if err != nil {
 			return err
 		}
 
 		gr.boards[currentPlayerID] = updatedBoard
+		logPlayerBoard(logger, currentPlayer.GetName(), updatedBoard)
+		currentPlayer.InformSuccessfulTurn(updatedBoard.Copy())
+		// opponents are told of the moves in the order they were made, so their copies of the board can make them the same way
+		if appliedTurn.WhiteDiceMove != nil {
+			gr.informOpponentsOfMove(currentPlayerID, *appliedTurn.WhiteDiceMove)
+		}
+		if appliedTurn.ColorDiceMove != nil {
+			gr.informOpponentsOfMove(currentPlayerID, *appliedTurn.ColorDiceMove)
+		}
 	}
 
-	for playerID, pl := range gr.playersByID {
+	for _, playerID := range gr.seating {
 		if playerID != currentPlayerID {
+			pl := gr.playersByID[playerID]
 			inactivePlayerBoard := gr.boards[playerID]
-			proposedTurn := promptInactivePlayerTurn(pl, inactivePlayerBoard, diceRoll)
+			// pass a copy so validating the proposed turn doesn't cross off cells on the real board
+			proposedTurn := promptInactivePlayerTurn(logger.With("player", pl.GetName()), pl, inactivePlayerBoard.Copy(), diceRoll)
+			if gr.recordDecisions {
+				gr.decisions = append(gr.decisions, Decision{
+					Turn: turn, PlayerID: playerID, Board: board.StateOf(inactivePlayerBoard), DiceRoll: diceRoll,
+					Chosen: actions.ActivePlayerTurn{WhiteDiceMove: proposedTurn.WhiteDiceMove},
+				})
+			}
 			// player can elect to do nothing without a penalty if they are not the active player
 			// so only do something if they provided a move
 			if proposedTurn.WhiteDiceMove != nil {
+				logLinkedCrosses(
+					logger.With("player", pl.GetName()), pl.GetName(), inactivePlayerBoard,
+					actions.ActivePlayerTurn{WhiteDiceMove: proposedTurn.WhiteDiceMove},
+				)
 				err := inactivePlayerBoard.MakeMove(*proposedTurn.WhiteDiceMove)
 				if err != nil {
 					return err
 				}
+				pl.InformSuccessfulTurn(inactivePlayerBoard.Copy())
+				gr.informOpponentsOfMove(playerID, *proposedTurn.WhiteDiceMove)
 			}
 		}
 	}
 
+	// every player acts on the same roll, so rows completed during it are only locked once everyone has moved
+	gr.lockCompletedRows(logger)
 	return nil
 }
 
+// lockCompletedRows locks the rows that any player has crossed off the rightmost cell of for all players
+func (gr *gameRunnerImpl) lockCompletedRows(logger *slog.Logger) {
+	for _, rowColor := range gr.variant.RowColors() {
+		if gr.locks[rowColor] {
+			continue
+		}
+		completed := false
+		for _, playerBoard := range gr.boards {
+			if playerBoard.IsCellMarked(rowColor, lastCellNumber(playerBoard, rowColor)) {
+				completed = true
+			}
+		}
+		if !completed {
+			continue
+		}
+		gr.locks[rowColor] = true
+		for _, playerBoard := range gr.boards {
+			playerBoard.LockRow(rowColor)
+		}
+		logger.Info(fmt.Sprintf("row %v was locked", rowColor), "row", rowColor)
+		for _, pl := range gr.playersByID {
+			pl.InformRowLocked(rowColor)
+		}
+	}
+}
+
+// lastCellNumber is the number of the rightmost cell of the row with the given color, which locks the row when crossed off
+func lastCellNumber(playerBoard board.Board, rowColor actions.RowColor) int {
+	cells := playerBoard.Cells(rowColor)
+	return cells[len(cells)-1].Number
+}
+
+// informOpponentsOfMove tells every player other than the one who made the given move about it
+func (gr *gameRunnerImpl) informOpponentsOfMove(moverID player.PlayerID, move actions.Move) {
+	for playerID, pl := range gr.playersByID {
+		if playerID != moverID {
+			pl.InformOfOpponentMove(moverID, move)
+		}
+	}
+}
+
 // promptActivePlayerTurn prompts a player three times for their active player turn, where they can:
 // 1. make a move with the sum of the two white dice
 // 2. make a move with the sum of one white die and one color die
 // OR
 // take a penalty
 //
-// the returned turn has been guaranteed to be valid for the copy of the board they were given
+// the returned turn has been guaranteed to be valid for the copy of the board they were given,
+// with its color dice move made first if colorFirst is set, which the house rules may allow
 func promptActivePlayerTurn(
+	logger *slog.Logger,
 	currentPlayer player.Player,
 	playerBoard board.Board,
 	diceRoll actions.DiceRoll,
-) actions.ActivePlayerTurn {
+	config GameConfig,
+) (turn actions.ActivePlayerTurn, colorFirst bool) {
 	for try := 0; try < 3; try++ {
-		printPlayerBoard(currentPlayer.GetName(), playerBoard)
+		logPlayerBoard(logger, currentPlayer.GetName(), playerBoard)
 
 		// copy the board so the player can't manipulate it
 		proposedTurn := currentPlayer.PromptActivePlayerTurn(playerBoard.Copy(), diceRoll)
 
-		// copy the board so validity checking does not mutate the original board if something was invalid
-		if isActiveTurnValid(playerBoard.Copy(), diceRoll, proposedTurn) {
-			printValidTurn(currentPlayer.GetName(), proposedTurn.String())
-			printPlayerBoard(currentPlayer.GetName(), playerBoard)
-			return proposedTurn
+		var err error
+		if config.AnyDiceOrder {
+			colorFirst, err = rule_checker.ValidateActivePlayerTurnInAnyOrder(playerBoard, diceRoll, proposedTurn)
 		} else {
-			printInvalidTurn(currentPlayer.GetName(), proposedTurn.String())
+			err = rule_checker.ValidateActivePlayerTurn(playerBoard, diceRoll, proposedTurn)
+		}
+		if err == nil {
+			logValidTurn(logger, currentPlayer.GetName(), proposedTurn)
+			return proposedTurn, colorFirst
 		}
+		logInvalidTurn(logger, currentPlayer.GetName(), playerBoard, proposedTurn.String(), err)
+		currentPlayer.InformInvalidTurn(err)
 	}
 
 	// three invalid attempts in one turn forces a penalty
-	return actions.ActivePlayerTurn{}
+	return actions.ActivePlayerTurn{}, false
 
 }
 
@@ -200,131 +419,126 @@ func promptActivePlayerTurn(
 // where they can make a move with the sum of the two white dice
 // the returned turn has been guaranteed to be valid for the copy of the board they were given
 func promptInactivePlayerTurn(
+	logger *slog.Logger,
 	currentPlayer player.Player,
 	playerBoard board.Board,
 	diceRoll actions.DiceRoll,
 ) actions.InactivePlayerTurn {
 	for try := 0; try < 3; try++ {
-		proposedTurn := currentPlayer.PromptInactivePlayerTurn(playerBoard, diceRoll)
-		if isInactiveTurnValid(playerBoard, diceRoll, proposedTurn) {
+		proposedTurn := currentPlayer.PromptInactivePlayerTurn(playerBoard.Copy(), diceRoll)
+		err := rule_checker.ValidateInactivePlayerTurn(playerBoard, diceRoll, proposedTurn)
+		if err == nil {
 			return proposedTurn
 		}
+		logInvalidTurn(logger, currentPlayer.GetName(), playerBoard, proposedTurn.WhiteDiceMove.String(), err)
+		currentPlayer.InformInvalidTurn(err)
 	}
 
 	// three invalid attempts in one turn forces a no-op
 	return actions.InactivePlayerTurn{}
 }
 
-func isActiveTurnValid(
-	playerBoard board.Board,
-	diceRoll actions.DiceRoll,
-	activePlayerTurn actions.ActivePlayerTurn,
-) bool {
-	if isActiveTurnPenalty(activePlayerTurn) {
-		return true
-	}
-	if activePlayerTurn.WhiteDiceMove == nil {
-		return isColorDiceMoveValid(playerBoard, diceRoll, *activePlayerTurn.ColorDiceMove)
-	}
-	if activePlayerTurn.ColorDiceMove == nil {
-		return isWhiteDiceMoveValid(playerBoard, diceRoll, *activePlayerTurn.WhiteDiceMove)
-	}
-
-	return isWhiteDiceMoveValid(
-		playerBoard, diceRoll, *activePlayerTurn.WhiteDiceMove,
-	) && isColorDiceMoveValid(
-		playerBoard, diceRoll, *activePlayerTurn.ColorDiceMove,
-	)
-}
-
-func isInactiveTurnValid(
-	playerBoard board.Board,
-	diceRoll actions.DiceRoll,
-	inactivePlayerTurn actions.InactivePlayerTurn,
-) bool {
-	if inactivePlayerTurn.WhiteDiceMove == nil {
-		return true
-	}
-	return isWhiteDiceMoveValid(playerBoard, diceRoll, *inactivePlayerTurn.WhiteDiceMove)
-}
-
-// isWhiteDiceMoveValid determines if the rulechecker says the given color dice move is valid,
-// and the given board will allow the move to be played
-func isWhiteDiceMoveValid(
-	playerBoard board.Board,
-	diceRoll actions.DiceRoll,
-	move actions.Move,
-) bool {
-	if !rule_checker.WhiteDiceMoveIsValidForBoard(playerBoard, diceRoll, move) {
-		return false
-	}
-
-	return playerBoard.MakeMove(move) == nil
-}
-
-// isColorDiceMoveValid determines if the rulechecker says the given color dice move is valid,
-// and the given board will allow the move to be played
-func isColorDiceMoveValid(
-	playerBoard board.Board,
-	diceRoll actions.DiceRoll,
-	move actions.Move,
-) bool {
-	if !rule_checker.ColorDiceMoveIsValidForBoard(playerBoard, diceRoll, move) {
-		return false
-	}
-	return playerBoard.MakeMove(move) == nil
-}
-
 // isActiveTurnPenalty determines if the given turn represents a penalty,
 // which is the case when both moves it contains are nil
 func isActiveTurnPenalty(activePlayerTurn actions.ActivePlayerTurn) bool {
 	return activePlayerTurn.WhiteDiceMove == nil && activePlayerTurn.ColorDiceMove == nil
 }
 
-func printDiceRoll(diceRoll actions.DiceRoll) {
-	fmt.Printf("the white dice rolled were %v and %v\n", diceRoll.White1, diceRoll.White2)
-	fmt.Printf(
-		"the color dice rolled were red:%v, yellow:%v, green:%v, and blue:%v\n",
-		diceRoll.Red,
-		diceRoll.Yellow,
-		diceRoll.Green,
-		diceRoll.Blue,
+func logDiceRoll(logger *slog.Logger, diceRoll actions.DiceRoll) {
+	logger.Debug(
+		fmt.Sprintf("the white dice rolled were %v and %v", diceRoll.White1, diceRoll.White2),
+		"white1", diceRoll.White1,
+		"white2", diceRoll.White2,
+	)
+	logger.Debug(
+		fmt.Sprintf(
+			"the color dice rolled were red:%v, yellow:%v, green:%v, and blue:%v",
+			diceRoll.Red,
+			diceRoll.Yellow,
+			diceRoll.Green,
+			diceRoll.Blue,
+		),
+		"red", diceRoll.Red,
+		"yellow", diceRoll.Yellow,
+		"green", diceRoll.Green,
+		"blue", diceRoll.Blue,
 	)
 }
 
-func printValidTurn(playerName string, validTurnString string) {
-	fmt.Printf("player %v played a valid turn: %v\n", playerName, validTurnString)
+func logValidTurn(logger *slog.Logger, playerName string, validTurn actions.ActivePlayerTurn) {
+	logger.Debug(fmt.Sprintf("player %v played a valid turn: %v", playerName, validTurn.String()), "turn_played", validTurn)
 }
 
-func printInvalidTurn(playerName string, invalidTurnString string) {
-	fmt.Printf("player %v played an invalid turn: %v\n", playerName, invalidTurnString)
+// logInvalidTurn logs the invalid turn along with the board it was played on, in the board notation so it can be reproduced
+func logInvalidTurn(logger *slog.Logger, playerName string, playerBoard board.Board, invalidTurn string, err error) {
+	logger.Warn(
+		fmt.Sprintf("player %v played an invalid turn: %v: %v", playerName, invalidTurn, err),
+		"turn_played", invalidTurn,
+		"error", err,
+		"code", board.ViolationCode(err),
+		"board", board.Format(playerBoard, 0),
+	)
 }
 
-func printPlayerBoard(playerName string, playerBoard board.Board) {
-	fmt.Printf("%v's board:\n", playerName)
-	fmt.Println(playerBoard.Print())
+// logLinkedCrosses logs the cells the ruleset crosses off along with the moves of the given turn, if there are any
+func logLinkedCrosses(logger *slog.Logger, playerName string, playerBoard board.Board, turn actions.ActivePlayerTurn) {
+	crossed := rule_checker.CrossedCells(playerBoard, turn)
+	linked := slices.Clone(crossed)
+	for _, move := range []*actions.Move{turn.WhiteDiceMove, turn.ColorDiceMove} {
+		if move == nil {
+			continue
+		}
+		if idx := slices.Index(linked, *move); idx >= 0 {
+			linked = slices.Delete(linked, idx, idx+1)
+		}
+	}
+	if len(linked) == 0 {
+		return
+	}
+	logger.Debug(
+		fmt.Sprintf("player %v also crossed off the linked cells %v", playerName, linked),
+		"linked", linked,
+		"ruleset", playerBoard.Ruleset().Name(),
+	)
 }
 
-func printPenalty(playerName string, penaltyCount int) {
-	fmt.Printf("player %v took a penalty (they have %v penalties)\n", playerName, penaltyCount)
+func logPlayerBoard(logger *slog.Logger, playerName string, playerBoard board.Board) {
+	logger.Debug(fmt.Sprintf("%v's board:", playerName), "board", playerBoard.Print())
 }
 
-func (gr *gameRunnerImpl) endGame() {
-	// TODO handle ties
-	var winnerID player.PlayerID
-	var highScore int
-	for playerID, playerBoard := range gr.boards {
-		score := playerBoard.CalculateScore()
-		if score > highScore {
-			winnerID = playerID
-		}
+func logPenalty(logger *slog.Logger, playerName string, penaltyCount int) {
+	logger.Debug(fmt.Sprintf("player %v took a penalty (they have %v penalties)", playerName, penaltyCount), "penalties", penaltyCount)
+}
+
+// endGame scores the game, tells every player whether they won and returns the result
+func (gr *gameRunnerImpl) endGame(playOrder []player.PlayerID, turnCount int, endReason EndReason) GameResult {
+	result := GameResult{
+		Turns:     turnCount,
+		EndReason: endReason,
+		Config:    gr.config,
+		Decisions: gr.decisions,
+	}
+	for _, playerID := range playOrder {
+		result.Players = append(result.Players, PlayerResult{
+			ID:        playerID,
+			Name:      gr.playersByID[playerID].GetName(),
+			Score:     gr.boards[playerID].CalculateScore() - gr.config.PenaltyValue*gr.penalties[playerID],
+			Penalties: gr.penalties[playerID],
+			Board:     board.StateOf(gr.boards[playerID]),
+		})
+	}
+	result.Winners = determineWinners(result.Players)
+	for idx := range result.Players {
+		result.Players[idx].Won = slices.Contains(result.Winners, result.Players[idx].ID)
 	}
+
 	for playerID, pl := range gr.playersByID {
-		if playerID == winnerID {
+		if slices.Contains(result.Winners, playerID) {
 			pl.InformWin()
 		} else {
-			pl.InformLoss(winnerID)
+			pl.InformLoss(result.Winners[0])
 		}
 	}
-	fmt.Println("GAME OVERRR")
+	gr.logger.Info("GAME OVERRR", "turns", turnCount, "end_reason", endReason, "winners", result.Winners)
+	return result
 }
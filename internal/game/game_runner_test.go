@@ -1,9 +1,21 @@
 package game
 
 import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
 	"qwixx/internal/game/actions"
 	"qwixx/internal/game/board"
 	"qwixx/internal/game/player"
+	"qwixx/internal/game/rule_checker"
+	"qwixx/internal/game/ruleset"
+	"qwixx/internal/logging"
+	"slices"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -18,6 +30,209 @@ func TestRunGame(t *testing.T) {
 	runner.RunGame()
 }
 
+func TestRunGame_Result(t *testing.T) {
+	playSeededGame := func(seed int64, options ...Option) GameResult {
+		players := []player.Player{
+			player.NewStrategyPlayer("alice", mustStrategy(t, player.StrategyGreedy), nil),
+			player.NewStrategyPlayer("bob", mustStrategy(t, player.StrategyCareful), nil),
+		}
+		runner := NewGameRunner(players, append([]Option{WithRand(rand.New(rand.NewSource(seed)))}, options...)...)
+		return runner.RunGame()
+	}
+
+	result := playSeededGame(1, WithDecisions())
+	require.Len(t, result.Players, 2)
+	require.NotEqual(t, EndReasonTurnLimit, result.EndReason)
+	require.Greater(t, result.Turns, 0)
+	require.NotEmpty(t, result.Winners)
+	require.NoError(t, result.Validate())
+	// every player decides once a turn, the active player first
+	require.Len(t, result.Decisions, result.Turns*len(result.Players))
+	require.True(t, result.Decisions[0].Active)
+	require.Equal(t, result.Players[0].ID, result.Decisions[0].PlayerID)
+	mostPenalties := 0
+	for _, playerResult := range result.Players {
+		require.Equal(t, playerResult.Won, slices.Contains(result.Winners, playerResult.ID))
+		mostPenalties = max(mostPenalties, playerResult.Penalties)
+	}
+	if result.EndReason == EndReasonPenalties {
+		require.Equal(t, 4, mostPenalties)
+	}
+
+	// the same seed plays out the same game, apart from the randomly generated player IDs,
+	// without recording the decisions unless asked to
+	replayed := playSeededGame(1)
+	require.Nil(t, replayed.Decisions)
+	require.Equal(t, result.Turns, replayed.Turns)
+	require.Equal(t, result.EndReason, replayed.EndReason)
+	for idx := range result.Players {
+		require.Equal(t, result.Players[idx].Name, replayed.Players[idx].Name)
+		require.Equal(t, result.Players[idx].Score, replayed.Players[idx].Score)
+	}
+}
+
+func TestRunGame_LogsWithGameIDAndTurn(t *testing.T) {
+	var out bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&out, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	players := []player.Player{
+		player.NewStrategyPlayer("alice", mustStrategy(t, player.StrategyGreedy), nil),
+		player.NewStrategyPlayer("bob", mustStrategy(t, player.StrategyGreedy), nil),
+	}
+	result := NewGameRunner(
+		players, WithLogger(logger), WithGameID("game-1"), WithRand(rand.New(rand.NewSource(3))),
+	).RunGame()
+
+	type record struct {
+		Level  string `json:"level"`
+		Msg    string `json:"msg"`
+		GameID string `json:"game_id"`
+		Turn   int    `json:"turn"`
+		Player string `json:"player"`
+	}
+	var records []record
+	decoder := json.NewDecoder(&out)
+	for decoder.More() {
+		var r record
+		require.NoError(t, decoder.Decode(&r))
+		records = append(records, r)
+	}
+
+	require.Equal(t, "INFO", records[0].Level)
+	require.Contains(t, records[0].Msg, "play order: ")
+	require.Equal(t, "GAME OVERRR", records[len(records)-1].Msg)
+	lastTurn := 0
+	for _, r := range records {
+		require.Equal(t, "game-1", r.GameID)
+		if strings.HasPrefix(r.Msg, "it's player") {
+			require.Equal(t, lastTurn+1, r.Turn)
+			require.Contains(t, []string{"alice", "bob"}, r.Player)
+			lastTurn = r.Turn
+		}
+	}
+	require.Equal(t, result.Turns, lastTurn)
+}
+
+func mustStrategy(t *testing.T, name string) player.Strategy {
+	t.Helper()
+	strategy, err := player.NewStrategy(name, nil)
+	require.NoError(t, err)
+	return strategy
+}
+
+func TestLockCompletedRows(t *testing.T) {
+	alice := player.NewStrategyPlayer("alice", mustStrategy(t, player.StrategyFirst), nil)
+	bob := player.NewStrategyPlayer("bob", mustStrategy(t, player.StrategyFirst), nil)
+	gr := NewGameRunner([]player.Player{alice, bob}).(*gameRunnerImpl)
+
+	aliceID, bobID := gr.seating[0], gr.seating[1]
+	lockedBlue, err := board.FromState(board.State{Rows: map[actions.RowColor][]int{
+		actions.RowColorBlue: {12, 11, 10, 9, 8, 2},
+	}})
+	require.NoError(t, err)
+	gr.boards[aliceID] = lockedBlue
+
+	gr.lockCompletedRows(gr.logger)
+	require.Equal(t, map[actions.RowColor]bool{actions.RowColorBlue: true}, gr.locks)
+	require.True(t, gr.boards[aliceID].IsRowLocked(actions.RowColorBlue))
+	require.True(t, gr.boards[bobID].IsRowLocked(actions.RowColorBlue))
+	require.False(t, gr.boards[bobID].IsRowLocked(actions.RowColorRed))
+
+	_, over := gr.endReason()
+	require.False(t, over)
+	gr.locks[actions.RowColorRed] = true
+	reason, over := gr.endReason()
+	require.True(t, over)
+	require.Equal(t, EndReasonRowsLocked, reason)
+}
+
+func TestRunGame_Variant(t *testing.T) {
+	for _, variant := range board.Variants() {
+		t.Run(string(variant), func(t *testing.T) {
+			players := []player.Player{
+				player.NewStrategyPlayer("alice", mustStrategy(t, player.StrategyGreedy), nil),
+				player.NewStrategyPlayer("bob", mustStrategy(t, player.StrategyFirst), nil),
+			}
+			gr := NewGameRunner(players, WithVariant(variant), WithRand(rand.New(rand.NewSource(1)))).(*gameRunnerImpl)
+			result := gr.RunGame()
+			require.NotEqual(t, EndReasonTurnLimit, result.EndReason)
+			require.NoError(t, result.Validate())
+			for _, playerBoard := range gr.boards {
+				require.Equal(t, variant, playerBoard.Variant())
+			}
+		})
+	}
+
+	gr := NewGameRunner([]player.Player{player.NewComputerPlayer("alice")}, WithVariant("qwinto")).(*gameRunnerImpl)
+	require.Equal(t, board.VariantClassic, gr.variant)
+}
+
+func TestRunGame_Ruleset(t *testing.T) {
+	for _, name := range ruleset.Names() {
+		t.Run(name, func(t *testing.T) {
+			rules, err := ruleset.New(name)
+			require.NoError(t, err)
+			players := []player.Player{
+				player.NewStrategyPlayer("alice", mustStrategy(t, player.StrategyGreedy), nil),
+				player.NewStrategyPlayer("bob", mustStrategy(t, player.StrategyFirst), nil),
+			}
+			gr := NewGameRunner(players, WithRuleset(rules), WithRand(rand.New(rand.NewSource(1)))).(*gameRunnerImpl)
+			result := gr.RunGame()
+			require.NotEqual(t, EndReasonTurnLimit, result.EndReason)
+			require.NoError(t, result.Validate())
+			for _, playerBoard := range gr.boards {
+				require.Equal(t, name, playerBoard.Ruleset().Name())
+			}
+		})
+	}
+}
+
+func TestLockCompletedRows_MixxNumbers(t *testing.T) {
+	alice := player.NewStrategyPlayer("alice", mustStrategy(t, player.StrategyFirst), nil)
+	bob := player.NewStrategyPlayer("bob", mustStrategy(t, player.StrategyFirst), nil)
+	gr := NewGameRunner([]player.Player{alice, bob}, WithVariant(board.VariantMixxNumbers)).(*gameRunnerImpl)
+
+	// the rightmost cell of the mixx numbers red row is 11 rather than 12
+	aliceID := gr.seating[0]
+	lockedRed, err := board.FromState(board.State{Variant: board.VariantMixxNumbers, Rows: map[actions.RowColor][]int{
+		actions.RowColorRed: {10, 6, 2, 8, 3, 11},
+	}})
+	require.NoError(t, err)
+	gr.boards[aliceID] = lockedRed
+
+	gr.lockCompletedRows(gr.logger)
+	require.Equal(t, map[actions.RowColor]bool{actions.RowColorRed: true}, gr.locks)
+}
+
+func TestDetermineWinners(t *testing.T) {
+	type testCase struct {
+		name            string
+		input           []PlayerResult
+		expectedWinners []player.PlayerID
+	}
+	testCases := []testCase{
+		{
+			name:            "highest score wins",
+			input:           []PlayerResult{{ID: "a", Score: 10}, {ID: "b", Score: 30}, {ID: "c", Score: 20}},
+			expectedWinners: []player.PlayerID{"b"},
+		},
+		{
+			name:            "ties share the win",
+			input:           []PlayerResult{{ID: "a", Score: 30}, {ID: "b", Score: 10}, {ID: "c", Score: 30}},
+			expectedWinners: []player.PlayerID{"a", "c"},
+		},
+		{
+			name:            "negative scores can win",
+			input:           []PlayerResult{{ID: "a", Score: -10}, {ID: "b", Score: -5}},
+			expectedWinners: []player.PlayerID{"b"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expectedWinners, determineWinners(tc.input))
+		})
+	}
+}
+
 func TestIsActiveTurnPenalty(t *testing.T) {
 	type testCase struct {
 		name           string
@@ -258,12 +473,262 @@ func TestIsActiveTurnValid(t *testing.T) {
 			require.Equal(
 				t,
 				tc.expectedOutput,
-				isActiveTurnValid(
+				rule_checker.ValidateActivePlayerTurn(
 					tc.inputBoard,
 					tc.inputDiceRoll,
 					tc.inputProposedTurn,
-				),
+				) == nil,
 			)
 		})
 	}
 }
+
+// stubbornPlayer proposes a turn that breaks the rules on its first attempt, and remembers why it was rejected
+type stubbornPlayer struct {
+	player.Player
+	attempts   int
+	rejections []error
+}
+
+func (s *stubbornPlayer) PromptActivePlayerTurn(playerBoard board.Board, diceRoll actions.DiceRoll) actions.ActivePlayerTurn {
+	s.attempts++
+	if s.attempts == 1 {
+		return actions.ActivePlayerTurn{ColorDiceMove: &actions.Move{RowColor: actions.RowColorRed, CellNumber: 13}}
+	}
+	return s.Player.PromptActivePlayerTurn(playerBoard, diceRoll)
+}
+
+func (s *stubbornPlayer) InformInvalidTurn(err error) {
+	s.rejections = append(s.rejections, err)
+}
+
+func TestPromptActivePlayerTurn_InformsOfRuleViolations(t *testing.T) {
+	pl := &stubbornPlayer{Player: player.NewComputerPlayer("stubborn")}
+	diceRoll := actions.DiceRoll{
+		WhiteDiceRoll: actions.WhiteDiceRoll{White1: 4, White2: 5},
+		ColorDiceRoll: actions.ColorDiceRoll{Red: 4, Yellow: 3, Green: 6, Blue: 2},
+	}
+
+	turn, _ := promptActivePlayerTurn(logging.Discard(), pl, board.NewGameBoard(), diceRoll, DefaultGameConfig())
+
+	require.NoError(t, rule_checker.ValidateActivePlayerTurn(board.NewGameBoard(), diceRoll, turn))
+	require.Equal(t, 2, pl.attempts)
+	require.Len(t, pl.rejections, 1)
+	require.ErrorIs(t, pl.rejections[0], board.ErrInvalidCellNumber)
+	require.Equal(t, board.CodeInvalidCellNumber, board.ViolationCode(pl.rejections[0]))
+}
+
+func TestPromptActivePlayerTurn_LogsBoardOfInvalidTurn(t *testing.T) {
+	var out bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&out, nil))
+	pl := &stubbornPlayer{Player: player.NewComputerPlayer("stubborn")}
+	playerBoard, _, err := board.Parse("R:2,3 B:12L")
+	require.NoError(t, err)
+	diceRoll := actions.DiceRoll{
+		WhiteDiceRoll: actions.WhiteDiceRoll{White1: 4, White2: 5},
+		ColorDiceRoll: actions.ColorDiceRoll{Red: 4, Yellow: 3, Green: 6, Blue: 2},
+	}
+
+	promptActivePlayerTurn(logger, pl, playerBoard, diceRoll, DefaultGameConfig())
+
+	var record struct {
+		Msg   string `json:"msg"`
+		Board string `json:"board"`
+	}
+	require.NoError(t, json.NewDecoder(&out).Decode(&record))
+	require.Contains(t, record.Msg, "played an invalid turn")
+	require.Equal(t, "R:2,3 Y:- G:- B:12L", record.Board)
+}
+
+// passingPlayer takes a penalty on every turn and never crosses off the white dice sum of other players' turns
+type passingPlayer struct {
+	player.Player
+}
+
+func (passingPlayer) PromptActivePlayerTurn(board.Board, actions.DiceRoll) actions.ActivePlayerTurn {
+	return actions.ActivePlayerTurn{}
+}
+
+func (passingPlayer) PromptInactivePlayerTurn(board.Board, actions.DiceRoll) actions.InactivePlayerTurn {
+	return actions.InactivePlayerTurn{}
+}
+
+func TestRunGame_Config(t *testing.T) {
+	players := []player.Player{
+		passingPlayer{player.NewComputerPlayer("alice")},
+		passingPlayer{player.NewComputerPlayer("bob")},
+	}
+
+	// the first player reaches two penalties on the third turn, each costing three points
+	config := GameConfig{PenaltiesToEnd: 2, PenaltyValue: 3}
+	result := NewGameRunner(players, WithConfig(config), WithRand(rand.New(rand.NewSource(1)))).RunGame()
+	require.Equal(t, EndReasonPenalties, result.EndReason)
+	require.Equal(t, 3, result.Turns)
+	require.Equal(t, config.WithDefaults(), result.Config)
+	require.Equal(t, -6, result.Players[0].Score)
+	require.Equal(t, -3, result.Players[1].Score)
+
+	result = NewGameRunner(players, WithConfig(GameConfig{PenaltiesToEnd: 5, MaxTurns: 2})).RunGame()
+	require.Equal(t, EndReasonTurnLimit, result.EndReason)
+	require.Equal(t, 2, result.Turns)
+
+	var out bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&out, nil))
+	gr := NewGameRunner(players, WithLogger(logger), WithConfig(GameConfig{PenaltyValue: -1})).(*gameRunnerImpl)
+	require.Equal(t, DefaultGameConfig(), gr.config)
+	require.Contains(t, out.String(), "playing the default house rules: penalty value must not be negative")
+	gr.RunGame()
+	require.Contains(t, out.String(), "house rules: game ends at 2 locked rows or 4 penalties")
+}
+
+// scriptedPlayer proposes the same active player turn every time
+type scriptedPlayer struct {
+	player.Player
+	turn actions.ActivePlayerTurn
+}
+
+func (s scriptedPlayer) PromptActivePlayerTurn(board.Board, actions.DiceRoll) actions.ActivePlayerTurn {
+	return s.turn
+}
+
+func TestPromptActivePlayerTurn_AnyDiceOrder(t *testing.T) {
+	diceRoll := actions.DiceRoll{
+		WhiteDiceRoll: actions.WhiteDiceRoll{White1: 4, White2: 5},
+		ColorDiceRoll: actions.ColorDiceRoll{Red: 4, Yellow: 3, Green: 6, Blue: 2},
+	}
+	// the red die crosses off red 8, which is left of the white dice sum red 9
+	colorFirstTurn := actions.ActivePlayerTurn{
+		WhiteDiceMove: &actions.Move{RowColor: actions.RowColorRed, CellNumber: 9},
+		ColorDiceMove: &actions.Move{RowColor: actions.RowColorRed, CellNumber: 8},
+	}
+	pl := scriptedPlayer{Player: player.NewComputerPlayer("alice"), turn: colorFirstTurn}
+
+	turn, colorFirst := promptActivePlayerTurn(logging.Discard(), pl, board.NewGameBoard(), diceRoll, DefaultGameConfig())
+	require.Equal(t, actions.ActivePlayerTurn{}, turn)
+	require.False(t, colorFirst)
+
+	turn, colorFirst = promptActivePlayerTurn(logging.Discard(), pl, board.NewGameBoard(), diceRoll, GameConfig{AnyDiceOrder: true})
+	require.Equal(t, colorFirstTurn, turn)
+	require.True(t, colorFirst)
+}
+
+// colorFirstPlayer crosses off a red cell with the red die and then one further right with the white dice whenever it can,
+// taking a penalty otherwise, and counts the turns it made that way
+type colorFirstPlayer struct {
+	player.Player
+	colorFirstTurns *int
+}
+
+func (c colorFirstPlayer) PromptActivePlayerTurn(playerBoard board.Board, diceRoll actions.DiceRoll) actions.ActivePlayerTurn {
+	whiteSum := diceRoll.White1 + diceRoll.White2
+	for _, white := range []int{diceRoll.White1, diceRoll.White2} {
+		turn := actions.ActivePlayerTurn{
+			WhiteDiceMove: &actions.Move{RowColor: actions.RowColorRed, CellNumber: whiteSum},
+			ColorDiceMove: &actions.Move{RowColor: actions.RowColorRed, CellNumber: white + diceRoll.Red},
+		}
+		if colorFirst, err := rule_checker.ValidateActivePlayerTurnInAnyOrder(playerBoard, diceRoll, turn); err == nil && colorFirst {
+			*c.colorFirstTurns++
+			return turn
+		}
+	}
+	return actions.ActivePlayerTurn{}
+}
+
+// opponentTracker keeps a copy of the board of each opponent from the moves it is told of
+type opponentTracker struct {
+	player.Player
+	boards map[player.PlayerID]board.Board
+	err    error
+}
+
+func (o *opponentTracker) InformOfOpponentMove(playerID player.PlayerID, move actions.Move) {
+	if _, ok := o.boards[playerID]; !ok {
+		o.boards[playerID] = board.NewGameBoard()
+	}
+	if err := o.boards[playerID].MakeMove(move); err != nil && o.err == nil {
+		o.err = err
+	}
+}
+
+func TestRunGame_ColorFirstTurnsInformOpponents(t *testing.T) {
+	colorFirstTurns := 0
+	alice := colorFirstPlayer{Player: player.NewComputerPlayer("alice"), colorFirstTurns: &colorFirstTurns}
+	bob := &opponentTracker{Player: player.NewComputerPlayer("bob"), boards: make(map[player.PlayerID]board.Board)}
+	result := NewGameRunner(
+		[]player.Player{alice, bob}, WithConfig(GameConfig{AnyDiceOrder: true}), WithRand(rand.New(rand.NewSource(1))),
+	).RunGame()
+
+	require.Positive(t, colorFirstTurns, "alice never crossed off cells color dice first")
+	require.NoError(t, bob.err)
+	for _, playerResult := range result.Players {
+		if playerResult.Name == "alice" {
+			require.Equal(t, playerResult.Board, board.StateOf(bob.boards[playerResult.ID]))
+		}
+	}
+}
+
+func TestGameResult_Validate(t *testing.T) {
+	players := []player.Player{
+		player.NewStrategyPlayer("alice", mustStrategy(t, player.StrategyGreedy), nil),
+		player.NewStrategyPlayer("bob", mustStrategy(t, player.StrategyFirst), nil),
+	}
+	result := NewGameRunner(players, WithRand(rand.New(rand.NewSource(2)))).RunGame()
+	require.NoError(t, result.Validate())
+
+	tampered := result
+	tampered.Players = slices.Clone(result.Players)
+	tampered.Players[0].Score += 10
+	tampered.Players[1].Penalties = 5
+	err := tampered.Validate()
+	require.ErrorContains(t, err, fmt.Sprintf("score of %v is", result.Players[0].Name))
+	require.ErrorIs(t, err, board.ErrTooManyPenalties)
+
+	// a row locked for one player is locked for everyone, and by someone who crossed off its rightmost cell
+	tampered = result
+	tampered.Players = slices.Clone(result.Players)
+	unlocked := actions.RowColorRed
+	for _, rowColor := range board.VariantClassic.RowColors() {
+		if !slices.Contains(result.Players[0].Board.Locked, rowColor) {
+			unlocked = rowColor
+		}
+	}
+	tampered.Players[0].Board.Locked = append(slices.Clone(result.Players[0].Board.Locked), unlocked)
+	err = tampered.Validate()
+	require.ErrorIs(t, err, board.ErrLockWithoutLastCell)
+	require.ErrorContains(t, err, fmt.Sprintf("row %v is locked for %v but not for %v", unlocked, result.Players[0].Name, result.Players[1].Name))
+}
+
+// TestRunGame_WebhookPlayerRuleset plays a game of the double rules with a webhook player,
+// whose copies of its opponents' boards must allow the cells crossed off twice
+func TestRunGame_WebhookPlayerRuleset(t *testing.T) {
+	strategy := mustStrategy(t, player.StrategyGreedy)
+	service := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var prompt player.WebhookPrompt
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&prompt))
+		playerBoard, err := board.FromState(prompt.Board)
+		require.NoError(t, err)
+		if prompt.Type == player.ExternalMessagePromptActive {
+			require.NoError(t, json.NewEncoder(w).Encode(strategy.ChooseActivePlayerTurn(playerBoard, prompt.DiceRoll)))
+			return
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(strategy.ChooseInactivePlayerTurn(playerBoard, prompt.DiceRoll)))
+	}))
+	t.Cleanup(service.Close)
+
+	webhook, err := player.NewWebhookPlayer(player.WebhookPlayerConfig{Name: "webhook", URL: service.URL, Ruleset: ruleset.Double()})
+	require.NoError(t, err)
+	players := []player.Player{webhook, player.NewStrategyPlayer("bob", strategy, nil)}
+	result := NewGameRunner(players, WithRuleset(ruleset.Double()), WithRand(rand.New(rand.NewSource(1)))).RunGame()
+	require.NoError(t, result.Validate())
+
+	crossedTwice := false
+	for _, playerResult := range result.Players {
+		for _, cellNumbers := range playerResult.Board.Rows {
+			sorted := slices.Clone(cellNumbers)
+			slices.Sort(sorted)
+			crossedTwice = crossedTwice || len(slices.Compact(sorted)) < len(cellNumbers)
+		}
+	}
+	require.True(t, crossedTwice, "no cell was crossed off twice")
+	require.NoError(t, webhook.Err())
+}
@@ -76,6 +76,11 @@ func (b BadActorPlayer) InformSuccessfulTurn(updatedBoard board.Board) {
 	panic("implement me")
 }
 
+func (b BadActorPlayer) InformInvalidTurn(err error) {
+	//TODO implement me
+	panic("implement me")
+}
+
 func (b BadActorPlayer) InformOfOpponentMove(playerID PlayerID, move actions.Move) {
 	//TODO implement me
 	panic("implement me")
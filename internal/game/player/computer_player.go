@@ -2,21 +2,65 @@ package player
 
 import (
 	"fmt"
+	"log/slog"
 	"qwixx/internal/game/actions"
 	"qwixx/internal/game/board"
 	"qwixx/internal/game/rule_checker"
+	"qwixx/internal/logging"
 )
 
 var _ Player = ComputerPlayer{}
+var _ PenaltyValueListener = &ComputerPlayer{}
+
+// PenaltyValueListener is implemented by players whose turns depend on what a penalty costs,
+// to be told so by clients that only learn the house rules of their game once it starts
+type PenaltyValueListener interface {
+	InformOfPenaltyValue(penaltyValue int)
+}
 
 type ComputerPlayer struct {
-	name string
-	ID   PlayerID
+	name     string
+	ID       PlayerID
+	strategy Strategy
+	logger   *slog.Logger
 }
 
+// NewComputerPlayer creates a silent computer player that crosses off the first legal cells it finds
 func NewComputerPlayer(name string) Player {
+	return NewStrategyPlayer(name, firstStrategy{}, nil)
+}
+
+// NewStrategyPlayer creates a computer player that plays with the given strategy and tells the given logger what it hears
+// about the game at the debug level. It is silent if the logger is nil.
+func NewStrategyPlayer(name string, strategy Strategy, logger *slog.Logger) Player {
+	if logger == nil {
+		logger = logging.Discard()
+	}
 	return &ComputerPlayer{
-		name: name,
+		name:     name,
+		strategy: strategy,
+		logger:   logger.With("player", name),
+	}
+}
+
+func (c ComputerPlayer) debug(msg string, args ...any) {
+	if c.logger == nil {
+		return
+	}
+	c.logger.Debug(msg, args...)
+}
+
+func (c ComputerPlayer) getStrategy() Strategy {
+	if c.strategy == nil {
+		return firstStrategy{}
+	}
+	return c.strategy
+}
+
+// InformOfPenaltyValue has the player's strategy weigh penalties by the given cost, if it weighs them at all
+func (c *ComputerPlayer) InformOfPenaltyValue(penaltyValue int) {
+	if weigher, ok := c.strategy.(penaltyWeigher); ok {
+		c.strategy = weigher.withPenaltyValue(penaltyValue)
 	}
 }
 
@@ -29,33 +73,28 @@ func (c ComputerPlayer) InformOfPlayOrder(playerNames []string) {
 	for idx, name := range playerNames {
 		playOrder += fmt.Sprintf("  %v: %v", idx+1, name)
 	}
-	fmt.Printf("play order is:\n%v\n", playOrder)
+	c.debug(fmt.Sprintf("play order is:\n%v", playOrder))
 }
 
 func (c ComputerPlayer) PromptActivePlayerTurn(
 	playerBoard board.Board,
 	diceRoll actions.DiceRoll,
 ) actions.ActivePlayerTurn {
-	return actions.ActivePlayerTurn{
-		WhiteDiceMove: pickFirstValidWhiteDiceMove(playerBoard, diceRoll),
-		ColorDiceMove: pickFirstValidColorDiceMove(playerBoard, diceRoll),
-	}
+	return c.getStrategy().ChooseActivePlayerTurn(playerBoard, diceRoll)
 }
 
 func (c ComputerPlayer) PromptInactivePlayerTurn(
 	playerBoard board.Board,
 	diceRoll actions.DiceRoll,
 ) actions.InactivePlayerTurn {
-	return actions.InactivePlayerTurn{
-		WhiteDiceMove: pickFirstValidWhiteDiceMove(playerBoard, diceRoll),
-	}
+	return c.getStrategy().ChooseInactivePlayerTurn(playerBoard, diceRoll)
 }
 
 func pickFirstValidWhiteDiceMove(
 	playerBoard board.Board,
 	diceRoll actions.DiceRoll,
 ) *actions.Move {
-	possibleWhiteDiceMoves := rule_checker.DeterminePossibleWhiteDiceMoves(diceRoll)
+	possibleWhiteDiceMoves := rule_checker.PossibleWhiteDiceMoves(playerBoard, diceRoll)
 	for _, move := range possibleWhiteDiceMoves {
 		if ok, _ := playerBoard.IsMoveValid(move); ok {
 			return &move
@@ -68,7 +107,7 @@ func pickFirstValidColorDiceMove(
 	playerBoard board.Board,
 	diceRoll actions.DiceRoll,
 ) *actions.Move {
-	possibleWhiteDiceMoves := rule_checker.DeterminePossibleColorDiceMoves(diceRoll)
+	possibleWhiteDiceMoves := rule_checker.PossibleColorDiceMoves(playerBoard, diceRoll)
 	for _, move := range possibleWhiteDiceMoves {
 		if ok, _ := playerBoard.IsMoveValid(move); ok {
 			return &move
@@ -81,15 +120,19 @@ func (c ComputerPlayer) InformSuccessfulTurn(updatedBoard board.Board) {
 
 }
 
+func (c ComputerPlayer) InformInvalidTurn(err error) {
+	c.debug(fmt.Sprintf("turn was rejected: %v", err), "error", err, "code", board.ViolationCode(err))
+}
+
 func (c ComputerPlayer) InformOfOpponentMove(playerID PlayerID, move actions.Move) {}
 
 func (c ComputerPlayer) InformRowLocked(color actions.RowColor) {
-	fmt.Printf("row %v was locked\n", color)
+	c.debug(fmt.Sprintf("row %v was locked", color), "row", color)
 }
 
 func (c ComputerPlayer) InformWin() {
-	fmt.Printf("PARTYYYY YOU WON\n")
+	c.debug("PARTYYYY YOU WON")
 }
 func (c ComputerPlayer) InformLoss(winnerID PlayerID) {
-	fmt.Printf("BOO YOU LOST :( %v won the game\n", winnerID)
+	c.debug(fmt.Sprintf("BOO YOU LOST :( %v won the game", winnerID), "winner_id", winnerID)
 }
@@ -0,0 +1,246 @@
+package player
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"qwixx/internal/game/actions"
+	"qwixx/internal/game/board"
+	"sync"
+	"time"
+)
+
+var _ Player = &ExternalPlayer{}
+
+// DefaultExternalPlayerTimeout is how long an external bot has to reply to a prompt if no timeout is configured
+const DefaultExternalPlayerTimeout = 5 * time.Second
+
+// invalidMove returns a move that no board accepts.
+// It is returned in place of a bot's reply when the bot times out or replies with something malformed,
+// so the game runner treats the turn as invalid and handles it like any other invalid turn.
+func invalidMove() *actions.Move {
+	return &actions.Move{RowColor: -1, CellNumber: -1}
+}
+
+// ExternalPlayerConfig describes how to launch an external bot
+type ExternalPlayerConfig struct {
+	// Name is the name of the player in the game
+	Name string
+	// Command is the executable to run, followed by its arguments
+	Command []string
+	// Timeout is how long the bot has to reply to each prompt, DefaultExternalPlayerTimeout if zero
+	Timeout time.Duration
+	// Stderr receives the bot's diagnostic output, os.Stderr if nil
+	Stderr io.Writer
+}
+
+// ExternalPlayer is a player whose decisions are made by a subprocess speaking the external bot protocol
+// over its stdin and stdout. See ExternalMessage for the messages it is sent.
+type ExternalPlayer struct {
+	name    string
+	timeout time.Duration
+
+	cmd     *exec.Cmd
+	stdin   io.WriteCloser
+	encoder *json.Encoder
+	replies chan ExternalReply
+
+	mu       sync.Mutex
+	promptID int
+	lastErr  error
+}
+
+// NewExternalPlayer launches the bot described by the given config.
+// The returned player must be closed once the game is over to stop the bot.
+func NewExternalPlayer(config ExternalPlayerConfig) (*ExternalPlayer, error) {
+	if len(config.Command) == 0 {
+		return nil, errors.New("external player needs a command to run")
+	}
+	timeout := config.Timeout
+	if timeout <= 0 {
+		timeout = DefaultExternalPlayerTimeout
+	}
+	stderr := config.Stderr
+	if stderr == nil {
+		stderr = os.Stderr
+	}
+
+	cmd := exec.Command(config.Command[0], config.Command[1:]...)
+	cmd.Stderr = stderr
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("starting external player %v: %w", config.Name, err)
+	}
+
+	e := &ExternalPlayer{
+		name:    config.Name,
+		timeout: timeout,
+		cmd:     cmd,
+		stdin:   stdin,
+		encoder: json.NewEncoder(stdin),
+		replies: make(chan ExternalReply),
+	}
+	go e.readReplies(stdout)
+	return e, nil
+}
+
+// readReplies reads every line the bot writes to stdout until it exits.
+// Lines that are not valid replies are recorded as errors and answer the pending prompt with an invalid turn.
+func (e *ExternalPlayer) readReplies(stdout io.Reader) {
+	defer close(e.replies)
+	scanner := bufio.NewScanner(stdout)
+	for scanner.Scan() {
+		var reply ExternalReply
+		if err := json.Unmarshal(scanner.Bytes(), &reply); err != nil {
+			e.recordError(fmt.Errorf("malformed reply %q: %w", scanner.Text(), err))
+			reply = ExternalReply{ID: e.currentPromptID(), WhiteDiceMove: invalidMove()}
+		}
+		e.replies <- reply
+	}
+}
+
+// Close stops the bot, waiting for it to exit
+func (e *ExternalPlayer) Close() error {
+	// drain any replies nobody is waiting for so the reader can reach the end of the bot's output
+	go func() {
+		for range e.replies {
+		}
+	}()
+	_ = e.stdin.Close()
+	done := make(chan error, 1)
+	go func() { done <- e.cmd.Wait() }()
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(e.timeout):
+		_ = e.cmd.Process.Kill()
+		return <-done
+	}
+}
+
+// Err returns the last problem encountered talking to the bot, if any
+func (e *ExternalPlayer) Err() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.lastErr
+}
+
+func (e *ExternalPlayer) recordError(err error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.lastErr = err
+}
+
+func (e *ExternalPlayer) currentPromptID() int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.promptID
+}
+
+func (e *ExternalPlayer) send(message ExternalMessage) {
+	if err := e.encoder.Encode(message); err != nil {
+		e.recordError(fmt.Errorf("sending %v: %w", message.Type, err))
+	}
+}
+
+// prompt sends the given prompt and waits for the bot's reply to it.
+// Replies to earlier prompts that arrive late are discarded.
+func (e *ExternalPlayer) prompt(message ExternalMessage) (ExternalReply, error) {
+	e.mu.Lock()
+	e.promptID++
+	message.ID = e.promptID
+	e.mu.Unlock()
+
+	e.send(message)
+	deadline := time.After(e.timeout)
+	for {
+		select {
+		case reply, ok := <-e.replies:
+			if !ok {
+				return ExternalReply{}, errors.New("external player exited")
+			}
+			if reply.ID == message.ID {
+				return reply, nil
+			}
+		case <-deadline:
+			return ExternalReply{}, fmt.Errorf("no reply to prompt %v within %v", message.ID, e.timeout)
+		}
+	}
+}
+
+func (e *ExternalPlayer) GetName() string {
+	return e.name
+}
+
+func (e *ExternalPlayer) InformOfPlayOrder(playerNames []string) {
+	e.send(ExternalMessage{Type: ExternalMessagePlayOrder, PlayerNames: playerNames})
+}
+
+func (e *ExternalPlayer) PromptActivePlayerTurn(
+	playerBoard board.Board,
+	diceRoll actions.DiceRoll,
+) actions.ActivePlayerTurn {
+	state := board.StateOf(playerBoard)
+	reply, err := e.prompt(ExternalMessage{Type: ExternalMessagePromptActive, Board: &state, DiceRoll: &diceRoll})
+	if err != nil {
+		e.recordError(err)
+		return actions.ActivePlayerTurn{WhiteDiceMove: invalidMove()}
+	}
+	return actions.ActivePlayerTurn{WhiteDiceMove: reply.WhiteDiceMove, ColorDiceMove: reply.ColorDiceMove}
+}
+
+func (e *ExternalPlayer) PromptInactivePlayerTurn(
+	playerBoard board.Board,
+	diceRoll actions.DiceRoll,
+) actions.InactivePlayerTurn {
+	state := board.StateOf(playerBoard)
+	reply, err := e.prompt(ExternalMessage{Type: ExternalMessagePromptInactive, Board: &state, DiceRoll: &diceRoll})
+	if err != nil {
+		e.recordError(err)
+		return actions.InactivePlayerTurn{WhiteDiceMove: invalidMove()}
+	}
+	if reply.ColorDiceMove != nil {
+		// inactive players cannot use the color dice, so a reply containing a color dice move is invalid
+		return actions.InactivePlayerTurn{WhiteDiceMove: invalidMove()}
+	}
+	return actions.InactivePlayerTurn{WhiteDiceMove: reply.WhiteDiceMove}
+}
+
+func (e *ExternalPlayer) InformSuccessfulTurn(updatedBoard board.Board) {
+	state := board.StateOf(updatedBoard)
+	e.send(ExternalMessage{Type: ExternalMessageSuccessfulTurn, Board: &state})
+}
+
+func (e *ExternalPlayer) InformInvalidTurn(err error) {
+	rejection := board.ViolationOf(err)
+	e.send(ExternalMessage{Type: ExternalMessageInvalidTurn, Rejection: &rejection})
+}
+
+func (e *ExternalPlayer) InformOfOpponentMove(playerID PlayerID, move actions.Move) {
+	e.send(ExternalMessage{Type: ExternalMessageOpponentMove, PlayerID: playerID, Move: &move})
+}
+
+func (e *ExternalPlayer) InformRowLocked(color actions.RowColor) {
+	e.send(ExternalMessage{Type: ExternalMessageRowLocked, RowColor: &color})
+}
+
+func (e *ExternalPlayer) InformWin() {
+	won := true
+	e.send(ExternalMessage{Type: ExternalMessageResult, Won: &won})
+}
+
+func (e *ExternalPlayer) InformLoss(winnerID PlayerID) {
+	won := false
+	e.send(ExternalMessage{Type: ExternalMessageResult, Won: &won, WinnerID: winnerID})
+}
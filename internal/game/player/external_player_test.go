@@ -0,0 +1,250 @@
+package player
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"qwixx/internal/game/actions"
+	"qwixx/internal/game/board"
+	"qwixx/internal/game/rule_checker"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// conformanceBotEnv names an environment variable holding the command of a bot to run the conformance test against,
+// e.g. QWIXX_CONFORMANCE_BOT="python3 my_bot.py". The reference bot is used when it is not set.
+const conformanceBotEnv = "QWIXX_CONFORMANCE_BOT"
+
+// helperBotEnv is set when the test binary is re-run as a misbehaving bot by TestExternalBotHelperProcess
+const helperBotEnv = "QWIXX_EXTERNAL_BOT_HELPER"
+
+var testDiceRoll = actions.DiceRoll{
+	WhiteDiceRoll: actions.WhiteDiceRoll{
+		White1: 4,
+		White2: 5,
+	},
+	ColorDiceRoll: actions.ColorDiceRoll{
+		Red:    4,
+		Yellow: 3,
+		Green:  6,
+		Blue:   2,
+	},
+}
+
+var (
+	referenceBotOnce sync.Once
+	referenceBotDir  string
+	referenceBotPath string
+	referenceBotErr  error
+)
+
+func TestMain(m *testing.M) {
+	code := m.Run()
+	if referenceBotDir != "" {
+		_ = os.RemoveAll(referenceBotDir)
+	}
+	os.Exit(code)
+}
+
+// buildReferenceBot builds cmd/qwixx-reference-bot once per test run, returning the path of the binary
+func buildReferenceBot(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go toolchain not available to build the reference bot")
+	}
+	referenceBotOnce.Do(func() {
+		referenceBotDir, referenceBotErr = os.MkdirTemp("", "qwixx-reference-bot")
+		if referenceBotErr != nil {
+			return
+		}
+		referenceBotPath = filepath.Join(referenceBotDir, "qwixx-reference-bot")
+		output, err := exec.Command("go", "build", "-o", referenceBotPath, "qwixx/cmd/qwixx-reference-bot").CombinedOutput()
+		if err != nil {
+			referenceBotErr = fmt.Errorf("%w: %s", err, output)
+		}
+	})
+	require.NoError(t, referenceBotErr)
+	return referenceBotPath
+}
+
+// helperBotCommand returns a command re-running this test binary as a bot with the given behavior
+func helperBotCommand(behavior string) []string {
+	return []string{os.Args[0], "-test.run=TestExternalBotHelperProcess", "--", behavior}
+}
+
+// TestExternalBotHelperProcess is not a real test, it is run as a subprocess by the tests below to act as a bot
+func TestExternalBotHelperProcess(t *testing.T) {
+	if os.Getenv(helperBotEnv) != "1" {
+		return
+	}
+	behavior := os.Args[len(os.Args)-1]
+	switch behavior {
+	case "silent":
+		_, _ = io.Copy(io.Discard, os.Stdin)
+	case "garbage":
+		scanner := bufio.NewScanner(os.Stdin)
+		for scanner.Scan() {
+			fmt.Println("this is not json")
+		}
+	case "slow-first":
+		_ = ServeExternalBot(os.Stdin, os.Stdout, &slowFirstPromptPlayer{delay: 300 * time.Millisecond})
+	}
+	os.Exit(0)
+}
+
+// slowFirstPromptPlayer passes on every prompt, but takes a long time to answer the first one
+type slowFirstPromptPlayer struct {
+	ComputerPlayer
+	delay    time.Duration
+	answered bool
+}
+
+func (s *slowFirstPromptPlayer) PromptInactivePlayerTurn(board.Board, actions.DiceRoll) actions.InactivePlayerTurn {
+	if !s.answered {
+		s.answered = true
+		time.Sleep(s.delay)
+	}
+	return actions.InactivePlayerTurn{}
+}
+
+func newTestExternalPlayer(t *testing.T, command []string, timeout time.Duration) *ExternalPlayer {
+	t.Helper()
+	t.Setenv(helperBotEnv, "1")
+	p, err := NewExternalPlayer(ExternalPlayerConfig{
+		Name:    "external",
+		Command: command,
+		Timeout: timeout,
+		Stderr:  io.Discard,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = p.Close() })
+	return p
+}
+
+// TestExternalBotConformance feeds every message from testdata/external_bot_conformance.jsonl to a bot,
+// checking that it answers every prompt, and only prompts, with a legal turn
+func TestExternalBotConformance(t *testing.T) {
+	command := strings.Fields(os.Getenv(conformanceBotEnv))
+	if len(command) == 0 {
+		command = []string{buildReferenceBot(t)}
+	}
+	fixture, err := os.ReadFile(filepath.Join("testdata", "external_bot_conformance.jsonl"))
+	require.NoError(t, err)
+
+	cmd := exec.Command(command[0], command[1:]...)
+	stdin, err := cmd.StdinPipe()
+	require.NoError(t, err)
+	stdout, err := cmd.StdoutPipe()
+	require.NoError(t, err)
+	require.NoError(t, cmd.Start())
+	t.Cleanup(func() { _ = cmd.Process.Kill(); _ = cmd.Wait() })
+
+	replies := make(chan ExternalReply)
+	go func() {
+		defer close(replies)
+		scanner := bufio.NewScanner(stdout)
+		for scanner.Scan() {
+			var reply ExternalReply
+			if err := json.Unmarshal(scanner.Bytes(), &reply); err != nil {
+				t.Errorf("malformed reply %q: %v", scanner.Text(), err)
+				continue
+			}
+			replies <- reply
+		}
+	}()
+
+	for _, line := range strings.Split(strings.TrimSpace(string(fixture)), "\n") {
+		var message ExternalMessage
+		require.NoError(t, json.Unmarshal([]byte(line), &message))
+		_, err := fmt.Fprintln(stdin, line)
+		require.NoError(t, err)
+
+		if message.Type != ExternalMessagePromptActive && message.Type != ExternalMessagePromptInactive {
+			continue
+		}
+		var reply ExternalReply
+		select {
+		case reply = <-replies:
+		case <-time.After(DefaultExternalPlayerTimeout):
+			t.Fatalf("no reply to prompt %v", message.ID)
+		}
+		require.Equal(t, message.ID, reply.ID, "reply must echo the prompt ID")
+
+		promptBoard, err := board.FromState(*message.Board)
+		require.NoError(t, err)
+		if message.Type == ExternalMessagePromptInactive {
+			require.Nil(t, reply.ColorDiceMove, "prompt %v: inactive players cannot use the color dice", message.ID)
+		}
+		if reply.WhiteDiceMove != nil {
+			require.True(t,
+				rule_checker.WhiteDiceMoveIsValidForBoard(promptBoard, *message.DiceRoll, *reply.WhiteDiceMove),
+				"prompt %v: illegal white dice move %v", message.ID, reply.WhiteDiceMove,
+			)
+			require.NoError(t, promptBoard.MakeMove(*reply.WhiteDiceMove))
+		}
+		if reply.ColorDiceMove != nil {
+			require.True(t,
+				rule_checker.ColorDiceMoveIsValidForBoard(promptBoard, *message.DiceRoll, *reply.ColorDiceMove),
+				"prompt %v: illegal color dice move %v", message.ID, reply.ColorDiceMove,
+			)
+		}
+	}
+
+	require.NoError(t, stdin.Close())
+	_, open := <-replies
+	require.False(t, open, "bot replied to a message that was not a prompt")
+	require.NoError(t, cmd.Wait())
+}
+
+func TestExternalPlayer_ReferenceBotTurns(t *testing.T) {
+	p := newTestExternalPlayer(t, []string{buildReferenceBot(t)}, DefaultExternalPlayerTimeout)
+	p.InformOfPlayOrder([]string{"external", "alice"})
+
+	activeTurn := p.PromptActivePlayerTurn(board.NewGameBoard(), testDiceRoll)
+	require.Equal(t, actions.ActivePlayerTurn{
+		WhiteDiceMove: &actions.Move{RowColor: actions.RowColorRed, CellNumber: 9},
+		ColorDiceMove: &actions.Move{RowColor: actions.RowColorYellow, CellNumber: 7},
+	}, activeTurn)
+
+	inactiveTurn := p.PromptInactivePlayerTurn(board.NewGameBoard(), testDiceRoll)
+	require.Equal(t, actions.InactivePlayerTurn{
+		WhiteDiceMove: &actions.Move{RowColor: actions.RowColorRed, CellNumber: 9},
+	}, inactiveTurn)
+	require.NoError(t, p.Err())
+}
+
+func TestExternalPlayer_Timeout(t *testing.T) {
+	p := newTestExternalPlayer(t, helperBotCommand("silent"), 100*time.Millisecond)
+
+	activeTurn := p.PromptActivePlayerTurn(board.NewGameBoard(), testDiceRoll)
+	require.Equal(t, invalidMove(), activeTurn.WhiteDiceMove)
+	require.ErrorContains(t, p.Err(), "no reply to prompt 1")
+}
+
+func TestExternalPlayer_MalformedReply(t *testing.T) {
+	p := newTestExternalPlayer(t, helperBotCommand("garbage"), DefaultExternalPlayerTimeout)
+
+	inactiveTurn := p.PromptInactivePlayerTurn(board.NewGameBoard(), testDiceRoll)
+	require.Equal(t, invalidMove(), inactiveTurn.WhiteDiceMove)
+	require.ErrorContains(t, p.Err(), "malformed reply")
+}
+
+func TestExternalPlayer_LateReplyIsDiscarded(t *testing.T) {
+	p := newTestExternalPlayer(t, helperBotCommand("slow-first"), 100*time.Millisecond)
+
+	firstTurn := p.PromptInactivePlayerTurn(board.NewGameBoard(), testDiceRoll)
+	require.Equal(t, invalidMove(), firstTurn.WhiteDiceMove)
+
+	// the reply to the first prompt arrives while waiting for the second, and must not be taken as its answer
+	time.Sleep(250 * time.Millisecond)
+	secondTurn := p.PromptInactivePlayerTurn(board.NewGameBoard(), testDiceRoll)
+	require.Equal(t, actions.InactivePlayerTurn{}, secondTurn)
+}
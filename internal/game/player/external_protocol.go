@@ -0,0 +1,146 @@
+package player
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"qwixx/internal/game/actions"
+	"qwixx/internal/game/board"
+)
+
+// The external bot protocol lets a bot written in any language play Qwixx as a subprocess.
+// The game sends one JSON object per line to the bot's stdin, and the bot answers every prompt
+// with one JSON object per line on its stdout. Informational messages are not answered.
+//
+// Every prompt carries an ID which must be echoed back in the reply, so that a late reply to a prompt
+// that already timed out is never mistaken for the reply to a later prompt.
+// Bots should write any diagnostics to stderr, since everything on stdout is read as a reply.
+
+// ExternalMessageType identifies the kind of a message sent to an external bot
+type ExternalMessageType string
+
+const (
+	// ExternalMessagePlayOrder tells the bot the names of all players in the order they will take turns
+	ExternalMessagePlayOrder ExternalMessageType = "play_order"
+	// ExternalMessagePromptActive asks the bot for its ActivePlayerTurn, and expects a reply
+	ExternalMessagePromptActive ExternalMessageType = "prompt_active"
+	// ExternalMessagePromptInactive asks the bot for its InactivePlayerTurn, and expects a reply
+	ExternalMessagePromptInactive ExternalMessageType = "prompt_inactive"
+	// ExternalMessageSuccessfulTurn tells the bot its board after one of its turns was applied
+	ExternalMessageSuccessfulTurn ExternalMessageType = "successful_turn"
+	// ExternalMessageInvalidTurn tells the bot why its reply to the last prompt was rejected, before it is prompted again
+	ExternalMessageInvalidTurn ExternalMessageType = "invalid_turn"
+	// ExternalMessageOpponentMove tells the bot about a move made by another player
+	ExternalMessageOpponentMove ExternalMessageType = "opponent_move"
+	// ExternalMessageRowLocked tells the bot a row has been locked for all players
+	ExternalMessageRowLocked ExternalMessageType = "row_locked"
+	// ExternalMessageResult tells the bot whether it won or lost the game
+	ExternalMessageResult ExternalMessageType = "result"
+)
+
+// ExternalMessage is a single message sent from the game to an external bot.
+// Only the fields relevant to the message's type are set.
+type ExternalMessage struct {
+	Type        ExternalMessageType `json:"type"`
+	ID          int                 `json:"id,omitempty"`
+	PlayerNames []string            `json:"player_names,omitempty"`
+	Board       *board.State        `json:"board,omitempty"`
+	DiceRoll    *actions.DiceRoll   `json:"dice,omitempty"`
+	PlayerID    PlayerID            `json:"player_id,omitempty"`
+	Move        *actions.Move       `json:"move,omitempty"`
+	RowColor    *actions.RowColor   `json:"row_color,omitempty"`
+	Won         *bool               `json:"won,omitempty"`
+	WinnerID    PlayerID            `json:"winner_id,omitempty"`
+	// Rejection has the code and description of the rule an invalid turn broke
+	Rejection *board.RuleViolation `json:"rejection,omitempty"`
+}
+
+// ExternalReply is a bot's answer to a prompt.
+// A reply to an active prompt may set both moves, while a reply to an inactive prompt may only set the white dice move.
+// Leaving out both moves means taking a penalty as the active player, or passing as an inactive player.
+type ExternalReply struct {
+	ID            int           `json:"id"`
+	WhiteDiceMove *actions.Move `json:"white_dice_move"`
+	ColorDiceMove *actions.Move `json:"color_dice_move"`
+}
+
+// ServeExternalBot runs the bot side of the external bot protocol, reading messages from in,
+// dispatching them to the given player, and writing its replies to out.
+// It returns when in is exhausted or a message cannot be handled.
+func ServeExternalBot(in io.Reader, out io.Writer, p Player) error {
+	scanner := bufio.NewScanner(in)
+	encoder := json.NewEncoder(out)
+	for scanner.Scan() {
+		var message ExternalMessage
+		if err := json.Unmarshal(scanner.Bytes(), &message); err != nil {
+			return fmt.Errorf("malformed message: %w", err)
+		}
+		reply, err := handleExternalMessage(p, message)
+		if err != nil {
+			return err
+		}
+		if reply == nil {
+			continue
+		}
+		if err := encoder.Encode(reply); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// handleExternalMessage passes the given message on to the player, returning the reply to send if it was a prompt
+func handleExternalMessage(p Player, message ExternalMessage) (*ExternalReply, error) {
+	switch message.Type {
+	case ExternalMessagePlayOrder:
+		p.InformOfPlayOrder(message.PlayerNames)
+	case ExternalMessagePromptActive, ExternalMessagePromptInactive:
+		if message.Board == nil || message.DiceRoll == nil {
+			return nil, fmt.Errorf("prompt %v is missing its board or dice", message.ID)
+		}
+		playerBoard, err := board.FromState(*message.Board)
+		if err != nil {
+			return nil, err
+		}
+		if message.Type == ExternalMessagePromptInactive {
+			turn := p.PromptInactivePlayerTurn(playerBoard, *message.DiceRoll)
+			return &ExternalReply{ID: message.ID, WhiteDiceMove: turn.WhiteDiceMove}, nil
+		}
+		turn := p.PromptActivePlayerTurn(playerBoard, *message.DiceRoll)
+		return &ExternalReply{ID: message.ID, WhiteDiceMove: turn.WhiteDiceMove, ColorDiceMove: turn.ColorDiceMove}, nil
+	case ExternalMessageSuccessfulTurn:
+		if message.Board == nil {
+			return nil, fmt.Errorf("successful turn is missing its board")
+		}
+		updatedBoard, err := board.FromState(*message.Board)
+		if err != nil {
+			return nil, err
+		}
+		p.InformSuccessfulTurn(updatedBoard)
+	case ExternalMessageInvalidTurn:
+		if message.Rejection == nil {
+			return nil, fmt.Errorf("invalid turn is missing its rejection")
+		}
+		p.InformInvalidTurn(message.Rejection)
+	case ExternalMessageOpponentMove:
+		if message.Move == nil {
+			return nil, fmt.Errorf("opponent move is missing its move")
+		}
+		p.InformOfOpponentMove(message.PlayerID, *message.Move)
+	case ExternalMessageRowLocked:
+		if message.RowColor == nil {
+			return nil, fmt.Errorf("row locked is missing its row color")
+		}
+		p.InformRowLocked(*message.RowColor)
+	case ExternalMessageResult:
+		if message.Won != nil && *message.Won {
+			p.InformWin()
+		} else {
+			p.InformLoss(message.WinnerID)
+		}
+	default:
+		// unknown messages are ignored so that bots keep working when new informational messages are added
+	}
+	return nil, nil
+}
@@ -13,6 +13,9 @@ type Player interface {
 	PromptActivePlayerTurn(playerBoard board.Board, diceRoll actions.DiceRoll) actions.ActivePlayerTurn
 	PromptInactivePlayerTurn(playerBoard board.Board, diceRoll actions.DiceRoll) actions.InactivePlayerTurn
 	InformSuccessfulTurn(updatedBoard board.Board)
+	// InformInvalidTurn tells the player why the turn it just proposed was rejected before it is prompted again.
+	// The error wraps a *board.RuleViolation, whose code says which rule was broken.
+	InformInvalidTurn(err error)
 	InformOfOpponentMove(playerID PlayerID, move actions.Move)
 	InformRowLocked(color actions.RowColor)
 	InformWin()
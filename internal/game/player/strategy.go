@@ -0,0 +1,218 @@
+package player
+
+import (
+	"fmt"
+	"math/rand"
+	"qwixx/internal/analysis"
+	"qwixx/internal/game/actions"
+	"qwixx/internal/game/board"
+	"qwixx/internal/game/rule_checker"
+	"strings"
+	"time"
+)
+
+// Strategy decides the turns of a computer player
+type Strategy interface {
+	ChooseActivePlayerTurn(playerBoard board.Board, diceRoll actions.DiceRoll) actions.ActivePlayerTurn
+	ChooseInactivePlayerTurn(playerBoard board.Board, diceRoll actions.DiceRoll) actions.InactivePlayerTurn
+}
+
+const (
+	// StrategyFirst crosses off the first legal cell it finds for each die, however many cells that skips
+	StrategyFirst = "first"
+	// StrategyRandom picks uniformly between the legal turns, only taking a penalty as the active player if it has to
+	StrategyRandom = "random"
+	// StrategyGreedy crosses off as many cells as it can, preferring the moves that skip the fewest cells
+	StrategyGreedy = "greedy"
+	// StrategyCareful only crosses off cells that skip at most one other cell,
+	// falling back to the least wasteful move as the active player rather than taking a penalty
+	StrategyCareful = "careful"
+	// StrategyHard plays the turn the analysis package recommends, weighing the points each move gains
+	// against the chances of the cells it skips coming up later
+	StrategyHard = "hard"
+)
+
+// StrategyNames lists the names of the strategies NewStrategy knows
+func StrategyNames() []string {
+	return []string{StrategyFirst, StrategyRandom, StrategyGreedy, StrategyCareful, StrategyHard}
+}
+
+// defaultPenaltyValue is what a penalty costs by the original rules
+const defaultPenaltyValue = 5
+
+// StrategyOption configures a strategy created by NewStrategy
+type StrategyOption func(*strategyOptions)
+
+type strategyOptions struct {
+	penaltyValue int
+}
+
+// WithPenaltyValue tells the strategies weighing penalties against moves what a penalty costs in their game,
+// if its house rules change it from the 5 points of the original rules
+func WithPenaltyValue(penaltyValue int) StrategyOption {
+	return func(o *strategyOptions) {
+		o.penaltyValue = penaltyValue
+	}
+}
+
+// penaltyWeigher is implemented by strategies whose turns depend on what a penalty costs
+type penaltyWeigher interface {
+	withPenaltyValue(penaltyValue int) Strategy
+}
+
+// NewStrategy creates the strategy with the given name, using the given source of randomness if the strategy needs one,
+// or a source seeded from the clock if it is nil
+func NewStrategy(name string, rng *rand.Rand, options ...StrategyOption) (Strategy, error) {
+	if rng == nil {
+		rng = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+	strategyOptions := strategyOptions{penaltyValue: defaultPenaltyValue}
+	for _, option := range options {
+		option(&strategyOptions)
+	}
+	switch strings.ToLower(name) {
+	case StrategyFirst:
+		return firstStrategy{}, nil
+	case StrategyRandom:
+		return randomStrategy{rng: rng}, nil
+	case StrategyGreedy:
+		return greedyStrategy{maxSkipped: 10}, nil
+	case StrategyCareful:
+		return greedyStrategy{maxSkipped: 1}, nil
+	case StrategyHard:
+		return hardStrategy{penaltyValue: strategyOptions.penaltyValue}, nil
+	default:
+		return nil, fmt.Errorf("unknown strategy %q, expected one of %v", name, strings.Join(StrategyNames(), ", "))
+	}
+}
+
+// firstStrategy is the original computer player behavior, taking the first legal move in the order the rule checker lists them
+type firstStrategy struct{}
+
+func (firstStrategy) ChooseActivePlayerTurn(playerBoard board.Board, diceRoll actions.DiceRoll) actions.ActivePlayerTurn {
+	whiteDiceMove := pickFirstValidWhiteDiceMove(playerBoard, diceRoll)
+	afterWhite := playerBoard.Copy()
+	if whiteDiceMove != nil {
+		_ = afterWhite.MakeMove(*whiteDiceMove)
+	}
+	return actions.ActivePlayerTurn{
+		WhiteDiceMove: whiteDiceMove,
+		ColorDiceMove: pickFirstValidColorDiceMove(afterWhite, diceRoll),
+	}
+}
+
+func (firstStrategy) ChooseInactivePlayerTurn(playerBoard board.Board, diceRoll actions.DiceRoll) actions.InactivePlayerTurn {
+	return actions.InactivePlayerTurn{
+		WhiteDiceMove: pickFirstValidWhiteDiceMove(playerBoard, diceRoll),
+	}
+}
+
+type randomStrategy struct {
+	rng *rand.Rand
+}
+
+func (s randomStrategy) ChooseActivePlayerTurn(playerBoard board.Board, diceRoll actions.DiceRoll) actions.ActivePlayerTurn {
+	turns := rule_checker.LegalActiveTurns(playerBoard, diceRoll)
+	// the penalty is always the last legal turn, only take it if there is nothing else
+	if len(turns) == 1 {
+		return turns[0]
+	}
+	return turns[s.rng.Intn(len(turns)-1)]
+}
+
+func (s randomStrategy) ChooseInactivePlayerTurn(playerBoard board.Board, diceRoll actions.DiceRoll) actions.InactivePlayerTurn {
+	turns := rule_checker.LegalInactiveTurns(playerBoard, diceRoll)
+	return turns[s.rng.Intn(len(turns))]
+}
+
+// greedyStrategy crosses off every cell that skips at most maxSkipped empty cells, preferring the least wasteful moves
+type greedyStrategy struct {
+	maxSkipped int
+}
+
+func (s greedyStrategy) ChooseActivePlayerTurn(playerBoard board.Board, diceRoll actions.DiceRoll) actions.ActivePlayerTurn {
+	var best actions.ActivePlayerTurn
+	bestCrosses, bestSkipped := 0, 0
+	// fallback is the single move skipping the fewest cells, in case every move skips too many
+	var fallback actions.ActivePlayerTurn
+	fallbackSkipped := -1
+
+	for _, turn := range rule_checker.LegalActiveTurns(playerBoard, diceRoll) {
+		crosses, skipped := 0, 0
+		withinLimit := true
+		afterWhite := playerBoard.Copy()
+		if turn.WhiteDiceMove != nil {
+			whiteSkipped := SkippedCells(afterWhite, *turn.WhiteDiceMove)
+			_ = afterWhite.MakeMove(*turn.WhiteDiceMove)
+			crosses++
+			skipped += whiteSkipped
+			withinLimit = whiteSkipped <= s.maxSkipped
+		}
+		if turn.ColorDiceMove != nil {
+			colorSkipped := SkippedCells(afterWhite, *turn.ColorDiceMove)
+			crosses++
+			skipped += colorSkipped
+			withinLimit = withinLimit && colorSkipped <= s.maxSkipped
+		}
+		if crosses == 1 && (fallbackSkipped < 0 || skipped < fallbackSkipped) {
+			fallback, fallbackSkipped = turn, skipped
+		}
+		if crosses > 0 && withinLimit && (crosses > bestCrosses || crosses == bestCrosses && skipped < bestSkipped) {
+			best, bestCrosses, bestSkipped = turn, crosses, skipped
+		}
+	}
+	if bestCrosses == 0 {
+		return fallback
+	}
+	return best
+}
+
+func (s greedyStrategy) ChooseInactivePlayerTurn(playerBoard board.Board, diceRoll actions.DiceRoll) actions.InactivePlayerTurn {
+	var best actions.InactivePlayerTurn
+	bestSkipped := s.maxSkipped + 1
+	for _, turn := range rule_checker.LegalInactiveTurns(playerBoard, diceRoll) {
+		if turn.WhiteDiceMove == nil {
+			continue
+		}
+		if skipped := SkippedCells(playerBoard, *turn.WhiteDiceMove); skipped < bestSkipped {
+			best, bestSkipped = turn, skipped
+		}
+	}
+	return best
+}
+
+// hardStrategy plays the turns with the best estimated score impact, taking a penalty as the active player
+// when every move gives up more than the penalty costs
+type hardStrategy struct {
+	penaltyValue int
+}
+
+func (s hardStrategy) ChooseActivePlayerTurn(playerBoard board.Board, diceRoll actions.DiceRoll) actions.ActivePlayerTurn {
+	return analysis.ActiveHint(playerBoard, diceRoll, s.penaltyValue).Turn
+}
+
+func (s hardStrategy) ChooseInactivePlayerTurn(playerBoard board.Board, diceRoll actions.DiceRoll) actions.InactivePlayerTurn {
+	return actions.InactivePlayerTurn{WhiteDiceMove: analysis.InactiveHint(playerBoard, diceRoll).Turn.WhiteDiceMove}
+}
+
+func (s hardStrategy) withPenaltyValue(penaltyValue int) Strategy {
+	s.penaltyValue = penaltyValue
+	return s
+}
+
+// SkippedCells counts the empty cells between the last crossed off cell of the move's row and the cell of the move,
+// which can never be crossed off once the move is made
+func SkippedCells(playerBoard board.Board, move actions.Move) int {
+	skipped := 0
+	for _, cell := range playerBoard.Cells(move.RowColor) {
+		if cell.Number == move.CellNumber {
+			return skipped
+		}
+		if playerBoard.IsCellMarked(move.RowColor, cell.Number) {
+			skipped = 0
+		} else {
+			skipped++
+		}
+	}
+	return skipped
+}
@@ -0,0 +1,192 @@
+package player
+
+import (
+	"math/rand"
+	"qwixx/internal/game/actions"
+	"qwixx/internal/game/board"
+	"qwixx/internal/game/rule_checker"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSkippedCells(t *testing.T) {
+	playerBoard, err := board.FromState(board.State{Rows: map[actions.RowColor][]int{
+		actions.RowColorRed:  {2, 3},
+		actions.RowColorBlue: {12},
+	}})
+	require.NoError(t, err)
+
+	type testCase struct {
+		name            string
+		move            actions.Move
+		expectedSkipped int
+	}
+	testCases := []testCase{
+		{name: "cell right after the last crossed off cell", move: actions.NewMove(actions.RowColorRed, 4), expectedSkipped: 0},
+		{name: "cells skipped after the last crossed off cell", move: actions.NewMove(actions.RowColorRed, 7), expectedSkipped: 3},
+		{name: "empty row counts from the left", move: actions.NewMove(actions.RowColorYellow, 5), expectedSkipped: 3},
+		{name: "descending row", move: actions.NewMove(actions.RowColorBlue, 9), expectedSkipped: 2},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expectedSkipped, SkippedCells(playerBoard, tc.move))
+		})
+	}
+}
+
+func TestNewStrategy(t *testing.T) {
+	for _, name := range StrategyNames() {
+		strategy, err := NewStrategy(name, rand.New(rand.NewSource(1)))
+		require.NoError(t, err)
+		require.NotNil(t, strategy)
+	}
+	_, err := NewStrategy("cheating", nil)
+	require.ErrorContains(t, err, `unknown strategy "cheating"`)
+}
+
+// TestNewStrategy_NilRand plays turns with strategies created without a source of randomness
+func TestNewStrategy_NilRand(t *testing.T) {
+	diceRoll := actions.RollQwixxDiceWith(rand.New(rand.NewSource(1)))
+	for _, name := range StrategyNames() {
+		t.Run(name, func(t *testing.T) {
+			strategy, err := NewStrategy(name, nil)
+			require.NoError(t, err)
+			require.NotPanics(t, func() {
+				strategy.ChooseActivePlayerTurn(board.NewGameBoard(), diceRoll)
+				strategy.ChooseInactivePlayerTurn(board.NewGameBoard(), diceRoll)
+			})
+		})
+	}
+}
+
+// TestStrategies_ChooseLegalTurns plays every strategy against many random rolls and boards
+// to check they only ever choose turns the rule checker allows
+func TestStrategies_ChooseLegalTurns(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for _, name := range StrategyNames() {
+		t.Run(name, func(t *testing.T) {
+			strategy, err := NewStrategy(name, rng)
+			require.NoError(t, err)
+			playerBoard := board.NewGameBoard()
+			for roll := 0; roll < 200; roll++ {
+				diceRoll := actions.RollQwixxDiceWith(rng)
+
+				inactiveTurn := strategy.ChooseInactivePlayerTurn(playerBoard.Copy(), diceRoll)
+				if inactiveTurn.WhiteDiceMove != nil {
+					require.True(t, rule_checker.WhiteDiceMoveIsValidForBoard(playerBoard, diceRoll, *inactiveTurn.WhiteDiceMove))
+				}
+
+				activeTurn := strategy.ChooseActivePlayerTurn(playerBoard.Copy(), diceRoll)
+				updatedBoard := playerBoard.Copy()
+				if activeTurn.WhiteDiceMove != nil {
+					require.True(t, rule_checker.WhiteDiceMoveIsValidForBoard(updatedBoard, diceRoll, *activeTurn.WhiteDiceMove))
+					require.NoError(t, updatedBoard.MakeMove(*activeTurn.WhiteDiceMove))
+				}
+				if activeTurn.ColorDiceMove != nil {
+					require.True(t, rule_checker.ColorDiceMoveIsValidForBoard(updatedBoard, diceRoll, *activeTurn.ColorDiceMove))
+					require.NoError(t, updatedBoard.MakeMove(*activeTurn.ColorDiceMove))
+				}
+				playerBoard = updatedBoard
+			}
+		})
+	}
+}
+
+func TestGreedyStrategies(t *testing.T) {
+	greedy, err := NewStrategy(StrategyGreedy, nil)
+	require.NoError(t, err)
+	careful, err := NewStrategy(StrategyCareful, nil)
+	require.NoError(t, err)
+
+	t.Run("greedy uses both dice when it can, skipping as few cells as possible", func(t *testing.T) {
+		// the white 9 skips three cells in any row, green 6+5 only skips the green 12
+		turn := greedy.ChooseActivePlayerTurn(board.NewGameBoard(), testDiceRoll)
+		require.Equal(t, actions.ActivePlayerTurn{
+			WhiteDiceMove: &actions.Move{RowColor: actions.RowColorBlue, CellNumber: 9},
+			ColorDiceMove: &actions.Move{RowColor: actions.RowColorGreen, CellNumber: 11},
+		}, turn)
+	})
+
+	t.Run("careful only uses the dice that skip at most one cell", func(t *testing.T) {
+		turn := careful.ChooseActivePlayerTurn(board.NewGameBoard(), testDiceRoll)
+		require.Equal(t, actions.ActivePlayerTurn{
+			ColorDiceMove: &actions.Move{RowColor: actions.RowColorGreen, CellNumber: 11},
+		}, turn)
+	})
+
+	t.Run("careful passes as an inactive player rather than skip cells", func(t *testing.T) {
+		turn := careful.ChooseInactivePlayerTurn(board.NewGameBoard(), testDiceRoll)
+		require.Nil(t, turn.WhiteDiceMove)
+	})
+
+	t.Run("careful makes its least wasteful move rather than take a penalty", func(t *testing.T) {
+		// every sum is a 6, which skips at least four cells in any row
+		diceRoll := actions.DiceRoll{
+			WhiteDiceRoll: actions.WhiteDiceRoll{White1: 3, White2: 3},
+			ColorDiceRoll: actions.ColorDiceRoll{Red: 3, Yellow: 3, Green: 3, Blue: 3},
+		}
+		turn := careful.ChooseActivePlayerTurn(board.NewGameBoard(), diceRoll)
+		require.Equal(t, actions.ActivePlayerTurn{
+			ColorDiceMove: &actions.Move{RowColor: actions.RowColorRed, CellNumber: 6},
+		}, turn)
+	})
+}
+
+func TestHardStrategy(t *testing.T) {
+	hard, err := NewStrategy(StrategyHard, nil)
+	require.NoError(t, err)
+	snakeEyes := actions.DiceRoll{
+		WhiteDiceRoll: actions.WhiteDiceRoll{White1: 1, White2: 1},
+		ColorDiceRoll: actions.ColorDiceRoll{Red: 6, Yellow: 6, Green: 6, Blue: 6},
+	}
+
+	t.Run("crosses off the cell that skips nothing rather than take a penalty", func(t *testing.T) {
+		turn := hard.ChooseActivePlayerTurn(board.NewGameBoard(), snakeEyes)
+		require.Equal(t, actions.ActivePlayerTurn{WhiteDiceMove: &actions.Move{RowColor: actions.RowColorRed, CellNumber: 2}}, turn)
+	})
+
+	t.Run("passes as an inactive player rather than skip cells it is likely to roll", func(t *testing.T) {
+		// a white 6 skips four cells in the red and yellow rows and six in the green and blue rows
+		diceRoll := actions.DiceRoll{WhiteDiceRoll: actions.WhiteDiceRoll{White1: 3, White2: 3}}
+		turn := hard.ChooseInactivePlayerTurn(board.NewGameBoard(), diceRoll)
+		require.Nil(t, turn.WhiteDiceMove)
+	})
+}
+
+func TestHardStrategy_PenaltyValue(t *testing.T) {
+	playerBoard, err := board.FromState(board.State{Rows: map[actions.RowColor][]int{
+		actions.RowColorRed: {2}, actions.RowColorYellow: {2}, actions.RowColorGreen: {12}, actions.RowColorBlue: {12},
+	}})
+	require.NoError(t, err)
+	// every move skips cells, the least wasteful being a red 7 from a white 1 and the red 6
+	diceRoll := actions.DiceRoll{
+		WhiteDiceRoll: actions.WhiteDiceRoll{White1: 1, White2: 1},
+		ColorDiceRoll: actions.ColorDiceRoll{Red: 6, Yellow: 6, Green: 1, Blue: 1},
+	}
+	redSeven := actions.ActivePlayerTurn{ColorDiceMove: &actions.Move{RowColor: actions.RowColorRed, CellNumber: 7}}
+
+	type testCase struct {
+		name         string
+		penaltyValue int
+		expected     actions.ActivePlayerTurn
+	}
+	testCases := []testCase{
+		{name: "takes a free penalty rather than skip cells", penaltyValue: 0, expected: actions.ActivePlayerTurn{}},
+		{name: "skips cells rather than take a costly penalty", penaltyValue: 50, expected: redSeven},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			hard, err := NewStrategy(StrategyHard, nil, WithPenaltyValue(tc.penaltyValue))
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, hard.ChooseActivePlayerTurn(playerBoard, diceRoll))
+
+			// a computer player told of the penalty value once its game starts plays the same
+			hard, err = NewStrategy(StrategyHard, nil)
+			require.NoError(t, err)
+			computerPlayer := NewStrategyPlayer("bot", hard, nil)
+			computerPlayer.(PenaltyValueListener).InformOfPenaltyValue(tc.penaltyValue)
+			require.Equal(t, tc.expected, computerPlayer.PromptActivePlayerTurn(playerBoard, diceRoll))
+		})
+	}
+}
@@ -0,0 +1,421 @@
+package player
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"qwixx/internal/analysis"
+	"qwixx/internal/game/actions"
+	"qwixx/internal/game/board"
+	"qwixx/internal/game/rule_checker"
+	"qwixx/internal/render"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+var _ Player = &TerminalPlayer{}
+
+// Terminal is a line based terminal shared by every human player sitting at it
+type Terminal struct {
+	mu     sync.Mutex
+	in     *bufio.Scanner
+	out    io.Writer
+	closed bool
+	// renderer draws boards and dice in color if the output is a terminal
+	renderer render.Renderer
+	// hints lets the players type "hint" for the recommended turn, with a penalty costing penaltyValue points
+	hints        bool
+	penaltyValue int
+	// anyDiceOrder accepts turns whose color dice move has to be made before their white dice move
+	anyDiceOrder bool
+}
+
+// TerminalOption changes how a terminal treats the players sitting at it
+type TerminalOption func(t *Terminal)
+
+// WithHints lets the players at the terminal type "hint" when prompted for the turn the analysis package recommends,
+// weighing a penalty as costing the given number of points
+func WithHints(penaltyValue int) TerminalOption {
+	return func(t *Terminal) {
+		t.hints = true
+		t.penaltyValue = penaltyValue
+	}
+}
+
+// WithAnyDiceOrder lets the active players at the terminal use the color dice before the white dice,
+// for games played with the AnyDiceOrder house rule
+func WithAnyDiceOrder() TerminalOption {
+	return func(t *Terminal) {
+		t.anyDiceOrder = true
+	}
+}
+
+func NewTerminal(in io.Reader, out io.Writer, options ...TerminalOption) *Terminal {
+	t := &Terminal{in: bufio.NewScanner(in), out: out, renderer: render.ForWriter(out)}
+	for _, option := range options {
+		option(t)
+	}
+	return t
+}
+
+func (t *Terminal) printf(format string, args ...any) {
+	_, _ = fmt.Fprintf(t.out, format, args...)
+}
+
+// readLine reads the next line of input, returning false once the input is exhausted
+func (t *Terminal) readLine() (string, bool) {
+	if t.closed {
+		return "", false
+	}
+	if !t.in.Scan() {
+		t.closed = true
+		return "", false
+	}
+	return strings.TrimSpace(t.in.Text()), true
+}
+
+// TerminalPlayer is a human playing from a terminal.
+// They are shown their board and the dice, along with the legal moves, and type their turn,
+// e.g. "w R7 c B9" to cross off red 7 with the white dice and blue 9 with a color die, or "pass".
+type TerminalPlayer struct {
+	name     string
+	terminal *Terminal
+	hotSeat  bool
+	// penalties counts the penalties taken by passing as the active player, for the penalty track of the board
+	penalties int
+}
+
+// NewTerminalPlayer creates a human player who has a terminal to themselves
+func NewTerminalPlayer(name string, in io.Reader, out io.Writer, options ...TerminalOption) Player {
+	return &TerminalPlayer{name: name, terminal: NewTerminal(in, out, options...)}
+}
+
+// NewHotSeatPlayers creates human players with the given names taking turns at the same terminal.
+// Every prompt starts by asking for the terminal to be handed to the player being prompted.
+func NewHotSeatPlayers(names []string, in io.Reader, out io.Writer, options ...TerminalOption) []Player {
+	terminal := NewTerminal(in, out, options...)
+	players := make([]Player, 0, len(names))
+	for _, name := range names {
+		players = append(players, &TerminalPlayer{name: name, terminal: terminal, hotSeat: true})
+	}
+	return players
+}
+
+func (tp *TerminalPlayer) GetName() string {
+	return tp.name
+}
+
+// announce prints a message for this player, naming them when the terminal is shared
+func (tp *TerminalPlayer) announce(format string, args ...any) {
+	if tp.hotSeat {
+		tp.terminal.printf("[%v] ", tp.name)
+	}
+	tp.terminal.printf(format, args...)
+}
+
+func (tp *TerminalPlayer) InformOfPlayOrder(playerNames []string) {
+	tp.terminal.mu.Lock()
+	defer tp.terminal.mu.Unlock()
+	tp.announce("play order: %v\n", strings.Join(playerNames, ", "))
+}
+
+// takeSeat asks for the shared terminal to be handed to this player and waits for them to confirm they have it
+func (tp *TerminalPlayer) takeSeat() {
+	if !tp.hotSeat {
+		return
+	}
+	tp.terminal.printf("\n==== pass the terminal to %v and press enter ====\n", tp.name)
+	tp.terminal.readLine()
+}
+
+func (tp *TerminalPlayer) printSituation(playerBoard board.Board, diceRoll actions.DiceRoll) {
+	tp.terminal.printf("%v's board:\n%v\n", tp.name, tp.terminal.renderer.Board(playerBoard, tp.penalties))
+	tp.terminal.printf("%v\n", tp.terminal.renderer.Dice(diceRoll))
+}
+
+func (tp *TerminalPlayer) PromptActivePlayerTurn(
+	playerBoard board.Board,
+	diceRoll actions.DiceRoll,
+) actions.ActivePlayerTurn {
+	tp.terminal.mu.Lock()
+	defer tp.terminal.mu.Unlock()
+	tp.takeSeat()
+	tp.printSituation(playerBoard, diceRoll)
+	tp.terminal.printf("white dice moves: %v\n", formatMoves(legalWhiteDiceMoves(playerBoard, diceRoll)))
+	tp.terminal.printf("color dice moves: %v\n", formatMoves(legalColorDiceMoves(playerBoard, diceRoll)))
+
+	for {
+		tp.announce("your turn as the active player, e.g. \"w R7 c B9\", \"w R7\", \"c B9\" or \"pass\" to take a penalty%v: ", tp.hintChoice())
+		line, ok := tp.terminal.readLine()
+		if !ok {
+			tp.penalties++
+			return actions.ActivePlayerTurn{}
+		}
+		if isHintRequest(line) {
+			tp.printHint(func() analysis.Hint {
+				return analysis.ActiveHint(playerBoard, diceRoll, tp.terminal.penaltyValue)
+			})
+			continue
+		}
+		turn, err := parseActivePlayerTurn(line)
+		if err == nil {
+			err = explainActiveTurnRejection(playerBoard, diceRoll, turn, tp.terminal.anyDiceOrder)
+		}
+		if err != nil {
+			tp.terminal.printf("  %v\n", err)
+			continue
+		}
+		if turn.WhiteDiceMove == nil && turn.ColorDiceMove == nil {
+			tp.penalties++
+		}
+		return turn
+	}
+}
+
+func (tp *TerminalPlayer) PromptInactivePlayerTurn(
+	playerBoard board.Board,
+	diceRoll actions.DiceRoll,
+) actions.InactivePlayerTurn {
+	tp.terminal.mu.Lock()
+	defer tp.terminal.mu.Unlock()
+	tp.takeSeat()
+	tp.printSituation(playerBoard, diceRoll)
+	tp.terminal.printf("white dice moves: %v\n", formatMoves(legalWhiteDiceMoves(playerBoard, diceRoll)))
+
+	for {
+		tp.announce("you may cross off the white dice sum, e.g. \"w R7\", or \"pass\"%v: ", tp.hintChoice())
+		line, ok := tp.terminal.readLine()
+		if !ok {
+			return actions.InactivePlayerTurn{}
+		}
+		if isHintRequest(line) {
+			tp.printHint(func() analysis.Hint {
+				return analysis.InactiveHint(playerBoard, diceRoll)
+			})
+			continue
+		}
+		turn, err := parseActivePlayerTurn(line)
+		if err == nil && turn.ColorDiceMove != nil {
+			err = errors.New("only the active player can use the color dice")
+		}
+		if err == nil && turn.WhiteDiceMove != nil {
+			err = explainWhiteDiceMoveRejection(playerBoard, diceRoll, *turn.WhiteDiceMove)
+		}
+		if err != nil {
+			tp.terminal.printf("  %v\n", err)
+			continue
+		}
+		return actions.InactivePlayerTurn{WhiteDiceMove: turn.WhiteDiceMove}
+	}
+}
+
+// hintChoice mentions typing "hint" in a prompt if the terminal allows hints
+func (tp *TerminalPlayer) hintChoice() string {
+	if !tp.terminal.hints {
+		return ""
+	}
+	return ` ("hint" for a recommendation)`
+}
+
+// isHintRequest determines if the typed line asks for a hint
+func isHintRequest(line string) bool {
+	return strings.EqualFold(line, "hint")
+}
+
+// printHint prints the hint the given function works out, or that hints are not allowed if the terminal does not allow them
+func (tp *TerminalPlayer) printHint(hint func() analysis.Hint) {
+	if !tp.terminal.hints {
+		tp.terminal.printf("  hints are not allowed in this game\n")
+		return
+	}
+	recommended := hint()
+	tp.terminal.printf("  hint: %v, which %v\n", formatTurn(recommended.Turn), recommended.Explanation)
+}
+
+func (tp *TerminalPlayer) InformSuccessfulTurn(updatedBoard board.Board) {
+	tp.terminal.mu.Lock()
+	defer tp.terminal.mu.Unlock()
+	tp.announce("your board is now:\n%v\n", tp.terminal.renderer.Board(updatedBoard, tp.penalties))
+}
+
+func (tp *TerminalPlayer) InformInvalidTurn(err error) {
+	tp.terminal.mu.Lock()
+	defer tp.terminal.mu.Unlock()
+	tp.announce("that turn is not allowed: %v\n", err)
+}
+
+func (tp *TerminalPlayer) InformOfOpponentMove(playerID PlayerID, move actions.Move) {}
+
+func (tp *TerminalPlayer) InformRowLocked(color actions.RowColor) {
+	tp.terminal.mu.Lock()
+	defer tp.terminal.mu.Unlock()
+	tp.announce("the %v row was locked\n", color)
+}
+
+func (tp *TerminalPlayer) InformWin() {
+	tp.terminal.mu.Lock()
+	defer tp.terminal.mu.Unlock()
+	tp.announce("you won!\n")
+}
+
+func (tp *TerminalPlayer) InformLoss(winnerID PlayerID) {
+	tp.terminal.mu.Lock()
+	defer tp.terminal.mu.Unlock()
+	tp.announce("you lost, %v won the game\n", winnerID)
+}
+
+// legalWhiteDiceMoves lists the cells that can be crossed off with the sum of the white dice
+func legalWhiteDiceMoves(playerBoard board.Board, diceRoll actions.DiceRoll) []actions.Move {
+	var legal []actions.Move
+	for _, turn := range rule_checker.LegalInactiveTurns(playerBoard, diceRoll) {
+		if turn.WhiteDiceMove != nil {
+			legal = append(legal, *turn.WhiteDiceMove)
+		}
+	}
+	return legal
+}
+
+// legalColorDiceMoves lists the cells that can be crossed off with a color die when the white dice are not used
+func legalColorDiceMoves(playerBoard board.Board, diceRoll actions.DiceRoll) []actions.Move {
+	var legal []actions.Move
+	for _, turn := range rule_checker.LegalActiveTurns(playerBoard, diceRoll) {
+		if turn.WhiteDiceMove == nil && turn.ColorDiceMove != nil {
+			legal = append(legal, *turn.ColorDiceMove)
+		}
+	}
+	return legal
+}
+
+// formatMoves lists the given moves in the notation players type them in
+func formatMoves(moves []actions.Move) string {
+	if len(moves) == 0 {
+		return "none"
+	}
+	formatted := make([]string, 0, len(moves))
+	for _, move := range moves {
+		formatted = append(formatted, formatMove(move))
+	}
+	return strings.Join(formatted, " ")
+}
+
+// formatTurn writes a turn the way players type it, like "w R7 c B9" or "pass"
+func formatTurn(turn actions.ActivePlayerTurn) string {
+	var fields []string
+	if turn.WhiteDiceMove != nil {
+		fields = append(fields, "w", formatMove(*turn.WhiteDiceMove))
+	}
+	if turn.ColorDiceMove != nil {
+		fields = append(fields, "c", formatMove(*turn.ColorDiceMove))
+	}
+	if len(fields) == 0 {
+		return "pass"
+	}
+	return strings.Join(fields, " ")
+}
+
+// formatMove writes a move as the first letter of its row color followed by its cell number, like R7
+func formatMove(move actions.Move) string {
+	return fmt.Sprintf("%v%v", move.RowColor.String()[:1], move.CellNumber)
+}
+
+// parseMove parses a move written as a row color, or its first letter, followed by a cell number, like R7 or blue9
+func parseMove(text string) (actions.Move, error) {
+	split := strings.IndexAny(text, "0123456789")
+	if split <= 0 {
+		return actions.Move{}, fmt.Errorf("%q is not a move, expected a row color followed by a cell number like R7", text)
+	}
+	colorText, numberText := text[:split], text[split:]
+	cellNumber, err := strconv.Atoi(numberText)
+	if err != nil {
+		return actions.Move{}, fmt.Errorf("%q is not a cell number", numberText)
+	}
+	if len(colorText) == 1 {
+		for _, color := range actions.RowColors() {
+			if strings.EqualFold(colorText, color.String()[:1]) {
+				return actions.NewMove(color, cellNumber), nil
+			}
+		}
+	}
+	color, err := actions.ParseRowColor(colorText)
+	if err != nil {
+		return actions.Move{}, err
+	}
+	return actions.NewMove(color, cellNumber), nil
+}
+
+// parseActivePlayerTurn parses a typed turn such as "w R7 c B9", "c B9" or "pass"
+func parseActivePlayerTurn(line string) (actions.ActivePlayerTurn, error) {
+	fields := strings.Fields(strings.ToLower(line))
+	if len(fields) == 1 && fields[0] == "pass" {
+		return actions.ActivePlayerTurn{}, nil
+	}
+	if len(fields) == 0 || len(fields)%2 != 0 {
+		return actions.ActivePlayerTurn{}, fmt.Errorf("could not understand %q", line)
+	}
+	var turn actions.ActivePlayerTurn
+	for idx := 0; idx < len(fields); idx += 2 {
+		move, err := parseMove(fields[idx+1])
+		if err != nil {
+			return actions.ActivePlayerTurn{}, err
+		}
+		switch fields[idx] {
+		case "w", "white":
+			if turn.WhiteDiceMove != nil {
+				return actions.ActivePlayerTurn{}, errors.New("only one white dice move can be made per turn")
+			}
+			turn.WhiteDiceMove = &move
+		case "c", "color":
+			if turn.ColorDiceMove != nil {
+				return actions.ActivePlayerTurn{}, errors.New("only one color dice move can be made per turn")
+			}
+			turn.ColorDiceMove = &move
+		default:
+			return actions.ActivePlayerTurn{}, fmt.Errorf("%q should be w for the white dice or c for a color die", fields[idx])
+		}
+	}
+	return turn, nil
+}
+
+// explainWhiteDiceMoveRejection explains why the given white dice move cannot be made, returning nil if it can
+func explainWhiteDiceMoveRejection(playerBoard board.Board, diceRoll actions.DiceRoll, move actions.Move) error {
+	if ok, err := playerBoard.IsMoveValid(move); !ok {
+		return fmt.Errorf("cannot cross off %v: %w", formatMove(move), err)
+	}
+	if err := rule_checker.ValidateWhiteDiceMove(playerBoard, diceRoll, move); err != nil {
+		return fmt.Errorf("cannot cross off %v: %w", formatMove(move), err)
+	}
+	return nil
+}
+
+// explainActiveTurnRejection explains why the given turn cannot be played, returning nil if it can.
+// The white dice move is made before the color dice move is checked, as it would be when the turn is played,
+// unless anyDiceOrder allows the turn to be played with the color dice move first.
+func explainActiveTurnRejection(playerBoard board.Board, diceRoll actions.DiceRoll, turn actions.ActivePlayerTurn, anyDiceOrder bool) error {
+	if anyDiceOrder {
+		if _, err := rule_checker.ValidateActivePlayerTurnInAnyOrder(playerBoard, diceRoll, turn); err == nil {
+			return nil
+		}
+	}
+	playerBoard = playerBoard.Copy()
+	if turn.WhiteDiceMove != nil {
+		if err := explainWhiteDiceMoveRejection(playerBoard, diceRoll, *turn.WhiteDiceMove); err != nil {
+			return err
+		}
+		if err := playerBoard.MakeMove(*turn.WhiteDiceMove); err != nil {
+			return err
+		}
+	}
+	if turn.ColorDiceMove != nil {
+		move := *turn.ColorDiceMove
+		if ok, err := playerBoard.IsMoveValid(move); !ok {
+			return fmt.Errorf("cannot cross off %v: %w", formatMove(move), err)
+		}
+		// the die is the color of the cell, which is not the color of its row on every sheet
+		if err := rule_checker.ValidateColorDiceMove(playerBoard, diceRoll, move); err != nil {
+			return fmt.Errorf("cannot cross off %v: %w", formatMove(move), err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,212 @@
+package player
+
+import (
+	"bytes"
+	"qwixx/internal/game/actions"
+	"qwixx/internal/game/board"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseActivePlayerTurn(t *testing.T) {
+	type testCase struct {
+		name          string
+		input         string
+		expectedTurn  actions.ActivePlayerTurn
+		expectedError bool
+	}
+	testCases := []testCase{
+		{
+			name:         "pass",
+			input:        "pass",
+			expectedTurn: actions.ActivePlayerTurn{},
+		},
+		{
+			name:  "white and color moves",
+			input: "w R7 c B9",
+			expectedTurn: actions.ActivePlayerTurn{
+				WhiteDiceMove: &actions.Move{RowColor: actions.RowColorRed, CellNumber: 7},
+				ColorDiceMove: &actions.Move{RowColor: actions.RowColorBlue, CellNumber: 9},
+			},
+		},
+		{
+			name:  "color move only with spelled out color",
+			input: "color yellow12",
+			expectedTurn: actions.ActivePlayerTurn{
+				ColorDiceMove: &actions.Move{RowColor: actions.RowColorYellow, CellNumber: 12},
+			},
+		},
+		{
+			name:  "moves in either order",
+			input: "C g3 W y4",
+			expectedTurn: actions.ActivePlayerTurn{
+				WhiteDiceMove: &actions.Move{RowColor: actions.RowColorYellow, CellNumber: 4},
+				ColorDiceMove: &actions.Move{RowColor: actions.RowColorGreen, CellNumber: 3},
+			},
+		},
+		{
+			name:          "empty input",
+			input:         "",
+			expectedError: true,
+		},
+		{
+			name:          "two white moves",
+			input:         "w R7 w B9",
+			expectedError: true,
+		},
+		{
+			name:  "big points rows",
+			input: "w O7 c P9",
+			expectedTurn: actions.ActivePlayerTurn{
+				WhiteDiceMove: &actions.Move{RowColor: actions.RowColorOrange, CellNumber: 7},
+				ColorDiceMove: &actions.Move{RowColor: actions.RowColorPurple, CellNumber: 9},
+			},
+		},
+		{
+			name:          "unknown color",
+			input:         "w X7",
+			expectedError: true,
+		},
+		{
+			name:          "missing cell number",
+			input:         "w R",
+			expectedError: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			turn, err := parseActivePlayerTurn(tc.input)
+			if tc.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedTurn, turn)
+		})
+	}
+}
+
+func TestTerminalPlayer_PromptActivePlayerTurn(t *testing.T) {
+	playerBoard := board.NewGameBoard()
+	require.NoError(t, playerBoard.MakeMove(actions.NewMove(actions.RowColorYellow, 8)))
+	input := strings.Join([]string{
+		"w R10",      // wrong sum
+		"w Y7",       // left of a crossed off cell
+		"w R9 c R8",  // color move to the left of the white move
+		"c Y9",       // no white die plus the yellow die makes 9
+		"w R9 c Y12", // rightmost cell without five others
+		"w R9 c B7",
+	}, "\n")
+	var output bytes.Buffer
+	p := NewTerminalPlayer("alice", strings.NewReader(input), &output)
+
+	turn := p.PromptActivePlayerTurn(playerBoard, testDiceRoll)
+	require.Equal(t, actions.ActivePlayerTurn{
+		WhiteDiceMove: &actions.Move{RowColor: actions.RowColorRed, CellNumber: 9},
+		ColorDiceMove: &actions.Move{RowColor: actions.RowColorBlue, CellNumber: 7},
+	}, turn)
+
+	printed := output.String()
+	require.Contains(t, printed, "white dice moves: R9 Y9 G9 B9\n")
+	require.Contains(t, printed, "color dice moves: R8 R9 G10 G11 B6 B7\n")
+	require.Contains(t, printed, "cannot cross off R10: cell 10 is not the sum of the white dice 4 and 5")
+	require.Contains(t, printed, "cannot cross off Y7: cell 7 is to the left of already crossed off cells")
+	require.Contains(t, printed, "cannot cross off R8: cell 8 is to the left of already crossed off cells")
+	require.Contains(t, printed, "cannot cross off Y9: cell 9 is not the sum of the Yellow die 3 and either white die 4 or 5")
+	require.Contains(t, printed, "cannot cross off Y12: cannot cross off rightmost cell")
+}
+
+func TestTerminalPlayer_MixedColorCells(t *testing.T) {
+	// on the Mixx colors sheet the red 5 is a green cell, crossed off with the green die, and the red 7 a blue one
+	playerBoard, err := board.NewBoard(board.VariantMixxColors)
+	require.NoError(t, err)
+	var output bytes.Buffer
+	p := NewTerminalPlayer("alice", strings.NewReader("c R5\nc R7\n"), &output)
+
+	require.Equal(t, actions.ActivePlayerTurn{
+		ColorDiceMove: &actions.Move{RowColor: actions.RowColorRed, CellNumber: 7},
+	}, p.PromptActivePlayerTurn(playerBoard, testDiceRoll))
+	require.Contains(t, output.String(), "cannot cross off R5: cell 5 is not the sum of the Green die 6 and either white die 4 or 5")
+}
+
+func TestTerminalPlayer_AnyDiceOrder(t *testing.T) {
+	// red 8 from the red 4 and a white 4 has to be crossed off before the white dice red 9
+	colorFirst := actions.ActivePlayerTurn{
+		WhiteDiceMove: &actions.Move{RowColor: actions.RowColorRed, CellNumber: 9},
+		ColorDiceMove: &actions.Move{RowColor: actions.RowColorRed, CellNumber: 8},
+	}
+
+	var output bytes.Buffer
+	p := NewTerminalPlayer("alice", strings.NewReader("w R9 c R8\n"), &output, WithAnyDiceOrder())
+	require.Equal(t, colorFirst, p.PromptActivePlayerTurn(board.NewGameBoard(), testDiceRoll))
+	require.NotContains(t, output.String(), "cannot cross off")
+
+	output.Reset()
+	p = NewTerminalPlayer("alice", strings.NewReader("w R9 c R8\n"), &output)
+	require.Equal(t, actions.ActivePlayerTurn{}, p.PromptActivePlayerTurn(board.NewGameBoard(), testDiceRoll))
+	require.Contains(t, output.String(), "cannot cross off R8: cell 8 is to the left of already crossed off cells")
+}
+
+func TestTerminalPlayer_PromptInactivePlayerTurn(t *testing.T) {
+	var output bytes.Buffer
+	p := NewTerminalPlayer("alice", strings.NewReader("c B7\nw G9\n"), &output)
+
+	turn := p.PromptInactivePlayerTurn(board.NewGameBoard(), testDiceRoll)
+	require.Equal(t, actions.InactivePlayerTurn{
+		WhiteDiceMove: &actions.Move{RowColor: actions.RowColorGreen, CellNumber: 9},
+	}, turn)
+	require.Contains(t, output.String(), "only the active player can use the color dice")
+}
+
+func TestTerminalPlayer_EndOfInputPasses(t *testing.T) {
+	var output bytes.Buffer
+	p := NewTerminalPlayer("alice", strings.NewReader("w R10\n"), &output)
+
+	require.Equal(t, actions.ActivePlayerTurn{}, p.PromptActivePlayerTurn(board.NewGameBoard(), testDiceRoll))
+	require.Equal(t, actions.InactivePlayerTurn{}, p.PromptInactivePlayerTurn(board.NewGameBoard(), testDiceRoll))
+}
+
+func TestHotSeatPlayers(t *testing.T) {
+	var output bytes.Buffer
+	input := strings.Join([]string{
+		"",     // alice takes the seat
+		"pass", // alice's turn
+		"",     // bob takes the seat
+		"w B9", // bob's turn
+	}, "\n")
+	players := NewHotSeatPlayers([]string{"alice", "bob"}, strings.NewReader(input), &output)
+	require.Len(t, players, 2)
+
+	require.Equal(t, actions.ActivePlayerTurn{}, players[0].PromptActivePlayerTurn(board.NewGameBoard(), testDiceRoll))
+	require.Equal(t, actions.InactivePlayerTurn{
+		WhiteDiceMove: &actions.Move{RowColor: actions.RowColorBlue, CellNumber: 9},
+	}, players[1].PromptInactivePlayerTurn(board.NewGameBoard(), testDiceRoll))
+
+	printed := output.String()
+	aliceSeat := strings.Index(printed, "pass the terminal to alice")
+	bobSeat := strings.Index(printed, "pass the terminal to bob")
+	require.True(t, aliceSeat >= 0 && bobSeat > aliceSeat)
+	require.Contains(t, printed, "[bob] you may cross off the white dice sum")
+}
+
+func TestTerminalPlayer_Hint(t *testing.T) {
+	playerBoard, _, err := board.Parse("R:2,3,4,5,6,7,8 Y:2,3,4,5,6,7,8 G:12,11,10 B:12,11,10")
+	require.NoError(t, err)
+
+	var output bytes.Buffer
+	p := NewTerminalPlayer("alice", strings.NewReader("hint\nw R9\nhint\npass\n"), &output, WithHints(5))
+	p.PromptActivePlayerTurn(playerBoard, testDiceRoll)
+	p.PromptInactivePlayerTurn(playerBoard, testDiceRoll)
+	printed := output.String()
+	require.Contains(t, printed, `or "pass" to take a penalty ("hint" for a recommendation): `)
+	require.Contains(t, printed, "hint: w Y9 c R9, which crosses off Yellow 9 skipping no cells, then Red 9 skipping no cells; avoids penalty\n")
+	require.Contains(t, printed, "hint: w R9, which crosses off Red 9 skipping no cells\n")
+
+	output.Reset()
+	p = NewTerminalPlayer("alice", strings.NewReader("hint\npass\n"), &output)
+	require.Equal(t, actions.InactivePlayerTurn{}, p.PromptInactivePlayerTurn(playerBoard, testDiceRoll))
+	require.Contains(t, output.String(), "hints are not allowed in this game\n")
+	require.NotContains(t, output.String(), `"hint"`)
+}
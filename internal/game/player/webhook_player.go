@@ -0,0 +1,288 @@
+package player
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"qwixx/internal/game/actions"
+	"qwixx/internal/game/board"
+	"qwixx/internal/game/ruleset"
+	"strconv"
+	"sync"
+	"time"
+)
+
+var _ Player = &WebhookPlayer{}
+
+const (
+	// DefaultWebhookTimeout is how long a single webhook request may take if no timeout is configured
+	DefaultWebhookTimeout = 5 * time.Second
+	// DefaultWebhookRetryBackoff is how long to wait before the first retry if no backoff is configured.
+	// Each later retry waits one backoff longer than the previous one.
+	DefaultWebhookRetryBackoff = 200 * time.Millisecond
+
+	// WebhookTimestampHeader holds the unix time at which a webhook request was signed
+	WebhookTimestampHeader = "X-Qwixx-Timestamp"
+	// WebhookSignatureHeader holds the signature of a webhook request, see WebhookSignature
+	WebhookSignatureHeader = "X-Qwixx-Signature"
+)
+
+// WebhookPlayerConfig describes the HTTP service making a webhook player's decisions
+type WebhookPlayerConfig struct {
+	// Name is the name of the player in the game
+	Name string
+	// URL receives a POST for every prompt
+	URL string
+	// Secret signs every request when set, so the service can check requests come from this game
+	Secret []byte
+	// Timeout bounds each request, DefaultWebhookTimeout if zero
+	Timeout time.Duration
+	// Retries is how many more times a request is attempted after a network error or a 5xx response
+	Retries int
+	// RetryBackoff is the wait before the first retry, DefaultWebhookRetryBackoff if zero
+	RetryBackoff time.Duration
+	// Client sends the requests, http.DefaultClient if nil
+	Client *http.Client
+	// Variant is the sheet of the game, used to keep track of the opponents' boards, the classic sheet if empty
+	Variant board.Variant
+	// Ruleset is the rules for crossing off cells of the game, used to keep track of the opponents' boards, the classic rules if nil
+	Ruleset ruleset.Ruleset
+}
+
+// WebhookPrompt is the body POSTed to a webhook player's URL.
+// The service answers a prompt_active prompt with an actions.ActivePlayerTurn,
+// and a prompt_inactive prompt with an actions.InactivePlayerTurn, encoded as JSON.
+type WebhookPrompt struct {
+	Type           ExternalMessageType      `json:"type"`
+	ID             int                      `json:"id"`
+	PlayerName     string                   `json:"player_name"`
+	Board          board.State              `json:"board"`
+	DiceRoll       actions.DiceRoll         `json:"dice"`
+	OpponentBoards map[PlayerID]board.State `json:"opponent_boards"`
+	// Rejection says why the service's previous answer was not allowed, if it was rejected
+	Rejection *board.RuleViolation `json:"rejection,omitempty"`
+}
+
+// WebhookPlayer is a player whose decisions are made by an HTTP service.
+// It keeps track of its opponents' boards from the moves it is informed of, and sends them along with every prompt.
+type WebhookPlayer struct {
+	config WebhookPlayerConfig
+	client *http.Client
+
+	mu             sync.Mutex
+	promptID       int
+	opponentBoards map[PlayerID]board.Board
+	rejection      *board.RuleViolation
+	lastErr        error
+}
+
+// NewWebhookPlayer creates a player POSTing its prompts to the URL of the given config
+func NewWebhookPlayer(config WebhookPlayerConfig) (*WebhookPlayer, error) {
+	parsedURL, err := url.Parse(config.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid webhook url: %w", err)
+	}
+	if parsedURL.Scheme != "http" && parsedURL.Scheme != "https" {
+		return nil, fmt.Errorf("invalid webhook url %q: must be http or https", config.URL)
+	}
+	if _, err := board.NewBoard(config.Variant); err != nil {
+		return nil, err
+	}
+	if config.Retries < 0 {
+		return nil, errors.New("webhook retries cannot be negative")
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = DefaultWebhookTimeout
+	}
+	if config.RetryBackoff <= 0 {
+		config.RetryBackoff = DefaultWebhookRetryBackoff
+	}
+	client := config.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &WebhookPlayer{
+		config:         config,
+		client:         client,
+		opponentBoards: make(map[PlayerID]board.Board),
+	}, nil
+}
+
+// WebhookSignature signs a webhook request body sent at the given unix timestamp with the given secret.
+// The signature is the hex encoded HMAC-SHA256 of the timestamp, a period, and the body, prefixed with "sha256=".
+func WebhookSignature(secret []byte, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Err returns the last problem encountered talking to the service, if any
+func (w *WebhookPlayer) Err() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.lastErr
+}
+
+func (w *WebhookPlayer) recordError(err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.lastErr = err
+}
+
+// newPrompt builds the next prompt of the given type, capturing the current state of the opponents' boards
+func (w *WebhookPlayer) newPrompt(
+	promptType ExternalMessageType,
+	playerBoard board.Board,
+	diceRoll actions.DiceRoll,
+) WebhookPrompt {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.promptID++
+	rejection := w.rejection
+	w.rejection = nil
+	opponentBoards := make(map[PlayerID]board.State, len(w.opponentBoards))
+	for playerID, opponentBoard := range w.opponentBoards {
+		opponentBoards[playerID] = board.StateOf(opponentBoard)
+	}
+	return WebhookPrompt{
+		Type:           promptType,
+		ID:             w.promptID,
+		PlayerName:     w.config.Name,
+		Board:          board.StateOf(playerBoard),
+		DiceRoll:       diceRoll,
+		OpponentBoards: opponentBoards,
+		Rejection:      rejection,
+	}
+}
+
+// post sends the given prompt, retrying failed attempts, and decodes the response into reply
+func (w *WebhookPlayer) post(prompt WebhookPrompt, reply any) error {
+	body, err := json.Marshal(prompt)
+	if err != nil {
+		return err
+	}
+	for attempt := 0; ; attempt++ {
+		retryable, err := w.attempt(body, reply)
+		if err == nil {
+			return nil
+		}
+		if !retryable || attempt >= w.config.Retries {
+			return fmt.Errorf("prompt %v: %w", prompt.ID, err)
+		}
+		time.Sleep(w.config.RetryBackoff * time.Duration(attempt+1))
+	}
+}
+
+// attempt makes a single request, reporting whether a failure is worth retrying
+func (w *WebhookPlayer) attempt(body []byte, reply any) (retryable bool, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), w.config.Timeout)
+	defer cancel()
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, w.config.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	if len(w.config.Secret) > 0 {
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		request.Header.Set(WebhookTimestampHeader, timestamp)
+		request.Header.Set(WebhookSignatureHeader, WebhookSignature(w.config.Secret, timestamp, body))
+	}
+
+	response, err := w.client.Do(request)
+	if err != nil {
+		return true, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode >= 500 {
+		return true, fmt.Errorf("webhook responded %v", response.Status)
+	}
+	if response.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("webhook responded %v", response.Status)
+	}
+	responseBody, err := io.ReadAll(response.Body)
+	if err != nil {
+		return true, err
+	}
+	if err := json.Unmarshal(responseBody, reply); err != nil {
+		return false, fmt.Errorf("malformed response %q: %w", responseBody, err)
+	}
+	return false, nil
+}
+
+func (w *WebhookPlayer) GetName() string {
+	return w.config.Name
+}
+
+func (w *WebhookPlayer) InformOfPlayOrder(playerNames []string) {}
+
+func (w *WebhookPlayer) PromptActivePlayerTurn(
+	playerBoard board.Board,
+	diceRoll actions.DiceRoll,
+) actions.ActivePlayerTurn {
+	var turn actions.ActivePlayerTurn
+	if err := w.post(w.newPrompt(ExternalMessagePromptActive, playerBoard, diceRoll), &turn); err != nil {
+		w.recordError(err)
+		return actions.ActivePlayerTurn{WhiteDiceMove: invalidMove()}
+	}
+	return turn
+}
+
+func (w *WebhookPlayer) PromptInactivePlayerTurn(
+	playerBoard board.Board,
+	diceRoll actions.DiceRoll,
+) actions.InactivePlayerTurn {
+	var turn actions.InactivePlayerTurn
+	if err := w.post(w.newPrompt(ExternalMessagePromptInactive, playerBoard, diceRoll), &turn); err != nil {
+		w.recordError(err)
+		return actions.InactivePlayerTurn{WhiteDiceMove: invalidMove()}
+	}
+	return turn
+}
+
+func (w *WebhookPlayer) InformSuccessfulTurn(updatedBoard board.Board) {}
+
+// InformInvalidTurn remembers why the last answer was rejected, to send it along with the prompt asking again
+func (w *WebhookPlayer) InformInvalidTurn(err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	rejection := board.ViolationOf(err)
+	w.rejection = &rejection
+}
+
+// InformOfOpponentMove crosses off the move on this player's copy of the opponent's board
+func (w *WebhookPlayer) InformOfOpponentMove(playerID PlayerID, move actions.Move) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	opponentBoard, ok := w.opponentBoards[playerID]
+	if !ok {
+		opponentBoard, _ = board.NewBoard(w.config.Variant, board.WithRuleset(w.config.Ruleset))
+		w.opponentBoards[playerID] = opponentBoard
+	}
+	if err := opponentBoard.MakeMove(move); err != nil {
+		w.lastErr = fmt.Errorf("tracking opponent %v: %w", playerID, err)
+	}
+}
+
+// InformRowLocked locks the row on this player's copies of the opponents' boards
+func (w *WebhookPlayer) InformRowLocked(color actions.RowColor) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, opponentBoard := range w.opponentBoards {
+		opponentBoard.LockRow(color)
+	}
+}
+
+func (w *WebhookPlayer) InformWin() {}
+
+func (w *WebhookPlayer) InformLoss(winnerID PlayerID) {}
@@ -0,0 +1,150 @@
+package player
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"qwixx/internal/game/actions"
+	"qwixx/internal/game/board"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestWebhookPlayer(t *testing.T, handler http.HandlerFunc, config WebhookPlayerConfig) *WebhookPlayer {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	config.Name = "webhook"
+	config.URL = server.URL
+	config.RetryBackoff = time.Millisecond
+	p, err := NewWebhookPlayer(config)
+	require.NoError(t, err)
+	return p
+}
+
+func TestNewWebhookPlayer_InvalidConfig(t *testing.T) {
+	_, err := NewWebhookPlayer(WebhookPlayerConfig{URL: "ftp://example.com"})
+	require.Error(t, err)
+	_, err = NewWebhookPlayer(WebhookPlayerConfig{URL: "http://example.com", Retries: -1})
+	require.Error(t, err)
+}
+
+func TestWebhookPlayer_PromptActivePlayerTurn(t *testing.T) {
+	secret := []byte("shh")
+	var received WebhookPrompt
+	p := newTestWebhookPlayer(t, func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		timestamp := r.Header.Get(WebhookTimestampHeader)
+		require.NotEmpty(t, timestamp)
+		require.Equal(t, WebhookSignature(secret, timestamp, body), r.Header.Get(WebhookSignatureHeader))
+		require.NoError(t, json.Unmarshal(body, &received))
+		_, _ = w.Write([]byte(`{"white_dice_move":{"row_color":"Blue","cell_number":9},"color_dice_move":{"row_color":"Blue","cell_number":7}}`))
+	}, WebhookPlayerConfig{Secret: secret})
+
+	p.InformOfOpponentMove("alice", actions.NewMove(actions.RowColorGreen, 11))
+	p.InformRowLocked(actions.RowColorRed)
+	playerBoard := board.NewGameBoard()
+	require.NoError(t, playerBoard.MakeMove(actions.NewMove(actions.RowColorYellow, 3)))
+
+	turn := p.PromptActivePlayerTurn(playerBoard, testDiceRoll)
+	require.Equal(t, actions.ActivePlayerTurn{
+		WhiteDiceMove: &actions.Move{RowColor: actions.RowColorBlue, CellNumber: 9},
+		ColorDiceMove: &actions.Move{RowColor: actions.RowColorBlue, CellNumber: 7},
+	}, turn)
+	require.NoError(t, p.Err())
+
+	require.Equal(t, ExternalMessagePromptActive, received.Type)
+	require.Equal(t, 1, received.ID)
+	require.Equal(t, "webhook", received.PlayerName)
+	require.Equal(t, testDiceRoll, received.DiceRoll)
+	require.Equal(t, []int{3}, received.Board.Rows[actions.RowColorYellow])
+	require.Equal(t, []int{11}, received.OpponentBoards["alice"].Rows[actions.RowColorGreen])
+	require.Equal(t, []actions.RowColor{actions.RowColorRed}, received.OpponentBoards["alice"].Locked)
+}
+
+func TestWebhookPlayer_RetriesServerErrors(t *testing.T) {
+	var requests atomic.Int32
+	p := newTestWebhookPlayer(t, func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{"white_dice_move":{"row_color":"Red","cell_number":9}}`))
+	}, WebhookPlayerConfig{Retries: 2})
+
+	turn := p.PromptInactivePlayerTurn(board.NewGameBoard(), testDiceRoll)
+	require.Equal(t, actions.InactivePlayerTurn{
+		WhiteDiceMove: &actions.Move{RowColor: actions.RowColorRed, CellNumber: 9},
+	}, turn)
+	require.EqualValues(t, 3, requests.Load())
+}
+
+func TestWebhookPlayer_InvalidTurnOnFailure(t *testing.T) {
+	type testCase struct {
+		name             string
+		handler          http.HandlerFunc
+		config           WebhookPlayerConfig
+		expectedRequests int32
+		expectedError    string
+	}
+	testCases := []testCase{
+		{
+			name: "server errors beyond the retries",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusInternalServerError)
+			},
+			config:           WebhookPlayerConfig{Retries: 1},
+			expectedRequests: 2,
+			expectedError:    "500",
+		},
+		{
+			name: "client errors are not retried",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusUnauthorized)
+			},
+			config:           WebhookPlayerConfig{Retries: 3},
+			expectedRequests: 1,
+			expectedError:    "401",
+		},
+		{
+			name: "malformed responses are not retried",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte("pass"))
+			},
+			config:           WebhookPlayerConfig{Retries: 3},
+			expectedRequests: 1,
+			expectedError:    "malformed response",
+		},
+		{
+			name: "slow responses time out",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				select {
+				case <-r.Context().Done():
+				case <-time.After(time.Second):
+				}
+			},
+			config:           WebhookPlayerConfig{Timeout: 50 * time.Millisecond},
+			expectedRequests: 1,
+			expectedError:    "deadline exceeded",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var requests atomic.Int32
+			p := newTestWebhookPlayer(t, func(w http.ResponseWriter, r *http.Request) {
+				requests.Add(1)
+				tc.handler(w, r)
+			}, tc.config)
+
+			turn := p.PromptActivePlayerTurn(board.NewGameBoard(), testDiceRoll)
+			require.Equal(t, invalidMove(), turn.WhiteDiceMove)
+			require.Equal(t, tc.expectedRequests, requests.Load())
+			require.ErrorContains(t, p.Err(), tc.expectedError)
+		})
+	}
+}
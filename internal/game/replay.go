@@ -0,0 +1,149 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"qwixx/internal/game/actions"
+	"qwixx/internal/game/board"
+	"qwixx/internal/game/player"
+	"qwixx/internal/game/ruleset"
+	"qwixx/internal/logging"
+)
+
+// ReplayStep is a decision of a game being replayed, with the board of its player once the decision is made
+type ReplayStep struct {
+	Decision Decision
+	Board    board.Board
+	// Penalties is the number of penalties the player has taken once the decision is made
+	Penalties int
+	// Locked are the rows locked at the end of the turn of the decision, if it is the last decision of its turn,
+	// which are locked on the board too
+	Locked []actions.RowColor
+}
+
+// Replay plays the decisions of the given result again from empty boards, calling the given function after each of them,
+// and checks the replayed game comes to the same boards, penalties and scores as the result, which must be valid.
+// It returns every way the result and its decisions disagree, and stops at the first decision that cannot be made.
+func Replay(result GameResult, onStep func(step ReplayStep)) error {
+	if len(result.Decisions) == 0 {
+		return errors.New("the game has no decisions to replay")
+	}
+	config := result.Config.WithDefaults()
+	variant, rules, err := sheetOf(result)
+	if err != nil {
+		return err
+	}
+	gr := &gameRunnerImpl{
+		boards:    make(map[player.PlayerID]board.Board, len(result.Players)),
+		penalties: make(map[player.PlayerID]int),
+		locks:     make(map[actions.RowColor]bool),
+		logger:    logging.Discard(),
+		variant:   variant,
+	}
+	names := make(map[player.PlayerID]string, len(result.Players))
+	for _, playerResult := range result.Players {
+		gr.boards[playerResult.ID], _ = board.NewBoard(variant, board.WithRuleset(rules))
+		names[playerResult.ID] = playerResult.Name
+	}
+
+	var mismatches []error
+	for idx, decision := range result.Decisions {
+		playerBoard, ok := gr.boards[decision.PlayerID]
+		if !ok {
+			return fmt.Errorf("turn %v: decision of unknown player %v", decision.Turn, decision.PlayerID)
+		}
+		name := names[decision.PlayerID]
+		if err := compareBoards(decision.Board, playerBoard); err != nil {
+			mismatches = append(mismatches, fmt.Errorf("turn %v: board of %v before the decision: %w", decision.Turn, name, err))
+		}
+		if err := replayDecision(gr, decision); err != nil {
+			return errors.Join(append(mismatches, fmt.Errorf("turn %v: decision of %v: %w", decision.Turn, name, err))...)
+		}
+
+		step := ReplayStep{Decision: decision, Board: gr.boards[decision.PlayerID].Copy(), Penalties: gr.penalties[decision.PlayerID]}
+		// rows are locked once every player has made their decision of the turn, as the game runner does
+		if idx == len(result.Decisions)-1 || result.Decisions[idx+1].Turn != decision.Turn {
+			gr.lockCompletedRows(gr.logger)
+			step.Locked = newlyLocked(gr, step.Board)
+			step.Board = gr.boards[decision.PlayerID].Copy()
+		}
+		if onStep != nil {
+			onStep(step)
+		}
+	}
+
+	for _, playerResult := range result.Players {
+		replayed := gr.boards[playerResult.ID]
+		if err := compareBoards(playerResult.Board, replayed); err != nil {
+			mismatches = append(mismatches, fmt.Errorf("final board of %v: %w", playerResult.Name, err))
+		}
+		if penalties := gr.penalties[playerResult.ID]; penalties != playerResult.Penalties {
+			mismatches = append(mismatches, fmt.Errorf("%v took %v penalties, but the result has %v", playerResult.Name, penalties, playerResult.Penalties))
+		}
+		if score := replayed.CalculateScore() - config.PenaltyValue*gr.penalties[playerResult.ID]; score != playerResult.Score {
+			mismatches = append(mismatches, fmt.Errorf("%v scored %v, but the result has %v", playerResult.Name, score, playerResult.Score))
+		}
+	}
+	if err := result.Validate(); err != nil {
+		mismatches = append(mismatches, err)
+	}
+	return errors.Join(mismatches...)
+}
+
+// sheetOf finds the sheet and the rules the boards of the given result are played on
+func sheetOf(result GameResult) (board.Variant, ruleset.Ruleset, error) {
+	state := result.Decisions[0].Board
+	variant := state.Variant
+	if variant == "" {
+		variant = board.VariantClassic
+	}
+	if _, err := board.NewBoard(variant); err != nil {
+		return "", nil, err
+	}
+	rules, err := ruleset.New(state.Ruleset)
+	if err != nil {
+		return "", nil, err
+	}
+	return variant, rules, nil
+}
+
+// replayDecision makes the turn of the given decision on the board of its player, as the game runner does
+func replayDecision(gr *gameRunnerImpl, decision Decision) error {
+	if decision.Active && isActiveTurnPenalty(decision.Chosen) {
+		gr.penalties[decision.PlayerID]++
+		return nil
+	}
+	turn := decision.Chosen
+	if decision.ColorFirst {
+		turn = actions.ActivePlayerTurn{WhiteDiceMove: turn.ColorDiceMove, ColorDiceMove: turn.WhiteDiceMove}
+	}
+	updatedBoard, err := board.ApplyActivePlayerTurn(gr.boards[decision.PlayerID].Copy(), turn)
+	if err != nil {
+		return err
+	}
+	gr.boards[decision.PlayerID] = updatedBoard
+	return nil
+}
+
+// newlyLocked lists the rows locked on the runner's boards that were not yet locked on the given board
+func newlyLocked(gr *gameRunnerImpl, before board.Board) []actions.RowColor {
+	var locked []actions.RowColor
+	for _, rowColor := range gr.variant.RowColors() {
+		if gr.locks[rowColor] && !before.IsRowLocked(rowColor) {
+			locked = append(locked, rowColor)
+		}
+	}
+	return locked
+}
+
+// compareBoards returns an error if the given state is not the state of the given board, naming both in the board notation
+func compareBoards(expected board.State, actual board.Board) error {
+	expectedBoard, err := board.FromState(expected)
+	if err != nil {
+		return err
+	}
+	if expectedText, actualText := board.Format(expectedBoard, 0), board.Format(actual, 0); expectedText != actualText {
+		return fmt.Errorf("expected %q, but replaying gives %q", expectedText, actualText)
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package game
+
+import (
+	"math/rand"
+	"qwixx/internal/game/actions"
+	"qwixx/internal/game/board"
+	"qwixx/internal/game/player"
+	"qwixx/internal/game/ruleset"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func playReplayableGame(t *testing.T, options ...Option) GameResult {
+	t.Helper()
+	players := []player.Player{
+		player.NewStrategyPlayer("alice", mustStrategy(t, player.StrategyGreedy), nil),
+		player.NewStrategyPlayer("bob", mustStrategy(t, player.StrategyCareful), nil),
+		player.NewStrategyPlayer("carol", mustStrategy(t, player.StrategyFirst), nil),
+	}
+	return NewGameRunner(players, append([]Option{WithRand(rand.New(rand.NewSource(7))), WithDecisions()}, options...)...).RunGame()
+}
+
+func TestReplay(t *testing.T) {
+	type testCase struct {
+		name         string
+		inputOptions []Option
+	}
+	testCases := []testCase{
+		{name: "classic"},
+		{name: "big points sheet", inputOptions: []Option{WithVariant(board.VariantBigPoints)}},
+		{name: "connected rules", inputOptions: []Option{WithRuleset(mustRuleset(t, ruleset.NameConnected))}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := playReplayableGame(t, tc.inputOptions...)
+
+			var steps []ReplayStep
+			require.NoError(t, Replay(result, func(step ReplayStep) {
+				steps = append(steps, step)
+			}))
+			require.Len(t, steps, len(result.Decisions))
+			locked := map[actions.RowColor]bool{}
+			for _, step := range steps {
+				for _, rowColor := range step.Locked {
+					require.False(t, locked[rowColor], "%v locked twice", rowColor)
+					locked[rowColor] = true
+					require.True(t, step.Board.IsRowLocked(rowColor))
+				}
+			}
+			if result.EndReason == EndReasonRowsLocked {
+				require.Len(t, locked, result.Config.LocksToEnd)
+			}
+		})
+	}
+}
+
+func TestReplay_Mismatches(t *testing.T) {
+	type testCase struct {
+		name           string
+		inputTamper    func(result *GameResult)
+		expectedErrors []string
+	}
+	testCases := []testCase{
+		{
+			name:           "no decisions",
+			inputTamper:    func(result *GameResult) { result.Decisions = nil },
+			expectedErrors: []string{"the game has no decisions to replay"},
+		},
+		{
+			name: "wrong score",
+			inputTamper: func(result *GameResult) {
+				result.Players[1].Score += 3
+			},
+			expectedErrors: []string{"bob scored ", "score of bob is "},
+		},
+		{
+			name: "decision that cannot be made",
+			inputTamper: func(result *GameResult) {
+				move := actions.NewMove(actions.RowColorRed, 12)
+				result.Decisions[0].Chosen = actions.ActivePlayerTurn{WhiteDiceMove: &move}
+			},
+			expectedErrors: []string{"turn 1: decision of ", "cannot cross off rightmost cell of row"},
+		},
+		{
+			name: "missing penalty",
+			inputTamper: func(result *GameResult) {
+				result.Players[0].Penalties++
+			},
+			expectedErrors: []string{"alice took ", "penalties, but the result has "},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := playReplayableGame(t)
+			// the names in the result are in play order
+			for idx, name := range []string{"alice", "bob", "carol"} {
+				result.Players[idx].Name = name
+			}
+			tc.inputTamper(&result)
+			err := Replay(result, nil)
+			require.Error(t, err)
+			for _, expected := range tc.expectedErrors {
+				require.ErrorContains(t, err, expected)
+			}
+		})
+	}
+}
+
+func mustRuleset(t *testing.T, name string) ruleset.Ruleset {
+	t.Helper()
+	rules, err := ruleset.New(name)
+	require.NoError(t, err)
+	return rules
+}
@@ -0,0 +1,138 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"qwixx/internal/game/actions"
+	"qwixx/internal/game/board"
+	"qwixx/internal/game/player"
+	"slices"
+)
+
+// EndReason is why a game ended
+type EndReason string
+
+const (
+	// EndReasonRowsLocked means as many rows were locked as the house rules end the game at, two by default
+	EndReasonRowsLocked EndReason = "rows_locked"
+	// EndReasonPenalties means a player took as many penalties as the house rules end the game at, four by default
+	EndReasonPenalties EndReason = "penalties"
+	// EndReasonTurnLimit means the game was stopped after too many turns without ending on its own
+	EndReasonTurnLimit EndReason = "turn_limit"
+)
+
+// PlayerResult is how a single player did in a game
+type PlayerResult struct {
+	ID        player.PlayerID `json:"id"`
+	Name      string          `json:"name"`
+	Score     int             `json:"score"`
+	Penalties int             `json:"penalties"`
+	Won       bool            `json:"won"`
+	// Board is the player's board at the end of the game
+	Board board.State `json:"board"`
+}
+
+// GameResult is the outcome of a game
+type GameResult struct {
+	// Players are the results of every player in play order
+	Players []PlayerResult `json:"players"`
+	// Winners are the players with the highest score, more than one if they tied
+	Winners   []player.PlayerID `json:"winners"`
+	Turns     int               `json:"turns"`
+	EndReason EndReason         `json:"end_reason"`
+	// Config is the house rules the game was played by
+	Config GameConfig `json:"config"`
+	// Decisions are the turns every player chose in the order they chose them, to replay and review the game with.
+	// They are only recorded for games run WithDecisions.
+	Decisions []Decision `json:"decisions,omitempty"`
+}
+
+// Decision is a turn a player chose, along with the board and dice they chose it for
+type Decision struct {
+	// Turn is the number of the turn the decision was made in, counting from 1
+	Turn     int             `json:"turn"`
+	PlayerID player.PlayerID `json:"player_id"`
+	// Active is whether the player was the active player, who can use the color dice and takes a penalty by passing
+	Active bool `json:"active"`
+	// Board is the player's board before the chosen turn
+	Board    board.State      `json:"board"`
+	DiceRoll actions.DiceRoll `json:"dice"`
+	// Chosen is the turn the player chose, of which only the white dice move is set for an inactive player,
+	// and both moves are left out for a pass or a penalty
+	Chosen actions.ActivePlayerTurn `json:"chosen"`
+	// ColorFirst is whether the color dice move was made before the white dice move, which the house rules may allow
+	ColorFirst bool `json:"color_first,omitempty"`
+}
+
+// determineWinners finds the players with the highest score
+func determineWinners(players []PlayerResult) []player.PlayerID {
+	var winners []player.PlayerID
+	var highScore int
+	for _, result := range players {
+		switch {
+		case len(winners) == 0 || result.Score > highScore:
+			winners = []player.PlayerID{result.ID}
+			highScore = result.Score
+		case result.Score == highScore:
+			winners = append(winners, result.ID)
+		}
+	}
+	return winners
+}
+
+// Validate checks the result could have come about in a game played by its house rules, returning every invariant it breaks.
+// Every board is checked with board.Validate, accepting rows locked by another player, and on top of that
+// a row locked for one player must be locked for every player and scores must add up from the boards and penalties.
+func (r GameResult) Validate() error {
+	config := r.Config.WithDefaults()
+	var violations []error
+	for idx, playerResult := range r.Players {
+		var lockedByOthers []actions.RowColor
+		for _, rowColor := range playerResult.Board.Locked {
+			for otherIdx, other := range r.Players {
+				if otherIdx != idx && lastCellCrossed(other.Board, rowColor) {
+					lockedByOthers = append(lockedByOthers, rowColor)
+					break
+				}
+			}
+		}
+		err := board.Validate(
+			playerResult.Board, playerResult.Penalties,
+			board.WithMaxPenalties(config.PenaltiesToEnd), board.WithLockedByOthers(lockedByOthers...),
+		)
+		if err != nil {
+			violations = append(violations, fmt.Errorf("board of %v: %w", playerResult.Name, err))
+			continue
+		}
+
+		for _, other := range r.Players {
+			for _, rowColor := range other.Board.Locked {
+				if !slices.Contains(playerResult.Board.Locked, rowColor) {
+					violations = append(violations, fmt.Errorf("row %v is locked for %v but not for %v", rowColor, other.Name, playerResult.Name))
+				}
+			}
+		}
+
+		playerBoard, err := board.FromState(playerResult.Board)
+		if err != nil {
+			violations = append(violations, fmt.Errorf("board of %v: %w", playerResult.Name, err))
+			continue
+		}
+		if score := playerBoard.CalculateScore() - config.PenaltyValue*playerResult.Penalties; score != playerResult.Score {
+			violations = append(violations, fmt.Errorf(
+				"score of %v is %v, but their board and penalties add up to %v", playerResult.Name, playerResult.Score, score,
+			))
+		}
+	}
+	return errors.Join(violations...)
+}
+
+// lastCellCrossed determines if the rightmost cell of the row with the given color is crossed off in the given state
+func lastCellCrossed(state board.State, rowColor actions.RowColor) bool {
+	variant := state.Variant
+	if variant == "" {
+		variant = board.VariantClassic
+	}
+	cells := variant.Cells(rowColor)
+	return len(cells) > 0 && slices.Contains(state.Rows[rowColor], cells[len(cells)-1].Number)
+}
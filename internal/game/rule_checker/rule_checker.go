@@ -1,6 +1,7 @@
 package rule_checker
 
 import (
+	"fmt"
 	"qwixx/internal/game/actions"
 	"qwixx/internal/game/board"
 	"slices"
@@ -14,17 +15,140 @@ import (
 //     d. if the given cell number is the rightmost cell, there are already five other cells crossed off in that row
 //  2. the proposed cell number of the move matches the sum of the two white dice from the dice roll
 func WhiteDiceMoveIsValidForBoard(playerBoard board.Board, diceRoll actions.DiceRoll, proposedMove actions.Move) bool {
-	// TODO do other rule checking here rather than in board? or both? idk
-	boardSaysValid, _ := playerBoard.IsMoveValid(proposedMove)
-	possibleMoves := DeterminePossibleWhiteDiceMoves(diceRoll)
-	return boardSaysValid && slices.Contains(possibleMoves, proposedMove)
+	return ValidateWhiteDiceMove(playerBoard, diceRoll, proposedMove) == nil
 }
 
 func ColorDiceMoveIsValidForBoard(playerBoard board.Board, diceRoll actions.DiceRoll, proposedMove actions.Move) bool {
-	// TODO do other rule checking here rather than in board? or both? idk
-	boardSaysValid, _ := playerBoard.IsMoveValid(proposedMove)
-	possibleMoves := DeterminePossibleColorDiceMoves(diceRoll)
-	return boardSaysValid && slices.Contains(possibleMoves, proposedMove)
+	return ValidateColorDiceMove(playerBoard, diceRoll, proposedMove) == nil
+}
+
+// ValidateWhiteDiceMove returns the rule the given white dice move breaks as a *board.RuleViolation, or nil if the move is valid,
+// see WhiteDiceMoveIsValidForBoard
+func ValidateWhiteDiceMove(playerBoard board.Board, diceRoll actions.DiceRoll, proposedMove actions.Move) error {
+	if err := validateMoveShape(playerBoard, proposedMove); err != nil {
+		return err
+	}
+	if !slices.Contains(PossibleWhiteDiceMoves(playerBoard, diceRoll), proposedMove) {
+		return board.NewRuleViolation(
+			board.CodeSumMismatch,
+			"cell %v is not the sum of the white dice %v and %v", proposedMove.CellNumber, diceRoll.White1, diceRoll.White2,
+		)
+	}
+	_, err := playerBoard.IsMoveValid(proposedMove)
+	return err
+}
+
+// ValidateColorDiceMove returns the rule the given color dice move breaks as a *board.RuleViolation, or nil if the move is valid.
+// On top of what the board checks, the die of the crossed off cell's color must have been rolled,
+// and the cell number must be the sum of that die and one of the white dice.
+// The cell's color is the color of its row, unless the board's variant mixes the colors of the cells.
+func ValidateColorDiceMove(playerBoard board.Board, diceRoll actions.DiceRoll, proposedMove actions.Move) error {
+	if err := validateMoveShape(playerBoard, proposedMove); err != nil {
+		return err
+	}
+	dieColor, ok := playerBoard.Variant().CellColor(proposedMove.RowColor, proposedMove.CellNumber)
+	if !ok {
+		dieColor = proposedMove.RowColor
+	}
+	colorDie := colorDieValue(diceRoll, dieColor)
+	if colorDie < 1 {
+		return board.NewRuleViolation(board.CodeColorMoveWithoutColorDie, "the %v die was not rolled", dieColor)
+	}
+	if !slices.Contains(PossibleColorDiceMoves(playerBoard, diceRoll), proposedMove) {
+		return board.NewRuleViolation(
+			board.CodeSumMismatch,
+			"cell %v is not the sum of the %v die %v and either white die %v or %v",
+			proposedMove.CellNumber, dieColor, colorDie, diceRoll.White1, diceRoll.White2,
+		)
+	}
+	_, err := playerBoard.IsMoveValid(proposedMove)
+	return err
+}
+
+// ValidateActivePlayerTurn returns the rule the given turn breaks, or nil if it is valid.
+// The color dice move is checked against the board after the white dice move is made, without changing the given board.
+// The returned error wraps a *board.RuleViolation and says which of the moves broke the rule.
+func ValidateActivePlayerTurn(playerBoard board.Board, diceRoll actions.DiceRoll, turn actions.ActivePlayerTurn) error {
+	afterWhite := playerBoard
+	if turn.WhiteDiceMove != nil {
+		if err := ValidateWhiteDiceMove(playerBoard, diceRoll, *turn.WhiteDiceMove); err != nil {
+			return fmt.Errorf("white dice move %v: %w", turn.WhiteDiceMove, err)
+		}
+		afterWhite = playerBoard.Copy()
+		if err := afterWhite.MakeMove(*turn.WhiteDiceMove); err != nil {
+			return fmt.Errorf("white dice move %v: %w", turn.WhiteDiceMove, err)
+		}
+	}
+	if turn.ColorDiceMove != nil {
+		if err := ValidateColorDiceMove(afterWhite, diceRoll, *turn.ColorDiceMove); err != nil {
+			return fmt.Errorf("color dice move %v: %w", turn.ColorDiceMove, err)
+		}
+	}
+	return nil
+}
+
+// ValidateActivePlayerTurnInAnyOrder is like ValidateActivePlayerTurn for house rules letting the active player use the color dice
+// before the white dice. A turn whose moves are only valid with the color dice move made first is accepted, returning colorFirst.
+// The returned error is the one the turn breaks in the usual order.
+func ValidateActivePlayerTurnInAnyOrder(
+	playerBoard board.Board,
+	diceRoll actions.DiceRoll,
+	turn actions.ActivePlayerTurn,
+) (colorFirst bool, err error) {
+	err = ValidateActivePlayerTurn(playerBoard, diceRoll, turn)
+	if err == nil || turn.WhiteDiceMove == nil || turn.ColorDiceMove == nil {
+		return false, err
+	}
+	if colorErr := ValidateColorDiceMove(playerBoard, diceRoll, *turn.ColorDiceMove); colorErr != nil {
+		return false, err
+	}
+	afterColor := playerBoard.Copy()
+	if afterColor.MakeMove(*turn.ColorDiceMove) != nil || ValidateWhiteDiceMove(afterColor, diceRoll, *turn.WhiteDiceMove) != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// ValidateInactivePlayerTurn returns the rule the given turn breaks, or nil if it is valid, see ValidateActivePlayerTurn
+func ValidateInactivePlayerTurn(playerBoard board.Board, diceRoll actions.DiceRoll, turn actions.InactivePlayerTurn) error {
+	if turn.WhiteDiceMove == nil {
+		return nil
+	}
+	if err := ValidateWhiteDiceMove(playerBoard, diceRoll, *turn.WhiteDiceMove); err != nil {
+		return fmt.Errorf("white dice move %v: %w", turn.WhiteDiceMove, err)
+	}
+	return nil
+}
+
+// validateMoveShape checks the move names a row of the board's sheet and a cell that exist, before anything looks up dice by its row color
+func validateMoveShape(playerBoard board.Board, move actions.Move) error {
+	if !slices.Contains(playerBoard.Variant().RowColors(), move.RowColor) {
+		return board.NewRuleViolation(board.CodeInvalidColor, "invalid move row color: %d", move.RowColor)
+	}
+	if move.CellNumber < 2 || move.CellNumber > 12 {
+		return board.NewRuleViolation(board.CodeInvalidCellNumber, "invalid cell number: %v. must be between 2 and 12", move.CellNumber)
+	}
+	return nil
+}
+
+// colorDieValue returns the value rolled on the die of the given row color, which is zero if that die was not rolled
+func colorDieValue(diceRoll actions.DiceRoll, rowColor actions.RowColor) int {
+	switch rowColor {
+	case actions.RowColorRed:
+		return diceRoll.Red
+	case actions.RowColorYellow:
+		return diceRoll.Yellow
+	case actions.RowColorGreen:
+		return diceRoll.Green
+	case actions.RowColorBlue:
+		return diceRoll.Blue
+	case actions.RowColorOrange:
+		return diceRoll.Orange
+	case actions.RowColorPurple:
+		return diceRoll.Purple
+	default:
+		return 0
+	}
 }
 
 // DeterminePossibleWhiteDiceMoves determines the possible moves that can be made based on only the white dice from the given dice roll.
@@ -40,6 +164,17 @@ func DeterminePossibleWhiteDiceMoves(diceRoll actions.DiceRoll) []actions.Move {
 	}
 }
 
+// PossibleWhiteDiceMoves determines the possible moves that can be made with the white dice on the sheet of the given board,
+// which are the moves of DeterminePossibleWhiteDiceMoves on the classic sheet, and the sum on each of its rows from top to bottom otherwise
+func PossibleWhiteDiceMoves(playerBoard board.Board, diceRoll actions.DiceRoll) []actions.Move {
+	rowColors := playerBoard.Variant().RowColors()
+	moves := make([]actions.Move, 0, len(rowColors))
+	for _, rowColor := range rowColors {
+		moves = append(moves, actions.NewMove(rowColor, diceRoll.White1+diceRoll.White2))
+	}
+	return moves
+}
+
 // DeterminePossibleColorDiceMoves determines the possible moves that can be made based on the color dice from the given dice roll.
 // This does not take into account the state of any board, just the moves based on the sums from the rolled dice.
 // The sum of either white die and one color die can be played on the row with that die's color,
@@ -58,6 +193,38 @@ func DeterminePossibleColorDiceMoves(diceRoll actions.DiceRoll) []actions.Move {
 	}
 }
 
+// PossibleColorDiceMoves determines the possible moves that can be made with the color dice on the sheet of the given board.
+// Like DeterminePossibleColorDiceMoves it does not check which cells are crossed off,
+// but the sum of either white die and a rolled color die can be played on any cell of that die's color,
+// which on a sheet with mixed colors can be in any row.
+// Moves are ordered by die color, then by white die, then by row, following the order of the rows on the sheet,
+// so on the classic sheet they match DeterminePossibleColorDiceMoves.
+func PossibleColorDiceMoves(playerBoard board.Board, diceRoll actions.DiceRoll) []actions.Move {
+	rowColors := playerBoard.Variant().RowColors()
+	var moves []actions.Move
+	for _, dieColor := range rowColors {
+		colorDie := colorDieValue(diceRoll, dieColor)
+		if colorDie < 1 {
+			continue
+		}
+		for _, sum := range []int{diceRoll.White1 + colorDie, diceRoll.White2 + colorDie} {
+			if !playerBoard.Variant().MixedColors() {
+				// every cell of a row has the row's color, and every row has a cell for every sum
+				moves = append(moves, actions.NewMove(dieColor, sum))
+				continue
+			}
+			for _, rowColor := range rowColors {
+				for _, cell := range playerBoard.Cells(rowColor) {
+					if cell.Color == dieColor && cell.Number == sum {
+						moves = append(moves, actions.NewMove(rowColor, sum))
+					}
+				}
+			}
+		}
+	}
+	return moves
+}
+
 // diceRollSums represents the sums from a dice roll based on the Qwixx rules of summing the different dice colors
 // - The white dice must be summed together to get the white dice sum
 // - Each other color of die can be summed with either of the individual white dice to get the two possible sums for that color
@@ -96,3 +263,74 @@ func determineDiceRollSums(diceRoll actions.DiceRoll) diceRollSums {
 		Blue2:   diceRoll.White2 + diceRoll.Blue,
 	}
 }
+
+// LegalActiveTurns determines every distinct turn the active player can legally take on the given board with the given roll:
+// crossing off a cell with the white dice only, with a color die only, with the white dice and then a color die, or taking a penalty.
+// The color dice move of a combined turn is checked against the board after the white dice move is made,
+// so it can neither cross off the same cell again nor a cell to the left of it in the same row.
+// Turns using only a color die come first, followed by each white dice move and the color dice moves that can follow it,
+// in the order the possible moves are determined, with the penalty, which is always legal, last.
+func LegalActiveTurns(playerBoard board.Board, diceRoll actions.DiceRoll) []actions.ActivePlayerTurn {
+	var turns []actions.ActivePlayerTurn
+	for _, colorDiceMove := range legalMoves(playerBoard, PossibleColorDiceMoves(playerBoard, diceRoll)) {
+		turns = append(turns, actions.ActivePlayerTurn{ColorDiceMove: &colorDiceMove})
+	}
+	for _, whiteDiceMove := range legalMoves(playerBoard, PossibleWhiteDiceMoves(playerBoard, diceRoll)) {
+		turns = append(turns, actions.ActivePlayerTurn{WhiteDiceMove: &whiteDiceMove})
+
+		afterWhite := playerBoard.Copy()
+		_ = afterWhite.MakeMove(whiteDiceMove)
+		for _, colorDiceMove := range legalMoves(afterWhite, PossibleColorDiceMoves(afterWhite, diceRoll)) {
+			// every turn gets its own copy of the white dice move so changing one turn cannot change another
+			white := whiteDiceMove
+			turns = append(turns, actions.ActivePlayerTurn{WhiteDiceMove: &white, ColorDiceMove: &colorDiceMove})
+		}
+	}
+	return append(turns, actions.ActivePlayerTurn{})
+}
+
+// LegalInactiveTurns determines every distinct turn an inactive player can legally take on the given board with the given roll:
+// crossing off a cell with the white dice, ordered like PossibleWhiteDiceMoves, or doing nothing, which is last.
+func LegalInactiveTurns(playerBoard board.Board, diceRoll actions.DiceRoll) []actions.InactivePlayerTurn {
+	var turns []actions.InactivePlayerTurn
+	for _, whiteDiceMove := range legalMoves(playerBoard, PossibleWhiteDiceMoves(playerBoard, diceRoll)) {
+		turns = append(turns, actions.InactivePlayerTurn{WhiteDiceMove: &whiteDiceMove})
+	}
+	return append(turns, actions.InactivePlayerTurn{})
+}
+
+// CrossedCells lists every cell the given valid turn crosses off the given board, in the order of the board's rows and cells,
+// listing a cell once for every time it is crossed off.
+// Besides the moves of the turn, these include the cells the board's ruleset links to them, see ruleset.Connected.
+// An invalid turn crosses off nothing.
+func CrossedCells(playerBoard board.Board, turn actions.ActivePlayerTurn) []actions.Move {
+	after, err := board.ApplyActivePlayerTurn(playerBoard.Copy(), turn)
+	if err != nil {
+		return nil
+	}
+	var crossed []actions.Move
+	for _, rowColor := range playerBoard.Variant().RowColors() {
+		for _, cell := range playerBoard.Cells(rowColor) {
+			for cross := playerBoard.CrossCount(rowColor, cell.Number); cross < after.CrossCount(rowColor, cell.Number); cross++ {
+				crossed = append(crossed, actions.NewMove(rowColor, cell.Number))
+			}
+		}
+	}
+	return crossed
+}
+
+// legalMoves filters the given possible moves down to the distinct ones the board allows
+func legalMoves(playerBoard board.Board, possibleMoves []actions.Move) []actions.Move {
+	var legal []actions.Move
+	seen := make(map[actions.Move]bool, len(possibleMoves))
+	for _, move := range possibleMoves {
+		if seen[move] {
+			continue
+		}
+		seen[move] = true
+		if ok, _ := playerBoard.IsMoveValid(move); ok {
+			legal = append(legal, move)
+		}
+	}
+	return legal
+}
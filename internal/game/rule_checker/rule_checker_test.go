@@ -3,6 +3,7 @@ package rule_checker
 import (
 	"qwixx/internal/game/actions"
 	"qwixx/internal/game/board"
+	"qwixx/internal/game/ruleset"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -335,3 +336,455 @@ func TestColorDiceMoveIsValidForBoard(t *testing.T) {
 		})
 	}
 }
+
+func move(rowColor actions.RowColor, cellNumber int) *actions.Move {
+	m := actions.NewMove(rowColor, cellNumber)
+	return &m
+}
+
+func TestLegalActiveTurns(t *testing.T) {
+	snakeEyes := actions.DiceRoll{
+		WhiteDiceRoll: actions.WhiteDiceRoll{White1: 1, White2: 1},
+		ColorDiceRoll: actions.ColorDiceRoll{Red: 1, Yellow: 1, Green: 1, Blue: 1},
+	}
+	lockedBoard, err := board.FromState(board.State{Locked: []actions.RowColor{
+		actions.RowColorRed, actions.RowColorYellow, actions.RowColorGreen, actions.RowColorBlue,
+	}})
+	require.NoError(t, err)
+
+	type testCase struct {
+		name          string
+		inputBoard    board.Board
+		inputDiceRoll actions.DiceRoll
+		expectedTurns []actions.ActivePlayerTurn
+	}
+	testCases := []testCase{
+		{
+			// every sum is 2, which can't lock the descending rows yet, and each die can only cross off a 2 once
+			name:          "duplicate sums are listed once and the white dice move changes the board before the color dice move",
+			inputBoard:    board.NewGameBoard(),
+			inputDiceRoll: snakeEyes,
+			expectedTurns: []actions.ActivePlayerTurn{
+				{ColorDiceMove: move(actions.RowColorRed, 2)},
+				{ColorDiceMove: move(actions.RowColorYellow, 2)},
+				{WhiteDiceMove: move(actions.RowColorRed, 2)},
+				{WhiteDiceMove: move(actions.RowColorRed, 2), ColorDiceMove: move(actions.RowColorYellow, 2)},
+				{WhiteDiceMove: move(actions.RowColorYellow, 2)},
+				{WhiteDiceMove: move(actions.RowColorYellow, 2), ColorDiceMove: move(actions.RowColorRed, 2)},
+				{},
+			},
+		},
+		{
+			name:          "only the penalty is left when every row is locked",
+			inputBoard:    lockedBoard,
+			inputDiceRoll: snakeEyes,
+			expectedTurns: []actions.ActivePlayerTurn{{}},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expectedTurns, LegalActiveTurns(tc.inputBoard, tc.inputDiceRoll))
+		})
+	}
+}
+
+// TestLegalActiveTurns_MatchesBruteForce checks the generated turns against every combination of possible moves
+// for every roll of the dice on a board that is part way through a game
+func TestLegalActiveTurns_MatchesBruteForce(t *testing.T) {
+	playerBoard, err := board.FromState(board.State{Rows: map[actions.RowColor][]int{
+		actions.RowColorRed:   {2, 4, 5, 6, 8},
+		actions.RowColorGreen: {12, 9},
+		actions.RowColorBlue:  {11, 10, 8, 7, 6},
+	}})
+	require.NoError(t, err)
+
+	isLegal := func(turn actions.ActivePlayerTurn, diceRoll actions.DiceRoll) bool {
+		afterTurn := playerBoard.Copy()
+		if turn.WhiteDiceMove != nil {
+			if !WhiteDiceMoveIsValidForBoard(afterTurn, diceRoll, *turn.WhiteDiceMove) || afterTurn.MakeMove(*turn.WhiteDiceMove) != nil {
+				return false
+			}
+		}
+		if turn.ColorDiceMove != nil {
+			return ColorDiceMoveIsValidForBoard(afterTurn, diceRoll, *turn.ColorDiceMove)
+		}
+		return true
+	}
+
+	for roll := 0; roll < 6*6*6*6*6*6; roll++ {
+		// each die is one base six digit of the roll
+		die := func(idx int) int {
+			value := roll
+			for ; idx > 0; idx-- {
+				value /= 6
+			}
+			return value%6 + 1
+		}
+		diceRoll := actions.DiceRoll{
+			WhiteDiceRoll: actions.WhiteDiceRoll{White1: die(0), White2: die(1)},
+			ColorDiceRoll: actions.ColorDiceRoll{Red: die(2), Yellow: die(3), Green: die(4), Blue: die(5)},
+		}
+
+		expected := map[string]bool{}
+		whiteDiceMoves := []*actions.Move{nil}
+		for _, m := range DeterminePossibleWhiteDiceMoves(diceRoll) {
+			whiteDiceMoves = append(whiteDiceMoves, &m)
+		}
+		colorDiceMoves := []*actions.Move{nil}
+		for _, m := range DeterminePossibleColorDiceMoves(diceRoll) {
+			colorDiceMoves = append(colorDiceMoves, &m)
+		}
+		for _, whiteDiceMove := range whiteDiceMoves {
+			for _, colorDiceMove := range colorDiceMoves {
+				turn := actions.ActivePlayerTurn{WhiteDiceMove: whiteDiceMove, ColorDiceMove: colorDiceMove}
+				if isLegal(turn, diceRoll) {
+					expected[turn.String()] = true
+				}
+			}
+		}
+
+		actual := map[string]bool{}
+		for _, turn := range LegalActiveTurns(playerBoard, diceRoll) {
+			require.False(t, actual[turn.String()], "turn %v is listed twice for %+v", turn, diceRoll)
+			actual[turn.String()] = true
+		}
+		require.Equal(t, expected, actual, "legal turns for %+v", diceRoll)
+	}
+}
+
+func TestLegalInactiveTurns(t *testing.T) {
+	playerBoard, err := board.FromState(board.State{
+		Rows:   map[actions.RowColor][]int{actions.RowColorRed: {9}},
+		Locked: []actions.RowColor{actions.RowColorBlue},
+	})
+	require.NoError(t, err)
+	diceRoll := actions.DiceRoll{
+		WhiteDiceRoll: actions.WhiteDiceRoll{White1: 4, White2: 5},
+		ColorDiceRoll: actions.ColorDiceRoll{Red: 1, Yellow: 1, Green: 1, Blue: 1},
+	}
+
+	// red 9 is already crossed off and the blue row is locked
+	require.Equal(t, []actions.InactivePlayerTurn{
+		{WhiteDiceMove: move(actions.RowColorYellow, 9)},
+		{WhiteDiceMove: move(actions.RowColorGreen, 9)},
+		{},
+	}, LegalInactiveTurns(playerBoard, diceRoll))
+}
+
+var testDiceRoll = actions.DiceRoll{
+	WhiteDiceRoll: actions.WhiteDiceRoll{White1: 4, White2: 5},
+	ColorDiceRoll: actions.ColorDiceRoll{Red: 4, Yellow: 3, Green: 6, Blue: 2},
+}
+
+func TestValidateActivePlayerTurn(t *testing.T) {
+	crossedBoard, err := board.FromState(board.State{
+		Rows:   map[actions.RowColor][]int{actions.RowColorRed: {9}, actions.RowColorGreen: {10}},
+		Locked: []actions.RowColor{actions.RowColorBlue},
+	})
+	require.NoError(t, err)
+	withoutRedDie := testDiceRoll
+	withoutRedDie.Red = 0
+
+	type testCase struct {
+		name          string
+		inputBoard    board.Board
+		inputDiceRoll actions.DiceRoll
+		inputTurn     actions.ActivePlayerTurn
+		expectedErr   error
+		expectedText  string
+	}
+	testCases := []testCase{
+		{
+			name:          "valid turn",
+			inputBoard:    board.NewGameBoard(),
+			inputDiceRoll: testDiceRoll,
+			inputTurn:     actions.ActivePlayerTurn{WhiteDiceMove: move(actions.RowColorBlue, 9), ColorDiceMove: move(actions.RowColorBlue, 7)},
+		},
+		{
+			name:          "white dice sum mismatch",
+			inputBoard:    board.NewGameBoard(),
+			inputDiceRoll: testDiceRoll,
+			inputTurn:     actions.ActivePlayerTurn{WhiteDiceMove: move(actions.RowColorRed, 10)},
+			expectedErr:   board.ErrSumMismatch,
+			expectedText:  "white dice move (Red 10): cell 10 is not the sum of the white dice 4 and 5",
+		},
+		{
+			name:          "color dice sum mismatch",
+			inputBoard:    board.NewGameBoard(),
+			inputDiceRoll: testDiceRoll,
+			inputTurn:     actions.ActivePlayerTurn{ColorDiceMove: move(actions.RowColorYellow, 4)},
+			expectedErr:   board.ErrSumMismatch,
+			expectedText:  "color dice move (Yellow 4): cell 4 is not the sum of the Yellow die 3 and either white die 4 or 5",
+		},
+		{
+			name:          "white dice move on an already crossed off cell",
+			inputBoard:    crossedBoard,
+			inputDiceRoll: testDiceRoll,
+			inputTurn:     actions.ActivePlayerTurn{WhiteDiceMove: move(actions.RowColorRed, 9)},
+			expectedErr:   board.ErrCellAlreadyCrossed,
+			expectedText:  "white dice move (Red 9): cell 9 is already crossed off",
+		},
+		{
+			name:          "color dice move crossing off the cell of the white dice move again",
+			inputBoard:    board.NewGameBoard(),
+			inputDiceRoll: testDiceRoll,
+			inputTurn:     actions.ActivePlayerTurn{WhiteDiceMove: move(actions.RowColorRed, 9), ColorDiceMove: move(actions.RowColorRed, 9)},
+			expectedErr:   board.ErrCellAlreadyCrossed,
+			expectedText:  "color dice move (Red 9): cell 9 is already crossed off",
+		},
+		{
+			name:          "color dice move left of the white dice move",
+			inputBoard:    board.NewGameBoard(),
+			inputDiceRoll: testDiceRoll,
+			inputTurn:     actions.ActivePlayerTurn{WhiteDiceMove: move(actions.RowColorRed, 9), ColorDiceMove: move(actions.RowColorRed, 8)},
+			expectedErr:   board.ErrLeftOfCrossedCell,
+			expectedText:  "color dice move (Red 8): cell 8 is to the left of already crossed off cells",
+		},
+		{
+			name:          "move in a locked row",
+			inputBoard:    crossedBoard,
+			inputDiceRoll: testDiceRoll,
+			inputTurn:     actions.ActivePlayerTurn{ColorDiceMove: move(actions.RowColorBlue, 7)},
+			expectedErr:   board.ErrRowLocked,
+			expectedText:  "color dice move (Blue 7): row is locked",
+		},
+		{
+			name: "rightmost cell with too few crosses",
+			// 6 and 6 on the white dice
+			inputBoard:    board.NewGameBoard(),
+			inputDiceRoll: actions.DiceRoll{WhiteDiceRoll: actions.WhiteDiceRoll{White1: 6, White2: 6}},
+			inputTurn:     actions.ActivePlayerTurn{WhiteDiceMove: move(actions.RowColorRed, 12)},
+			expectedErr:   board.ErrNotEnoughForLock,
+			expectedText:  "white dice move (Red 12): cannot cross off rightmost cell of row unless 5 cells have been crossed off in that row",
+		},
+		{
+			name:          "cell number off the board",
+			inputBoard:    board.NewGameBoard(),
+			inputDiceRoll: testDiceRoll,
+			inputTurn:     actions.ActivePlayerTurn{WhiteDiceMove: move(actions.RowColorRed, 1)},
+			expectedErr:   board.ErrInvalidCellNumber,
+			expectedText:  "white dice move (Red 1): invalid cell number: 1. must be between 2 and 12",
+		},
+		{
+			name:          "unknown row color",
+			inputBoard:    board.NewGameBoard(),
+			inputDiceRoll: testDiceRoll,
+			inputTurn:     actions.ActivePlayerTurn{ColorDiceMove: move(actions.RowColor(7), 7)},
+			expectedErr:   board.ErrInvalidColor,
+		},
+		{
+			name:          "color dice move without its die",
+			inputBoard:    board.NewGameBoard(),
+			inputDiceRoll: withoutRedDie,
+			inputTurn:     actions.ActivePlayerTurn{ColorDiceMove: move(actions.RowColorRed, 4)},
+			expectedErr:   board.ErrColorMoveWithoutColorDie,
+			expectedText:  "color dice move (Red 4): the Red die was not rolled",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateActivePlayerTurn(tc.inputBoard, tc.inputDiceRoll, tc.inputTurn)
+			if tc.expectedErr == nil {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, tc.expectedErr)
+			if tc.expectedText != "" {
+				require.EqualError(t, err, tc.expectedText)
+			}
+		})
+	}
+}
+
+func TestValidateInactivePlayerTurn(t *testing.T) {
+	require.NoError(t, ValidateInactivePlayerTurn(board.NewGameBoard(), testDiceRoll, actions.InactivePlayerTurn{}))
+	require.NoError(t, ValidateInactivePlayerTurn(board.NewGameBoard(), testDiceRoll, actions.InactivePlayerTurn{WhiteDiceMove: move(actions.RowColorGreen, 9)}))
+
+	err := ValidateInactivePlayerTurn(board.NewGameBoard(), testDiceRoll, actions.InactivePlayerTurn{WhiteDiceMove: move(actions.RowColorGreen, 7)})
+	require.ErrorIs(t, err, board.ErrSumMismatch)
+	require.Equal(t, board.CodeSumMismatch, board.ViolationCode(err))
+}
+
+func TestPossibleColorDiceMoves(t *testing.T) {
+	mixxColors, err := board.NewBoard(board.VariantMixxColors)
+	require.NoError(t, err)
+	mixxNumbers, err := board.NewBoard(board.VariantMixxNumbers)
+	require.NoError(t, err)
+
+	// rows with one color can only be crossed off with their own die, whatever the order of their numbers
+	require.Equal(t, DeterminePossibleColorDiceMoves(testDiceRoll), PossibleColorDiceMoves(board.NewGameBoard(), testDiceRoll))
+	require.Equal(t, DeterminePossibleColorDiceMoves(testDiceRoll), PossibleColorDiceMoves(mixxNumbers, testDiceRoll))
+
+	// with mixed colors the red die makes 8 and 9, which are red cells in the yellow and green rows,
+	// while no cell making 10 or 11 is green
+	require.Equal(t, []actions.Move{
+		actions.NewMove(actions.RowColorYellow, 8),
+		actions.NewMove(actions.RowColorGreen, 8),
+		actions.NewMove(actions.RowColorYellow, 9),
+		actions.NewMove(actions.RowColorGreen, 9),
+		actions.NewMove(actions.RowColorGreen, 7),
+		actions.NewMove(actions.RowColorBlue, 8),
+		actions.NewMove(actions.RowColorYellow, 6),
+		actions.NewMove(actions.RowColorRed, 7),
+	}, PossibleColorDiceMoves(mixxColors, testDiceRoll))
+}
+
+func TestValidateColorDiceMove_MixxColors(t *testing.T) {
+	type testCase struct {
+		name          string
+		inputDiceRoll actions.DiceRoll
+		inputMove     actions.Move
+		expectedErr   error
+		expectedText  string
+	}
+	mixxBoard, err := board.NewBoard(board.VariantMixxColors)
+	require.NoError(t, err)
+	noBlue := testDiceRoll
+	noBlue.Blue = 0
+
+	testCases := []testCase{
+		{
+			name:          "blue cell of the red row crossed off with the blue die",
+			inputDiceRoll: testDiceRoll,
+			inputMove:     actions.NewMove(actions.RowColorRed, 7),
+		},
+		{
+			name:          "cell crossed off with the die of its row rather than its own",
+			inputDiceRoll: testDiceRoll,
+			inputMove:     actions.NewMove(actions.RowColorRed, 8),
+			expectedErr:   board.ErrSumMismatch,
+			expectedText:  "cell 8 is not the sum of the Blue die 2 and either white die 4 or 5",
+		},
+		{
+			name:          "die of the cell's color not rolled",
+			inputDiceRoll: noBlue,
+			inputMove:     actions.NewMove(actions.RowColorRed, 7),
+			expectedErr:   board.ErrColorMoveWithoutColorDie,
+			expectedText:  "the Blue die was not rolled",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateColorDiceMove(mixxBoard, tc.inputDiceRoll, tc.inputMove)
+			if tc.expectedErr == nil {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, tc.expectedErr)
+			require.EqualError(t, err, tc.expectedText)
+		})
+	}
+
+	// every legal turn is one the validator accepts
+	for _, turn := range LegalActiveTurns(mixxBoard, testDiceRoll) {
+		require.NoError(t, ValidateActivePlayerTurn(mixxBoard, testDiceRoll, turn))
+	}
+}
+
+func TestBigPointsMoves(t *testing.T) {
+	bigPoints, err := board.NewBoard(board.VariantBigPoints)
+	require.NoError(t, err)
+	diceRoll := testDiceRoll
+	diceRoll.Orange, diceRoll.Purple = 1, 5
+
+	require.Equal(t, []actions.Move{
+		actions.NewMove(actions.RowColorRed, 9),
+		actions.NewMove(actions.RowColorOrange, 9),
+		actions.NewMove(actions.RowColorYellow, 9),
+		actions.NewMove(actions.RowColorGreen, 9),
+		actions.NewMove(actions.RowColorPurple, 9),
+		actions.NewMove(actions.RowColorBlue, 9),
+	}, PossibleWhiteDiceMoves(bigPoints, diceRoll))
+	require.Equal(t, DeterminePossibleWhiteDiceMoves(diceRoll), PossibleWhiteDiceMoves(board.NewGameBoard(), diceRoll))
+
+	colorMoves := PossibleColorDiceMoves(bigPoints, diceRoll)
+	require.Len(t, colorMoves, 12)
+	require.Contains(t, colorMoves, actions.NewMove(actions.RowColorOrange, 5))
+	require.Contains(t, colorMoves, actions.NewMove(actions.RowColorPurple, 10))
+	// the extra dice are not part of a classic game even when rolled
+	require.Equal(t, DeterminePossibleColorDiceMoves(diceRoll), PossibleColorDiceMoves(board.NewGameBoard(), diceRoll))
+
+	// the purple die makes 9, but the bonus cell needs the blue 9 crossed off first, which the white dice can do
+	err = ValidateActivePlayerTurn(bigPoints, diceRoll, actions.ActivePlayerTurn{ColorDiceMove: move(actions.RowColorPurple, 9)})
+	require.ErrorIs(t, err, board.ErrBonusWithoutNeighbor)
+	require.NoError(t, ValidateActivePlayerTurn(bigPoints, diceRoll, actions.ActivePlayerTurn{
+		WhiteDiceMove: move(actions.RowColorBlue, 9),
+		ColorDiceMove: move(actions.RowColorPurple, 9),
+	}))
+	require.Contains(t, LegalActiveTurns(bigPoints, diceRoll), actions.ActivePlayerTurn{
+		WhiteDiceMove: move(actions.RowColorBlue, 9),
+		ColorDiceMove: move(actions.RowColorPurple, 9),
+	})
+
+	err = ValidateWhiteDiceMove(board.NewGameBoard(), diceRoll, actions.NewMove(actions.RowColorOrange, 9))
+	require.ErrorIs(t, err, board.ErrInvalidColor)
+}
+
+func TestCrossedCells(t *testing.T) {
+	connectedBoard, err := board.NewBoard(board.VariantClassic, board.WithRuleset(ruleset.Connected()))
+	require.NoError(t, err)
+	doubleBoard, err := board.NewBoard(board.VariantClassic, board.WithRuleset(ruleset.Double()))
+	require.NoError(t, err)
+
+	// red 9 is chained to yellow 9 on the connected sheet
+	turn := actions.ActivePlayerTurn{WhiteDiceMove: move(actions.RowColorRed, 9), ColorDiceMove: move(actions.RowColorBlue, 7)}
+	require.Equal(t, []actions.Move{
+		actions.NewMove(actions.RowColorRed, 9),
+		actions.NewMove(actions.RowColorYellow, 9),
+		actions.NewMove(actions.RowColorBlue, 7),
+	}, CrossedCells(connectedBoard, turn))
+	require.False(t, connectedBoard.IsCellMarked(actions.RowColorRed, 9))
+	require.Equal(t, []actions.Move{
+		actions.NewMove(actions.RowColorRed, 9),
+		actions.NewMove(actions.RowColorBlue, 7),
+	}, CrossedCells(board.NewGameBoard(), turn))
+
+	// a cell crossed off twice in one turn is listed twice
+	twice := actions.ActivePlayerTurn{WhiteDiceMove: move(actions.RowColorRed, 9), ColorDiceMove: move(actions.RowColorRed, 9)}
+	require.Equal(t, []actions.Move{
+		actions.NewMove(actions.RowColorRed, 9),
+		actions.NewMove(actions.RowColorRed, 9),
+	}, CrossedCells(doubleBoard, twice))
+	require.Nil(t, CrossedCells(board.NewGameBoard(), twice))
+}
+
+func TestValidateActivePlayerTurnInAnyOrder(t *testing.T) {
+	type testCase struct {
+		name               string
+		inputTurn          actions.ActivePlayerTurn
+		expectedColorFirst bool
+		expectedErr        error
+	}
+	diceRoll := actions.DiceRoll{
+		WhiteDiceRoll: actions.WhiteDiceRoll{White1: 4, White2: 5},
+		ColorDiceRoll: actions.ColorDiceRoll{Red: 4, Yellow: 3, Green: 6, Blue: 2},
+	}
+	testCases := []testCase{
+		{
+			name:      "white dice first",
+			inputTurn: actions.ActivePlayerTurn{WhiteDiceMove: move(actions.RowColorRed, 9), ColorDiceMove: move(actions.RowColorBlue, 6)},
+		},
+		{
+			name:               "color die first",
+			inputTurn:          actions.ActivePlayerTurn{WhiteDiceMove: move(actions.RowColorRed, 9), ColorDiceMove: move(actions.RowColorRed, 8)},
+			expectedColorFirst: true,
+		},
+		{
+			name:        "invalid either way",
+			inputTurn:   actions.ActivePlayerTurn{WhiteDiceMove: move(actions.RowColorRed, 9), ColorDiceMove: move(actions.RowColorRed, 9)},
+			expectedErr: board.ErrCellAlreadyCrossed,
+		},
+		{
+			name:        "color die only",
+			inputTurn:   actions.ActivePlayerTurn{ColorDiceMove: move(actions.RowColorRed, 10)},
+			expectedErr: board.ErrSumMismatch,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			colorFirst, err := ValidateActivePlayerTurnInAnyOrder(board.NewGameBoard(), diceRoll, tc.inputTurn)
+			require.ErrorIs(t, err, tc.expectedErr)
+			require.Equal(t, tc.expectedColorFirst, colorFirst)
+		})
+	}
+}
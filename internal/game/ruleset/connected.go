@@ -0,0 +1,46 @@
+package ruleset
+
+import (
+	"qwixx/internal/game/actions"
+)
+
+var _ Ruleset = connected{}
+
+// connectedLinks are the chains of the Connected sheet, each joining a cell to the cell in the same column of the row below it.
+// On the classic sheet the ascending red and yellow rows line up by number,
+// while a column of the yellow and green rows holds numbers adding up to 14.
+var connectedLinks = [][2]actions.Move{
+	{actions.NewMove(actions.RowColorRed, 4), actions.NewMove(actions.RowColorYellow, 4)},
+	{actions.NewMove(actions.RowColorRed, 9), actions.NewMove(actions.RowColorYellow, 9)},
+	{actions.NewMove(actions.RowColorYellow, 6), actions.NewMove(actions.RowColorGreen, 8)},
+	{actions.NewMove(actions.RowColorYellow, 11), actions.NewMove(actions.RowColorGreen, 3)},
+	{actions.NewMove(actions.RowColorGreen, 10), actions.NewMove(actions.RowColorBlue, 10)},
+	{actions.NewMove(actions.RowColorGreen, 5), actions.NewMove(actions.RowColorBlue, 5)},
+}
+
+// Connected returns the rules where some cells are chained to the cell below or above them in a neighboring row.
+// Crossing off one end of a chain also crosses off the other end, if the usual rules allow it.
+func Connected() Ruleset {
+	return connected{}
+}
+
+type connected struct {
+	classic
+}
+
+func (connected) Name() string {
+	return NameConnected
+}
+
+func (connected) LinkedMoves(move actions.Move) []actions.Move {
+	var linked []actions.Move
+	for _, link := range connectedLinks {
+		switch move {
+		case link[0]:
+			linked = append(linked, link[1])
+		case link[1]:
+			linked = append(linked, link[0])
+		}
+	}
+	return linked
+}
@@ -0,0 +1,26 @@
+package ruleset
+
+import (
+	"qwixx/internal/game/actions"
+)
+
+var _ Ruleset = double{}
+
+// Double returns the rules where every cell but the rightmost one can be crossed off twice.
+// A cell can only be crossed off again while no cell to its right is crossed off,
+// and each cross counts towards locking the row and towards its score.
+func Double() Ruleset {
+	return double{}
+}
+
+type double struct {
+	classic
+}
+
+func (double) Name() string {
+	return NameDouble
+}
+
+func (double) MaxCrosses(rowColor actions.RowColor, cellNumber int) int {
+	return 2
+}
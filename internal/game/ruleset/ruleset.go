@@ -0,0 +1,78 @@
+// Package ruleset defines the rules for crossing off cells that published variants of Qwixx change.
+// The board's rows, the rule checker and the game runner consult a game's Ruleset,
+// so a variant's crossing rules can be plugged in without changing how moves are validated.
+package ruleset
+
+import (
+	"fmt"
+	"qwixx/internal/game/actions"
+	"strings"
+)
+
+// Ruleset decides how the cells of a board can be crossed off
+type Ruleset interface {
+	// Name identifies the ruleset, one of Names
+	Name() string
+	// MaxCrosses is how many times the cell with the given number in the row with the given color can be crossed off.
+	// The rightmost cell of a row, which locks it, is only ever crossed off once.
+	MaxCrosses(rowColor actions.RowColor, cellNumber int) int
+	// CrossesToLock is how many crosses a row needs before its rightmost cell can be crossed off
+	CrossesToLock() int
+	// LinkedMoves lists the cells crossed off along with the cell of the given move, whenever the usual rules allow it.
+	// Links are only followed one step, so a linked cell does not cross off the cells linked to it.
+	LinkedMoves(move actions.Move) []actions.Move
+}
+
+const (
+	// NameClassic is the name of the rules of the original game
+	NameClassic = "classic"
+	// NameConnected is the name of the rules where some cells are chained to a cell in a neighboring row, see Connected
+	NameConnected = "connected"
+	// NameDouble is the name of the rules where cells can be crossed off twice, see Double
+	NameDouble = "double"
+)
+
+// Names lists the names of the rulesets New knows
+func Names() []string {
+	return []string{NameClassic, NameConnected, NameDouble}
+}
+
+// New creates the ruleset with the given name, an empty name meaning the classic rules
+func New(name string) (Ruleset, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", NameClassic:
+		return Classic(), nil
+	case NameConnected:
+		return Connected(), nil
+	case NameDouble:
+		return Double(), nil
+	default:
+		return nil, fmt.Errorf("unknown ruleset %q, expected one of %v", name, strings.Join(Names(), ", "))
+	}
+}
+
+var _ Ruleset = classic{}
+
+// Classic returns the rules of the original game:
+// every cell is crossed off once, a row needs five crosses before it can be locked, and no cells are linked
+func Classic() Ruleset {
+	return classic{}
+}
+
+type classic struct{}
+
+func (classic) Name() string {
+	return NameClassic
+}
+
+func (classic) MaxCrosses(rowColor actions.RowColor, cellNumber int) int {
+	return 1
+}
+
+func (classic) CrossesToLock() int {
+	return 5
+}
+
+func (classic) LinkedMoves(move actions.Move) []actions.Move {
+	return nil
+}
@@ -0,0 +1,54 @@
+package ruleset
+
+import (
+	"qwixx/internal/game/actions"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	type testCase struct {
+		name         string
+		input        string
+		expectedName string
+		expectedErr  bool
+	}
+	testCases := []testCase{
+		{name: "empty is classic", input: "", expectedName: NameClassic},
+		{name: "classic", input: "classic", expectedName: NameClassic},
+		{name: "connected ignoring case and spaces", input: " Connected ", expectedName: NameConnected},
+		{name: "double", input: "double", expectedName: NameDouble},
+		{name: "unknown", input: "triple", expectedErr: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rules, err := New(tc.input)
+			if tc.expectedErr {
+				require.ErrorContains(t, err, "connected")
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedName, rules.Name())
+			require.Contains(t, Names(), rules.Name())
+		})
+	}
+}
+
+func TestRulesets(t *testing.T) {
+	red4 := actions.NewMove(actions.RowColorRed, 4)
+	yellow4 := actions.NewMove(actions.RowColorYellow, 4)
+
+	require.Equal(t, 1, Classic().MaxCrosses(actions.RowColorRed, 4))
+	require.Equal(t, 5, Classic().CrossesToLock())
+	require.Empty(t, Classic().LinkedMoves(red4))
+
+	require.Equal(t, []actions.Move{yellow4}, Connected().LinkedMoves(red4))
+	require.Equal(t, []actions.Move{red4}, Connected().LinkedMoves(yellow4))
+	require.Empty(t, Connected().LinkedMoves(actions.NewMove(actions.RowColorRed, 5)))
+	require.Equal(t, 1, Connected().MaxCrosses(actions.RowColorRed, 4))
+
+	require.Equal(t, 2, Double().MaxCrosses(actions.RowColorRed, 4))
+	require.Equal(t, 5, Double().CrossesToLock())
+	require.Empty(t, Double().LinkedMoves(red4))
+}
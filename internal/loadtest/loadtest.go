@@ -0,0 +1,304 @@
+// Package loadtest measures how a running server copes with many games at once,
+// by playing them with simulated websocket clients that choose their turns with bot strategies
+package loadtest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"qwixx/client"
+	"qwixx/internal/game/actions"
+	"qwixx/internal/game/board"
+	"qwixx/internal/game/player"
+	"qwixx/internal/server"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Config is the load a test puts on the server
+type Config struct {
+	// ServerURL is the websocket url of the server, such as ws://localhost:8080/ws
+	ServerURL string
+	// Games is the number of games played at the same time, each by PlayersPerGame clients
+	Games          int
+	PlayersPerGame int
+	// Strategy is the strategy each client chooses its turns with, one of player.StrategyNames.
+	// Its turns are chosen by the load tester, so a costly strategy slows the clients down as much as the server.
+	Strategy string
+	// Ramp is how long the starts of the games are spread over, all games starting at once if zero
+	Ramp time.Duration
+	// Timeout is how long a game may take, from connecting its clients to the end of the game, before its clients give up
+	Timeout time.Duration
+	// StatsInterval is how often the stats of the server are sampled while the games are played
+	StatsInterval time.Duration
+	// Seed seeds the strategies of the clients that need randomness
+	Seed int64
+}
+
+func (c Config) validate() error {
+	if _, err := url.Parse(c.ServerURL); err != nil || c.ServerURL == "" {
+		return fmt.Errorf("invalid server url %q", c.ServerURL)
+	}
+	if c.Games < 1 {
+		return errors.New("at least one game is needed")
+	}
+	if c.PlayersPerGame < 2 {
+		return errors.New("a game needs at least two players")
+	}
+	if c.Timeout <= 0 {
+		return errors.New("the timeout must be positive")
+	}
+	if c.StatsInterval <= 0 {
+		return errors.New("the stats interval must be positive")
+	}
+	_, err := player.NewStrategy(c.Strategy, nil)
+	return err
+}
+
+// Run plays the games of the given load test against its server, and reports how long the server took to answer
+func Run(config Config) (Report, error) {
+	if err := config.validate(); err != nil {
+		return Report{}, err
+	}
+	rec := &recorder{}
+	sampler := newStatsSampler(statsURL(config.ServerURL))
+	stopSampling := sampler.sampleEvery(config.StatsInterval)
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for idx := 0; idx < config.Games; idx++ {
+		wg.Add(1)
+		go func(idx int) {
+			defer wg.Done()
+			time.Sleep(config.Ramp * time.Duration(idx) / time.Duration(config.Games))
+			rec.gameOver(playGame(config, idx, rec))
+		}(idx)
+	}
+	wg.Wait()
+	duration := time.Since(start)
+	stopSampling()
+
+	report := rec.report(config)
+	report.Duration = duration
+	report.Server, report.ServerError = sampler.usage()
+	return report, nil
+}
+
+// simulatedGame is one game of the load test
+type simulatedGame struct {
+	mu sync.Mutex
+	// lastSubmission is when the latest turn of the game was submitted, or when the game was started before the first
+	lastSubmission time.Time
+}
+
+func (g *simulatedGame) submitted(at time.Time) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.lastSubmission = at
+}
+
+// sinceLastSubmission is how long before the given time the latest turn of the game was submitted
+func (g *simulatedGame) sinceLastSubmission(at time.Time) time.Duration {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return at.Sub(g.lastSubmission)
+}
+
+// simulatedClient plays one seat of a game of the load test
+type simulatedClient struct {
+	game     *simulatedGame
+	conn     *client.Client
+	strategy player.Strategy
+	rec      *recorder
+	// lobbies receives the lobby states the client is sent, if it is the host of its game
+	lobbies chan client.LobbyState
+
+	self player.PlayerID
+	// submitted is when the turn waiting to be acknowledged by the server was submitted, zero if none is waiting.
+	// The server acknowledges turns that cross off cells with the player's new board, and those it rejects with an invalid turn.
+	submitted time.Time
+}
+
+// errGameTimeout is the error of clients whose game took longer than the timeout
+var errGameTimeout = errors.New("the game took longer than the timeout")
+
+// playGame connects the clients of one game, has the first of them create a lobby the others join and start the game,
+// and plays the game to its end
+func playGame(config Config, idx int, rec *recorder) error {
+	deadline := time.After(config.Timeout)
+	game := &simulatedGame{}
+	clients := make([]*simulatedClient, 0, config.PlayersPerGame)
+	defer func() {
+		for _, c := range clients {
+			_ = c.conn.Close()
+		}
+	}()
+
+	played := make(chan error, config.PlayersPerGame)
+	for seat := 0; seat < config.PlayersPerGame; seat++ {
+		conn, err := client.Dial(config.ServerURL)
+		if err != nil {
+			rec.connectFailed()
+			return err
+		}
+		strategy, _ := player.NewStrategy(config.Strategy, rand.New(rand.NewSource(config.Seed+int64(idx*config.PlayersPerGame+seat))))
+		c := &simulatedClient{game: game, conn: conn, strategy: strategy, rec: rec}
+		if seat == 0 {
+			c.lobbies = make(chan client.LobbyState, config.PlayersPerGame)
+		}
+		clients = append(clients, c)
+		go func() { played <- c.play() }()
+	}
+
+	host := clients[0]
+	name := func(seat int) string { return fmt.Sprintf("game%d-player%d", idx+1, seat+1) }
+	if err := host.conn.CreateLobby(name(0), "", "", client.GameConfig{}); err != nil {
+		return err
+	}
+	lobby, err := host.awaitLobby(1, deadline)
+	if err != nil {
+		return err
+	}
+	for seat, c := range clients[1:] {
+		if err := c.conn.JoinLobby(lobby.Code, name(seat+1)); err != nil {
+			return err
+		}
+	}
+	if _, err := host.awaitLobby(config.PlayersPerGame, deadline); err != nil {
+		return err
+	}
+	game.submitted(time.Now())
+	if err := host.conn.StartGame(); err != nil {
+		return err
+	}
+
+	for range clients {
+		select {
+		case err := <-played:
+			if err != nil {
+				return err
+			}
+		case <-deadline:
+			return errGameTimeout
+		}
+	}
+	return nil
+}
+
+// awaitLobby waits for the host to be told its lobby has the given number of players
+func (c *simulatedClient) awaitLobby(players int, deadline <-chan time.Time) (client.LobbyState, error) {
+	for {
+		select {
+		case lobby := <-c.lobbies:
+			if len(lobby.Players) >= players {
+				return lobby, nil
+			}
+		case <-deadline:
+			return client.LobbyState{}, errGameTimeout
+		}
+	}
+}
+
+// play answers the prompts of the server with the turns of the client's strategy until the game is over
+func (c *simulatedClient) play() error {
+	for event := range c.conn.Events() {
+		switch payload := event.Payload.(type) {
+		case client.LobbyState:
+			if c.lobbies != nil {
+				select {
+				case c.lobbies <- payload:
+				default:
+				}
+			}
+		case client.Error:
+			c.rec.serverError()
+		case client.GameStarted:
+			c.self = payload.You
+		case client.Prompt:
+			c.rec.prompt(c.game.sinceLastSubmission(event.Received))
+			if err := c.answer(event.Type == client.MessagePromptActive, payload); err != nil {
+				return err
+			}
+		case client.BoardUpdate:
+			if payload.PlayerID == c.self {
+				c.acknowledged(event.Received)
+			}
+		case client.InvalidTurn:
+			c.rec.invalidTurn()
+			c.acknowledged(event.Received)
+		case client.GameOver:
+			return nil
+		}
+	}
+	if err := c.conn.Err(); err != nil {
+		c.rec.connectionLost()
+		return err
+	}
+	return client.ErrClosed
+}
+
+// answer submits the turn the client's strategy chooses for the given prompt
+func (c *simulatedClient) answer(active bool, prompt client.Prompt) error {
+	playerBoard, err := board.FromState(prompt.Board)
+	if err != nil {
+		return err
+	}
+	var turn actions.ActivePlayerTurn
+	if active {
+		turn = c.strategy.ChooseActivePlayerTurn(playerBoard, prompt.DiceRoll)
+	} else {
+		turn.WhiteDiceMove = c.strategy.ChooseInactivePlayerTurn(playerBoard, prompt.DiceRoll).WhiteDiceMove
+	}
+	submitted := time.Now()
+	if err := c.conn.SubmitTurn(prompt.PromptID, turn.WhiteDiceMove, turn.ColorDiceMove); err != nil {
+		return err
+	}
+	c.game.submitted(submitted)
+	c.rec.submission()
+	if turn.WhiteDiceMove != nil || turn.ColorDiceMove != nil {
+		c.submitted = submitted
+	}
+	return nil
+}
+
+// acknowledged records how long the server took to acknowledge the turn waiting for it, if any
+func (c *simulatedClient) acknowledged(at time.Time) {
+	if c.submitted.IsZero() {
+		return
+	}
+	c.rec.acknowledgement(at.Sub(c.submitted))
+	c.submitted = time.Time{}
+}
+
+// statsURL is the url of the stats of the server with the given websocket url
+func statsURL(serverURL string) string {
+	parsed, err := url.Parse(serverURL)
+	if err != nil {
+		return ""
+	}
+	parsed.Scheme = strings.Replace(parsed.Scheme, "ws", "http", 1)
+	parsed.Path = "/stats"
+	parsed.RawQuery = ""
+	return parsed.String()
+}
+
+// fetchStats gets the current stats of the server
+func fetchStats(httpClient *http.Client, statsURL string) (server.Stats, error) {
+	response, err := httpClient.Get(statsURL)
+	if err != nil {
+		return server.Stats{}, err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return server.Stats{}, fmt.Errorf("getting %v: %v", statsURL, response.Status)
+	}
+	var stats server.Stats
+	if err := json.NewDecoder(response.Body).Decode(&stats); err != nil {
+		return server.Stats{}, fmt.Errorf("decoding stats: %w", err)
+	}
+	return stats, nil
+}
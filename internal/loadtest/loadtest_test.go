@@ -0,0 +1,105 @@
+package loadtest
+
+import (
+	"bytes"
+	"qwixx/internal/game/player"
+	"qwixx/internal/server/servertest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRun(t *testing.T) {
+	report, err := Run(Config{
+		ServerURL:      servertest.New(t).URL,
+		Games:          3,
+		PlayersPerGame: 3,
+		Strategy:       player.StrategyFirst,
+		Ramp:           10 * time.Millisecond,
+		Timeout:        10 * time.Second,
+		StatsInterval:  10 * time.Millisecond,
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, 9, report.Clients)
+	require.Equal(t, 3, report.GamesFinished)
+	require.Zero(t, report.ErrorRate)
+	require.Equal(t, Errors{}, report.Errors)
+	require.Positive(t, report.Prompts.Count)
+	require.Positive(t, report.Submissions.Count)
+	require.Equal(t, report.Prompts.Count, report.TurnsSubmitted)
+	require.LessOrEqual(t, report.Prompts.P50, report.Prompts.Max)
+
+	require.Empty(t, report.ServerError)
+	require.NotNil(t, report.Server)
+	require.GreaterOrEqual(t, report.Server.PeakClients, int64(1))
+	require.Positive(t, report.Server.PeakGoroutines)
+
+	var text bytes.Buffer
+	require.NoError(t, report.WriteText(&text))
+	require.Contains(t, text.String(), "3 games finished")
+}
+
+func TestRun_InvalidConfig(t *testing.T) {
+	type testCase struct {
+		name     string
+		input    Config
+		expected string
+	}
+	valid := Config{
+		ServerURL: "ws://localhost:8080/ws", Games: 1, PlayersPerGame: 2, Strategy: player.StrategyFirst,
+		Timeout: time.Minute, StatsInterval: time.Second,
+	}
+	with := func(change func(*Config)) Config {
+		config := valid
+		change(&config)
+		return config
+	}
+	testCases := []testCase{
+		{name: "no server", input: with(func(c *Config) { c.ServerURL = "" }), expected: "invalid server url"},
+		{name: "no games", input: with(func(c *Config) { c.Games = 0 }), expected: "at least one game"},
+		{name: "one player", input: with(func(c *Config) { c.PlayersPerGame = 1 }), expected: "at least two players"},
+		{name: "no timeout", input: with(func(c *Config) { c.Timeout = 0 }), expected: "timeout must be positive"},
+		{name: "unknown strategy", input: with(func(c *Config) { c.Strategy = "nonsense" }), expected: "nonsense"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Run(tc.input)
+			require.ErrorContains(t, err, tc.expected)
+		})
+	}
+}
+
+func TestLatencyOf(t *testing.T) {
+	type testCase struct {
+		name     string
+		input    []time.Duration
+		expected Latency
+	}
+	hundred := make([]time.Duration, 0, 100)
+	for idx := 100; idx >= 1; idx-- {
+		hundred = append(hundred, time.Duration(idx)*time.Millisecond)
+	}
+	testCases := []testCase{
+		{name: "no durations"},
+		{
+			name:     "one duration",
+			input:    []time.Duration{time.Second},
+			expected: Latency{Count: 1, Mean: time.Second, P50: time.Second, P90: time.Second, P99: time.Second, Max: time.Second},
+		},
+		{
+			name:  "a hundred durations in reverse",
+			input: hundred,
+			expected: Latency{
+				Count: 100, Mean: 50500 * time.Microsecond, P50: 50 * time.Millisecond, P90: 90 * time.Millisecond,
+				P99: 99 * time.Millisecond, Max: 100 * time.Millisecond,
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, latencyOf(tc.input))
+		})
+	}
+}
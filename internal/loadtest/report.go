@@ -0,0 +1,334 @@
+package loadtest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"qwixx/internal/server"
+	"slices"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+// Latency summarizes how long the server took to answer one kind of request
+type Latency struct {
+	Count int           `json:"count"`
+	Mean  time.Duration `json:"mean_ns"`
+	P50   time.Duration `json:"p50_ns"`
+	P90   time.Duration `json:"p90_ns"`
+	P99   time.Duration `json:"p99_ns"`
+	Max   time.Duration `json:"max_ns"`
+}
+
+// latencyOf summarizes the given durations, using the nearest rank for the percentiles
+func latencyOf(durations []time.Duration) Latency {
+	if len(durations) == 0 {
+		return Latency{}
+	}
+	sorted := slices.Clone(durations)
+	slices.Sort(sorted)
+	var total time.Duration
+	for _, duration := range sorted {
+		total += duration
+	}
+	percentile := func(p int) time.Duration {
+		rank := (p*len(sorted) + 99) / 100
+		return sorted[max(rank, 1)-1]
+	}
+	return Latency{
+		Count: len(sorted),
+		Mean:  total / time.Duration(len(sorted)),
+		P50:   percentile(50),
+		P90:   percentile(90),
+		P99:   percentile(99),
+		Max:   sorted[len(sorted)-1],
+	}
+}
+
+// Errors counts what went wrong during a load test
+type Errors struct {
+	// Connect counts the clients that could not connect to the server
+	Connect int `json:"connect"`
+	// ConnectionLost counts the clients whose connection broke during their game
+	ConnectionLost int `json:"connection_lost"`
+	// Timeouts counts the games that took longer than the timeout
+	Timeouts int `json:"timeouts"`
+	// ServerErrors counts the requests the server reported an error for
+	ServerErrors int `json:"server_errors"`
+	// InvalidTurns counts the turns the server rejected
+	InvalidTurns int `json:"invalid_turns"`
+}
+
+// ServerUsage is how the resources of the server changed during a load test, from its stats before and after the games,
+// and the most it used while they were played
+type ServerUsage struct {
+	Before server.Stats `json:"before"`
+	After  server.Stats `json:"after"`
+
+	PeakClients    int64  `json:"peak_clients"`
+	PeakGoroutines int    `json:"peak_goroutines"`
+	PeakHeapBytes  uint64 `json:"peak_heap_bytes"`
+	PeakSysBytes   uint64 `json:"peak_sys_bytes"`
+	// CPUSeconds is the CPU time the server spent while the games were played
+	CPUSeconds float64 `json:"cpu_seconds"`
+	GCCycles   uint32  `json:"gc_cycles"`
+}
+
+// Report is what a load test measured
+type Report struct {
+	Games          int           `json:"games"`
+	PlayersPerGame int           `json:"players_per_game"`
+	Clients        int           `json:"clients"`
+	Strategy       string        `json:"strategy"`
+	Duration       time.Duration `json:"duration_ns"`
+	GamesFinished  int           `json:"games_finished"`
+
+	// Prompts is how long after the latest turn of a game was submitted its next prompt arrived,
+	// or how long after the game was started for the first prompt
+	Prompts Latency `json:"prompts"`
+	// Submissions is how long after a turn crossing off cells was submitted the server acknowledged it,
+	// passes and penalties not being acknowledged
+	Submissions Latency `json:"submissions"`
+	// TurnsSubmitted counts every turn submitted, acknowledged or not
+	TurnsSubmitted int `json:"turns_submitted"`
+
+	Errors Errors `json:"errors"`
+	// ErrorRate is the share of the games that did not finish
+	ErrorRate float64 `json:"error_rate"`
+
+	// Server is how the resources of the server changed, nil if its stats could not be sampled
+	Server *ServerUsage `json:"server,omitempty"`
+	// ServerError is why the stats of the server could not be sampled, if they could not
+	ServerError string `json:"server_error,omitempty"`
+}
+
+// WriteText writes the report as tables meant to be read by people
+func (r Report) WriteText(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(
+		tw, "%d games of %d %s players, %d clients, in %v\n",
+		r.Games, r.PlayersPerGame, r.Strategy, r.Clients, r.Duration.Round(time.Millisecond),
+	)
+	fmt.Fprintf(tw, "%d games finished, error rate %.2f%%, %d turns submitted\n\n", r.GamesFinished, 100*r.ErrorRate, r.TurnsSubmitted)
+
+	fmt.Fprintln(tw, "latency\tcount\tmean\tp50\tp90\tp99\tmax\t")
+	for _, row := range []struct {
+		name    string
+		latency Latency
+	}{{"prompts", r.Prompts}, {"submissions", r.Submissions}} {
+		fmt.Fprintf(
+			tw, "%s\t%d\t%v\t%v\t%v\t%v\t%v\t\n", row.name, row.latency.Count, roundLatency(row.latency.Mean),
+			roundLatency(row.latency.P50), roundLatency(row.latency.P90), roundLatency(row.latency.P99), roundLatency(row.latency.Max),
+		)
+	}
+
+	fmt.Fprintln(tw, "\nerrors\tcount\t")
+	for _, row := range []struct {
+		name  string
+		count int
+	}{
+		{"connect", r.Errors.Connect}, {"connection lost", r.Errors.ConnectionLost}, {"timeouts", r.Errors.Timeouts},
+		{"server errors", r.Errors.ServerErrors}, {"invalid turns", r.Errors.InvalidTurns},
+	} {
+		fmt.Fprintf(tw, "%s\t%d\t\n", row.name, row.count)
+	}
+
+	if r.Server == nil {
+		fmt.Fprintf(tw, "\nserver stats unavailable: %s\n", r.ServerError)
+		return tw.Flush()
+	}
+	fmt.Fprintln(tw, "\nserver\tbefore\tpeak\tafter\t")
+	fmt.Fprintf(tw, "clients\t%d\t%d\t%d\t\n", r.Server.Before.Clients, r.Server.PeakClients, r.Server.After.Clients)
+	fmt.Fprintf(tw, "goroutines\t%d\t%d\t%d\t\n", r.Server.Before.Goroutines, r.Server.PeakGoroutines, r.Server.After.Goroutines)
+	// the heap holds the results of the finished games the server keeps for their scoresheets and reviews
+	fmt.Fprintf(tw, "finished games kept\t%d\t\t%d\t\n", r.Server.Before.GamesFinished, r.Server.After.GamesFinished)
+	fmt.Fprintf(
+		tw, "heap\t%s\t%s\t%s\t\n",
+		formatBytes(r.Server.Before.HeapBytes), formatBytes(r.Server.PeakHeapBytes), formatBytes(r.Server.After.HeapBytes),
+	)
+	fmt.Fprintf(
+		tw, "memory from the os\t%s\t%s\t%s\t\n",
+		formatBytes(r.Server.Before.SysBytes), formatBytes(r.Server.PeakSysBytes), formatBytes(r.Server.After.SysBytes),
+	)
+	fmt.Fprintf(tw, "\ncpu time %.2fs, %d garbage collections\n", r.Server.CPUSeconds, r.Server.GCCycles)
+	return tw.Flush()
+}
+
+// WriteJSON writes the report as indented JSON, with durations in nanoseconds
+func (r Report) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
+
+func roundLatency(latency time.Duration) time.Duration {
+	return latency.Round(10 * time.Microsecond)
+}
+
+// formatBytes writes a number of bytes in mebibytes
+func formatBytes(bytes uint64) string {
+	return fmt.Sprintf("%.1f MiB", float64(bytes)/(1<<20))
+}
+
+// recorder collects the measurements of the clients of a load test
+type recorder struct {
+	mu              sync.Mutex
+	prompts         []time.Duration
+	acknowledgments []time.Duration
+	submissions     int
+	gamesFinished   int
+	errors          Errors
+}
+
+func (r *recorder) prompt(latency time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.prompts = append(r.prompts, latency)
+}
+
+func (r *recorder) submission() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.submissions++
+}
+
+func (r *recorder) acknowledgement(latency time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.acknowledgments = append(r.acknowledgments, latency)
+}
+
+func (r *recorder) connectFailed() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.errors.Connect++
+}
+
+func (r *recorder) connectionLost() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.errors.ConnectionLost++
+}
+
+func (r *recorder) serverError() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.errors.ServerErrors++
+}
+
+func (r *recorder) invalidTurn() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.errors.InvalidTurns++
+}
+
+// gameOver records how a game of the load test ended, nil if it was played to its end
+func (r *recorder) gameOver(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	switch {
+	case err == nil:
+		r.gamesFinished++
+	case errors.Is(err, errGameTimeout):
+		r.errors.Timeouts++
+	}
+}
+
+func (r *recorder) report(config Config) Report {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return Report{
+		Games:          config.Games,
+		PlayersPerGame: config.PlayersPerGame,
+		Clients:        config.Games * config.PlayersPerGame,
+		Strategy:       config.Strategy,
+		GamesFinished:  r.gamesFinished,
+		Prompts:        latencyOf(r.prompts),
+		Submissions:    latencyOf(r.acknowledgments),
+		TurnsSubmitted: r.submissions,
+		Errors:         r.errors,
+		ErrorRate:      float64(config.Games-r.gamesFinished) / float64(config.Games),
+	}
+}
+
+// statsSampler samples the stats of the server during a load test
+type statsSampler struct {
+	url        string
+	httpClient *http.Client
+
+	mu      sync.Mutex
+	sampled *ServerUsage
+	err     error
+}
+
+func newStatsSampler(url string) *statsSampler {
+	return &statsSampler{url: url, httpClient: &http.Client{Timeout: 5 * time.Second}}
+}
+
+// sampleEvery samples the stats of the server now and then at the given interval, until the returned function is called,
+// which samples them one last time
+func (s *statsSampler) sampleEvery(interval time.Duration) (stop func()) {
+	s.sample()
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				s.sample()
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		<-stopped
+		s.sample()
+	}
+}
+
+// sample takes a sample of the stats of the server. Sampling gives up on the first stats the server does not give,
+// as a server that answers slowly under load would otherwise be sampled out of step.
+func (s *statsSampler) sample() {
+	s.mu.Lock()
+	failed := s.err != nil
+	s.mu.Unlock()
+	if failed {
+		return
+	}
+	stats, err := fetchStats(s.httpClient, s.url)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err != nil {
+		s.sampled, s.err = nil, err
+		return
+	}
+	if s.sampled == nil {
+		s.sampled = &ServerUsage{Before: stats}
+	}
+	usage := s.sampled
+	usage.After = stats
+	usage.PeakClients = max(usage.PeakClients, stats.Clients)
+	usage.PeakGoroutines = max(usage.PeakGoroutines, stats.Goroutines)
+	usage.PeakHeapBytes = max(usage.PeakHeapBytes, stats.HeapBytes)
+	usage.PeakSysBytes = max(usage.PeakSysBytes, stats.SysBytes)
+	usage.CPUSeconds = stats.CPUSeconds - usage.Before.CPUSeconds
+	usage.GCCycles = stats.GCCycles - usage.Before.GCCycles
+}
+
+// usage returns how the resources of the server changed, or why its stats could not be sampled
+func (s *statsSampler) usage() (*ServerUsage, string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return nil, s.err.Error()
+	}
+	return s.sampled, ""
+}
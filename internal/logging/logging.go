@@ -0,0 +1,86 @@
+// Package logging provides the loggers games are narrated to
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+)
+
+// Discard returns a logger that drops everything, which games use unless they are given a logger
+func Discard() *slog.Logger {
+	return slog.New(discardHandler{})
+}
+
+type discardHandler struct{}
+
+func (discardHandler) Enabled(context.Context, slog.Level) bool  { return false }
+func (discardHandler) Handle(context.Context, slog.Record) error { return nil }
+func (h discardHandler) WithAttrs([]slog.Attr) slog.Handler      { return h }
+func (h discardHandler) WithGroup(string) slog.Handler           { return h }
+
+// NewHuman returns a logger that narrates a game to a person at the given level, see NewHumanHandler
+func NewHuman(w io.Writer, level slog.Leveler) *slog.Logger {
+	return slog.New(NewHumanHandler(w, level))
+}
+
+// HumanHandler writes each record as its message on a line of its own, the way the game used to be printed to the terminal.
+// Messages in this repository are whole sentences, so attributes are left out,
+// except for those with multi-line values like boards, which follow the message.
+type HumanHandler struct {
+	mu    *sync.Mutex
+	w     io.Writer
+	level slog.Leveler
+	attrs []slog.Attr
+}
+
+var _ slog.Handler = &HumanHandler{}
+
+func NewHumanHandler(w io.Writer, level slog.Leveler) *HumanHandler {
+	if level == nil {
+		level = slog.LevelInfo
+	}
+	return &HumanHandler{mu: &sync.Mutex{}, w: w, level: level}
+}
+
+func (h *HumanHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level.Level()
+}
+
+func (h *HumanHandler) Handle(_ context.Context, record slog.Record) error {
+	var text strings.Builder
+	text.WriteString(record.Message)
+	text.WriteString("\n")
+	writeMultiLine := func(attr slog.Attr) bool {
+		if value := attr.Value.String(); strings.Contains(value, "\n") {
+			text.WriteString(value)
+			text.WriteString("\n")
+		}
+		return true
+	}
+	for _, attr := range h.attrs {
+		writeMultiLine(attr)
+	}
+	record.Attrs(writeMultiLine)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := io.WriteString(h.w, text.String())
+	return err
+}
+
+func (h *HumanHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &HumanHandler{
+		mu:    h.mu,
+		w:     h.w,
+		level: h.level,
+		attrs: append(append([]slog.Attr(nil), h.attrs...), attrs...),
+	}
+}
+
+// WithGroup is a no-op since attributes are not written with their keys
+func (h *HumanHandler) WithGroup(string) slog.Handler {
+	return h
+}
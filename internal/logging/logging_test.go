@@ -0,0 +1,34 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHumanHandler(t *testing.T) {
+	var out bytes.Buffer
+	logger := NewHuman(&out, slog.LevelDebug).With("game_id", "abc")
+
+	logger.Debug("it's player alice's turn", "turn", 1)
+	logger.Info("alice's board:", "board", "Red: [2| ]\nYellow: [2| ]")
+	require.Equal(t, "it's player alice's turn\nalice's board:\nRed: [2| ]\nYellow: [2| ]\n", out.String())
+}
+
+func TestHumanHandler_Level(t *testing.T) {
+	var out bytes.Buffer
+	logger := NewHuman(&out, slog.LevelInfo)
+
+	logger.Debug("the white dice rolled were 1 and 2")
+	logger.Warn("player bob played an invalid turn")
+	require.Equal(t, "player bob played an invalid turn\n", out.String())
+}
+
+func TestDiscard(t *testing.T) {
+	logger := Discard()
+	require.False(t, logger.Enabled(context.Background(), slog.LevelError))
+	logger.Error("nobody hears this")
+}
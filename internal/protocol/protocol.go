@@ -0,0 +1,213 @@
+// Package protocol defines the messages exchanged between the server and its clients over the /ws websocket.
+//
+// Every websocket message is a JSON encoded Message, whose payload depends on its type.
+// Clients create or join a lobby, optionally add computer players, and start the game.
+// During the game the server prompts each client for its turns and keeps it informed of every board.
+package protocol
+
+import (
+	"encoding/json"
+	"fmt"
+	"qwixx/internal/game"
+	"qwixx/internal/game/actions"
+	"qwixx/internal/game/board"
+	"qwixx/internal/game/player"
+)
+
+type MessageType string
+
+// Messages sent from a client to the server
+const (
+	// MessageCreateLobby creates a new lobby hosted by the sender, payload CreateLobby
+	MessageCreateLobby MessageType = "create_lobby"
+	// MessageJoinLobby joins an existing lobby, payload JoinLobby
+	MessageJoinLobby MessageType = "join_lobby"
+	// MessageAddBot adds a computer player to the lobby hosted by the sender, payload AddBot
+	MessageAddBot MessageType = "add_bot"
+	// MessageStartGame starts the game in the lobby hosted by the sender, no payload
+	MessageStartGame MessageType = "start_game"
+	// MessageSubmitTurn answers a prompt, payload SubmitTurn
+	MessageSubmitTurn MessageType = "submit_turn"
+	// MessageRequestHint asks for the recommended answer to a prompt, in games whose config allows hints, payload RequestHint
+	MessageRequestHint MessageType = "request_hint"
+	// MessageChat talks to everyone in the sender's lobby or game,
+	// the server relaying it to all of them with the sender filled in, payload Chat
+	MessageChat MessageType = "chat"
+)
+
+// Messages sent from the server to a client
+const (
+	// MessageLobbyState describes the lobby the client is in whenever it changes, payload LobbyState
+	MessageLobbyState MessageType = "lobby_state"
+	// MessageError reports a request the server could not carry out, payload Error
+	MessageError MessageType = "error"
+	// MessageGameStarted tells the client the game has started and who is playing, payload GameStarted
+	MessageGameStarted MessageType = "game_started"
+	// MessagePlayOrder gives the names of the players in the order they take turns, payload PlayOrder
+	MessagePlayOrder MessageType = "play_order"
+	// MessagePromptActive asks for the client's ActivePlayerTurn, payload Prompt
+	MessagePromptActive MessageType = "prompt_active"
+	// MessagePromptInactive asks for the client's InactivePlayerTurn, payload Prompt
+	MessagePromptInactive MessageType = "prompt_inactive"
+	// MessageInvalidTurn tells the client why the turn it submitted was rejected before it is prompted again, payload InvalidTurn
+	MessageInvalidTurn MessageType = "invalid_turn"
+	// MessageBoardUpdate gives the current board of a player, payload BoardUpdate
+	MessageBoardUpdate MessageType = "board_update"
+	// MessageRowLocked tells the client a row was locked for all players, payload RowLocked
+	MessageRowLocked MessageType = "row_locked"
+	// MessageGameOver tells the client the game is over, payload GameOver
+	MessageGameOver MessageType = "game_over"
+	// MessageLog is a line of the game log describing what happened, payload Log
+	MessageLog MessageType = "log"
+	// MessageHint answers a MessageRequestHint with the recommended turn, payload Hint
+	MessageHint MessageType = "hint"
+)
+
+// Message is the envelope of every message sent over the websocket
+type Message struct {
+	Type    MessageType     `json:"type"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+// NewMessage wraps the given payload in a message of the given type, a nil payload leaves it empty
+func NewMessage(messageType MessageType, payload any) (Message, error) {
+	message := Message{Type: messageType}
+	if payload == nil {
+		return message, nil
+	}
+	encoded, err := json.Marshal(payload)
+	if err != nil {
+		return Message{}, fmt.Errorf("encoding %v payload: %w", messageType, err)
+	}
+	message.Payload = encoded
+	return message, nil
+}
+
+// Decode decodes the message's payload into the given value
+func (m Message) Decode(payload any) error {
+	if len(m.Payload) == 0 {
+		return fmt.Errorf("%v message has no payload", m.Type)
+	}
+	if err := json.Unmarshal(m.Payload, payload); err != nil {
+		return fmt.Errorf("decoding %v payload: %w", m.Type, err)
+	}
+	return nil
+}
+
+// CreateLobby creates a lobby for a game played on the sheet of the given variant, one of board.Variants,
+// an empty variant being the classic sheet, by the rules for crossing off cells of the given ruleset, one of ruleset.Names,
+// an empty ruleset being the classic rules, and by the given house rules, the rules left out being those of the original game
+type CreateLobby struct {
+	Name    string          `json:"name"`
+	Variant string          `json:"variant,omitempty"`
+	Ruleset string          `json:"ruleset,omitempty"`
+	Config  game.GameConfig `json:"config"`
+}
+
+type JoinLobby struct {
+	Code string `json:"code"`
+	Name string `json:"name"`
+}
+
+type AddBot struct {
+	Name string `json:"name"`
+}
+
+// SubmitTurn answers the prompt with the given ID.
+// Only the white dice move may be set when answering an inactive prompt, and leaving out both moves passes.
+type SubmitTurn struct {
+	PromptID      int           `json:"prompt_id"`
+	WhiteDiceMove *actions.Move `json:"white_dice_move"`
+	ColorDiceMove *actions.Move `json:"color_dice_move"`
+}
+
+// RequestHint asks for the recommended answer to the prompt with the given ID, which must be the latest prompt
+type RequestHint struct {
+	PromptID int `json:"prompt_id"`
+}
+
+type LobbyState struct {
+	Code    string   `json:"code"`
+	Host    string   `json:"host"`
+	Players []string `json:"players"`
+	// Variant is the sheet the game of the lobby will be played on
+	Variant board.Variant `json:"variant"`
+	// Ruleset is the name of the rules for crossing off cells the game of the lobby will be played by
+	Ruleset string `json:"ruleset"`
+	// Config is the house rules the game of the lobby will be played by
+	Config game.GameConfig `json:"config"`
+}
+
+type Error struct {
+	Message string `json:"message"`
+}
+
+// PlayerInfo identifies one player of a game
+type PlayerInfo struct {
+	ID   player.PlayerID `json:"id"`
+	Name string          `json:"name"`
+}
+
+type GameStarted struct {
+	GameID string `json:"game_id"`
+	// You is the ID of the receiving client in this game
+	You     player.PlayerID `json:"you"`
+	Players []PlayerInfo    `json:"players"`
+	// Variant is the sheet every board of the game is laid out like
+	Variant board.Variant `json:"variant"`
+	// Ruleset is the name of the rules for crossing off cells the game is played by
+	Ruleset string `json:"ruleset"`
+	// Config is the house rules the game is played by
+	Config game.GameConfig `json:"config"`
+}
+
+type PlayOrder struct {
+	Names []string `json:"names"`
+}
+
+// Prompt asks for a turn given the client's board and the dice
+type Prompt struct {
+	PromptID int              `json:"prompt_id"`
+	Board    board.State      `json:"board"`
+	DiceRoll actions.DiceRoll `json:"dice"`
+}
+
+// InvalidTurn is the rule broken by the turn submitted for the prompt with the given ID.
+// Code is one of the board.Code constants, for clients that want to react to or translate specific violations.
+type InvalidTurn struct {
+	PromptID int        `json:"prompt_id"`
+	Code     board.Code `json:"code"`
+	Message  string     `json:"message"`
+}
+
+// Hint is the recommended answer to the prompt with the given ID, with a short explanation of why,
+// such as "crosses off Yellow 5 skipping 1 cell; avoids penalty". Leaving out both moves passes.
+type Hint struct {
+	PromptID      int           `json:"prompt_id"`
+	WhiteDiceMove *actions.Move `json:"white_dice_move"`
+	ColorDiceMove *actions.Move `json:"color_dice_move"`
+	Explanation   string        `json:"explanation"`
+}
+
+type BoardUpdate struct {
+	PlayerID player.PlayerID `json:"player_id"`
+	Board    board.State     `json:"board"`
+}
+
+type RowLocked struct {
+	RowColor actions.RowColor `json:"row_color"`
+}
+
+type GameOver struct {
+	Won      bool            `json:"won"`
+	WinnerID player.PlayerID `json:"winner_id"`
+}
+
+type Log struct {
+	Text string `json:"text"`
+}
+
+type Chat struct {
+	From string `json:"from,omitempty"`
+	Text string `json:"text"`
+}
@@ -0,0 +1,173 @@
+// Package render draws boards and dice rolls for a line based terminal.
+// Rows are drawn in their own colors, with crossed off cells marked, skipped cells dimmed and the lock status at the end,
+// followed by the penalty track and the current score.
+// When the output is not a terminal, the same layout is drawn as plain text.
+package render
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"qwixx/internal/game/actions"
+	"qwixx/internal/game/board"
+	"strings"
+)
+
+const (
+	ansiReset   = "\x1b[0m"
+	ansiBold    = "\x1b[1m"
+	ansiDim     = "\x1b[2m"
+	ansiReverse = "\x1b[7m"
+)
+
+var rowANSIColors = map[actions.RowColor]string{
+	actions.RowColorRed:    "\x1b[31m",
+	actions.RowColorYellow: "\x1b[33m",
+	actions.RowColorGreen:  "\x1b[32m",
+	actions.RowColorBlue:   "\x1b[34m",
+	actions.RowColorOrange: "\x1b[38;5;208m",
+	actions.RowColorPurple: "\x1b[35m",
+}
+
+// penaltySlots is the number of boxes of the penalty track on the sheet
+const penaltySlots = 4
+
+// defaultPenaltyValue is the number of points a penalty costs when the renderer is not told otherwise
+const defaultPenaltyValue = 5
+
+// Renderer draws boards and dice rolls as text
+type Renderer struct {
+	// Color draws with ANSI escape codes, leaving it off draws plain text
+	Color bool
+	// PenaltyValue is the number of points each penalty costs in the score, 5 if zero
+	PenaltyValue int
+}
+
+// ForWriter returns a renderer drawing in color if the given output is a terminal,
+// unless the NO_COLOR environment variable is set, and drawing plain text otherwise
+func ForWriter(out io.Writer) Renderer {
+	return Renderer{Color: isTerminal(out) && os.Getenv("NO_COLOR") == ""}
+}
+
+// isTerminal determines if the given output is a terminal rather than a file, a pipe or a buffer
+func isTerminal(out io.Writer) bool {
+	file, ok := out.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := file.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// style wraps the given text in the given escape codes when drawing in color
+func (r Renderer) style(text string, codes ...string) string {
+	if !r.Color || len(codes) == 0 {
+		return text
+	}
+	return strings.Join(codes, "") + text + ansiReset
+}
+
+// Board draws every row of the given board, one per line, followed by a line with the penalty track and the score
+func (r Renderer) Board(b board.Board, penalties int) string {
+	var lines []string
+	nameWidth := 0
+	for _, rowColor := range b.Variant().RowColors() {
+		nameWidth = max(nameWidth, len(rowColor.String()))
+	}
+	for _, rowColor := range b.Variant().RowColors() {
+		lines = append(lines, r.row(b, rowColor, nameWidth))
+	}
+	lines = append(lines, r.footer(b, penalties))
+	return strings.Join(lines, "\n")
+}
+
+// row draws the row with the given color, its name padded to the given width
+func (r Renderer) row(b board.Board, rowColor actions.RowColor, nameWidth int) string {
+	cells := b.Cells(rowColor)
+	lastCrossed := -1
+	for idx, cell := range cells {
+		if b.CrossCount(rowColor, cell.Number) > 0 {
+			lastCrossed = idx
+		}
+	}
+
+	var line strings.Builder
+	line.WriteString(r.style(fmt.Sprintf("%-*v", nameWidth, rowColor), rowANSIColors[rowColor], ansiBold))
+	for idx, cell := range cells {
+		text := fmt.Sprint(cell.Number)
+		if cell.Color != rowColor && !r.Color {
+			// without colors the cells of a sheet with mixed colors are told apart by the initial of their color
+			text = cell.Color.String()[:1] + text
+		}
+		var codes []string
+		if r.Color {
+			codes = append(codes, rowANSIColors[cell.Color])
+		}
+		switch crosses := b.CrossCount(rowColor, cell.Number); {
+		case crosses > 0:
+			text = strings.Repeat("X", crosses)
+			codes = append(codes, ansiBold)
+		case idx < lastCrossed:
+			// cells to the left of a crossed off cell can never be crossed off anymore
+			if !r.Color {
+				text = "-"
+			}
+			codes = append(codes, ansiDim)
+		}
+		line.WriteString(" " + r.style(fmt.Sprintf("%3v", text), codes...))
+	}
+	if b.IsRowLocked(rowColor) {
+		line.WriteString("  " + r.style("locked", ansiReverse))
+	}
+	return line.String()
+}
+
+// footer draws the penalty track and the score of the board after the given number of penalties
+func (r Renderer) footer(b board.Board, penalties int) string {
+	penaltyValue := r.PenaltyValue
+	if penaltyValue == 0 {
+		penaltyValue = defaultPenaltyValue
+	}
+	var track strings.Builder
+	for slot := 0; slot < max(penaltySlots, penalties); slot++ {
+		if slot < penalties {
+			track.WriteString("[" + r.style("X", ansiBold) + "]")
+		} else {
+			track.WriteString("[ ]")
+		}
+	}
+	score := b.CalculateScore() - penaltyValue*penalties
+	return fmt.Sprintf("penalties %v  score %v", track.String(), r.style(fmt.Sprint(score), ansiBold))
+}
+
+// Dice draws the given roll on a single line, the orange and purple dice only if they were rolled
+func (r Renderer) Dice(diceRoll actions.DiceRoll) string {
+	dice := []struct {
+		rowColor actions.RowColor
+		value    int
+	}{
+		{actions.RowColorRed, diceRoll.Red},
+		{actions.RowColorYellow, diceRoll.Yellow},
+		{actions.RowColorGreen, diceRoll.Green},
+		{actions.RowColorBlue, diceRoll.Blue},
+		{actions.RowColorOrange, diceRoll.Orange},
+		{actions.RowColorPurple, diceRoll.Purple},
+	}
+	line := fmt.Sprintf("dice: white %v %v |", r.die(diceRoll.White1, ""), r.die(diceRoll.White2, ""))
+	for _, die := range dice {
+		if die.value < 1 {
+			continue
+		}
+		name := strings.ToLower(die.rowColor.String())
+		line += fmt.Sprintf(" %v %v", r.style(name, rowANSIColors[die.rowColor]), r.die(die.value, rowANSIColors[die.rowColor]))
+	}
+	return line
+}
+
+// die draws a single die in the given color, as a reversed block when drawing in color
+func (r Renderer) die(value int, color string) string {
+	if !r.Color {
+		return fmt.Sprint(value)
+	}
+	return r.style(fmt.Sprintf(" %v ", value), color, ansiReverse, ansiBold)
+}
@@ -0,0 +1,125 @@
+package render
+
+import (
+	"bytes"
+	"qwixx/internal/game/actions"
+	"qwixx/internal/game/board"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testBoard(t *testing.T) board.Board {
+	t.Helper()
+	b := board.NewGameBoard()
+	require.NoError(t, b.MakeMove(actions.NewMove(actions.RowColorRed, 2)))
+	require.NoError(t, b.MakeMove(actions.NewMove(actions.RowColorRed, 5)))
+	require.NoError(t, b.MakeMove(actions.NewMove(actions.RowColorGreen, 11)))
+	b.LockRow(actions.RowColorBlue)
+	return b
+}
+
+func TestRenderer_Board(t *testing.T) {
+	type testCase struct {
+		name          string
+		inputRenderer Renderer
+		inputBoard    board.Board
+		penalties     int
+		expected      string
+	}
+	mixxColors, err := board.NewBoard(board.VariantMixxColors)
+	require.NoError(t, err)
+	testCases := []testCase{
+		{
+			name:          "plain",
+			inputRenderer: Renderer{},
+			inputBoard:    testBoard(t),
+			penalties:     1,
+			expected: strings.Join([]string{
+				"Red      X   -   -   X   6   7   8   9  10  11  12",
+				"Yellow   2   3   4   5   6   7   8   9  10  11  12",
+				"Green    -   X  10   9   8   7   6   5   4   3   2",
+				"Blue    12  11  10   9   8   7   6   5   4   3   2  locked",
+				"penalties [X][ ][ ][ ]  score -1",
+			}, "\n"),
+		},
+		{
+			name:          "plain with more penalties than boxes and another penalty value",
+			inputRenderer: Renderer{PenaltyValue: 3},
+			inputBoard:    board.NewGameBoard(),
+			penalties:     5,
+			expected: strings.Join([]string{
+				"Red      2   3   4   5   6   7   8   9  10  11  12",
+				"Yellow   2   3   4   5   6   7   8   9  10  11  12",
+				"Green   12  11  10   9   8   7   6   5   4   3   2",
+				"Blue    12  11  10   9   8   7   6   5   4   3   2",
+				"penalties [X][X][X][X][X]  score -15",
+			}, "\n"),
+		},
+		{
+			name:          "plain mixed colors",
+			inputRenderer: Renderer{},
+			inputBoard:    mixxColors,
+			expected: strings.Join([]string{
+				"Red     Y2  Y3  G4  G5  G6  B7  B8  B9  10  11  12",
+				"Yellow  G2  G3  B4  B5  B6  R7  R8  R9  10  11  12",
+				"Green  B12 B11 R10  R9  R8  Y7  Y6  Y5   4   3   2",
+				"Blue   R12 R11 Y10  Y9  Y8  G7  G6  G5   4   3   2",
+				"penalties [ ][ ][ ][ ]  score 0",
+			}, "\n"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, tc.inputRenderer.Board(tc.inputBoard, tc.penalties))
+		})
+	}
+}
+
+func TestRenderer_Board_Color(t *testing.T) {
+	drawn := Renderer{Color: true}.Board(testBoard(t), 0)
+	lines := strings.Split(drawn, "\n")
+	require.Len(t, lines, 5)
+
+	// crossed off cells are bold, the cells skipped to their left dimmed, and both keep their row's color
+	require.True(t, strings.HasPrefix(lines[0], rowANSIColors[actions.RowColorRed]+ansiBold+"Red   "+ansiReset))
+	require.Contains(t, lines[0], rowANSIColors[actions.RowColorRed]+ansiBold+"  X"+ansiReset)
+	require.Contains(t, lines[0], rowANSIColors[actions.RowColorRed]+ansiDim+"  3"+ansiReset)
+	require.Contains(t, lines[3], ansiReverse+"locked"+ansiReset)
+	require.NotContains(t, lines[0], " - ")
+
+	// without the escape codes the colored board lines up like the plain one, showing the numbers of skipped cells
+	require.Equal(t, "Red      X   3   4   X   6   7   8   9  10  11  12", stripEscapes(lines[0]))
+	require.Equal(t, "Green   12   X  10   9   8   7   6   5   4   3   2", stripEscapes(lines[2]))
+}
+
+func TestRenderer_Dice(t *testing.T) {
+	diceRoll := actions.DiceRoll{
+		WhiteDiceRoll: actions.WhiteDiceRoll{White1: 4, White2: 5},
+		ColorDiceRoll: actions.ColorDiceRoll{Red: 4, Yellow: 3, Green: 6, Blue: 2},
+	}
+	require.Equal(t, "dice: white 4 5 | red 4 yellow 3 green 6 blue 2", Renderer{}.Dice(diceRoll))
+
+	diceRoll.Orange, diceRoll.Purple = 1, 6
+	require.Equal(t, "dice: white 4 5 | red 4 yellow 3 green 6 blue 2 orange 1 purple 6", Renderer{}.Dice(diceRoll))
+
+	colored := Renderer{Color: true}.Dice(diceRoll)
+	require.Contains(t, colored, rowANSIColors[actions.RowColorPurple]+ansiReverse+ansiBold+" 6 "+ansiReset)
+	require.Equal(t, "dice: white  4   5  | red  4  yellow  3  green  6  blue  2  orange  1  purple  6 ", stripEscapes(colored))
+}
+
+func TestForWriter(t *testing.T) {
+	require.False(t, ForWriter(&bytes.Buffer{}).Color)
+}
+
+// stripEscapes removes the ANSI escape codes the renderer draws with
+func stripEscapes(text string) string {
+	for _, code := range []string{ansiReset, ansiBold, ansiDim, ansiReverse} {
+		text = strings.ReplaceAll(text, code, "")
+	}
+	for _, code := range rowANSIColors {
+		text = strings.ReplaceAll(text, code, "")
+	}
+	return text
+}
@@ -0,0 +1,95 @@
+package review
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"qwixx/internal/game/actions"
+	"qwixx/internal/game/board"
+	"strings"
+	"text/tabwriter"
+)
+
+// WriteText writes the report as tables meant to be read by people, with the boards in the board notation
+func (r Report) WriteText(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "review of %d turns\n\n", r.Turns)
+
+	fmt.Fprintln(tw, "player\tdecisions\tmistakes\tscore loss\t")
+	for _, summary := range r.Players {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%.1f\t\n", summary.Name, summary.Decisions, summary.Mistakes, summary.ScoreLoss)
+	}
+
+	if len(r.Mistakes) == 0 {
+		fmt.Fprintln(tw, "\nno mistakes")
+		return tw.Flush()
+	}
+	fmt.Fprintln(tw, "\nbiggest blunders")
+	fmt.Fprintln(tw, "turn\tplayer\trole\tdice\tchosen\tbest\tscore loss\tboard\t")
+	for _, mistake := range r.Mistakes {
+		role := "inactive"
+		if mistake.Active {
+			role = "active"
+		}
+		notation := ""
+		if b, err := board.FromState(mistake.Board); err == nil {
+			notation = board.Format(b, mistake.Penalties)
+		}
+		fmt.Fprintf(
+			tw, "%d\t%s\t%s\t%s\t%s\t%s\t%.1f\t%s\t\n",
+			mistake.Turn, mistake.PlayerName, role, FormatDice(mistake.DiceRoll, mistake.Active),
+			FormatTurn(mistake.Chosen.Turn, mistake.Active), FormatTurn(mistake.Best.Turn, mistake.Active), mistake.ScoreLoss, notation,
+		)
+	}
+	return tw.Flush()
+}
+
+// WriteJSON writes the report as indented JSON
+func (r Report) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
+
+// FormatDice writes the dice a player could use, like "4+5" for the white dice and "4+5 R4 Y3 G6 B2" with the color dice
+func FormatDice(diceRoll actions.DiceRoll, active bool) string {
+	dice := fmt.Sprintf("%d+%d", diceRoll.White1, diceRoll.White2)
+	if !active {
+		return dice
+	}
+	colors := []struct {
+		rowColor actions.RowColor
+		value    int
+	}{
+		{actions.RowColorRed, diceRoll.Red}, {actions.RowColorYellow, diceRoll.Yellow},
+		{actions.RowColorGreen, diceRoll.Green}, {actions.RowColorBlue, diceRoll.Blue},
+		{actions.RowColorOrange, diceRoll.Orange}, {actions.RowColorPurple, diceRoll.Purple},
+	}
+	for _, color := range colors {
+		if color.value > 0 {
+			dice += fmt.Sprintf(" %s%d", color.rowColor.String()[:1], color.value)
+		}
+	}
+	return dice
+}
+
+// FormatTurn writes a turn the way terminal players type it, like "w R7 c B9", with "penalty" or "pass" for leaving out both moves
+func FormatTurn(turn actions.ActivePlayerTurn, active bool) string {
+	var fields []string
+	for _, dice := range []struct {
+		name string
+		move *actions.Move
+	}{{"w", turn.WhiteDiceMove}, {"c", turn.ColorDiceMove}} {
+		if dice.move != nil {
+			fields = append(fields, fmt.Sprintf("%s %s%d", dice.name, dice.move.RowColor.String()[:1], dice.move.CellNumber))
+		}
+	}
+	switch {
+	case len(fields) > 0:
+		return strings.Join(fields, " ")
+	case active:
+		return "penalty"
+	default:
+		return "pass"
+	}
+}
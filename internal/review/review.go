@@ -0,0 +1,130 @@
+// Package review goes over the decisions of a finished game, rating each turn a player chose against the best turn
+// the analysis package finds for the same board and dice, to show players where they gave away the most points.
+package review
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"qwixx/internal/analysis"
+	"qwixx/internal/game"
+	"qwixx/internal/game/actions"
+	"qwixx/internal/game/board"
+	"qwixx/internal/game/player"
+	"slices"
+)
+
+// minScoreLoss is the smallest score loss counted as a mistake, so rounding errors between turns of the same worth are not
+const minScoreLoss = 0.01
+
+// Mistake is a turn a player chose that the analysis rates below the best turn they could have taken
+type Mistake struct {
+	Turn       int              `json:"turn"`
+	PlayerID   player.PlayerID  `json:"player_id"`
+	PlayerName string           `json:"player_name"`
+	Active     bool             `json:"active"`
+	Board      board.State      `json:"board"`
+	DiceRoll   actions.DiceRoll `json:"dice"`
+	// Penalties is the number of penalties the player had taken before the turn
+	Penalties int                   `json:"penalties"`
+	Chosen    analysis.TurnAnalysis `json:"chosen"`
+	Best      analysis.TurnAnalysis `json:"best"`
+	// ScoreLoss estimates the points the chosen turn gave away, as the difference between the score impacts of the best and the chosen turn
+	ScoreLoss float64 `json:"score_loss"`
+}
+
+// PlayerSummary adds up the mistakes of one player
+type PlayerSummary struct {
+	ID        player.PlayerID `json:"id"`
+	Name      string          `json:"name"`
+	Decisions int             `json:"decisions"`
+	Mistakes  int             `json:"mistakes"`
+	ScoreLoss float64         `json:"score_loss"`
+}
+
+// Report is the review of a game
+type Report struct {
+	Turns int `json:"turns"`
+	// Players sum up the mistakes of every player in play order
+	Players []PlayerSummary `json:"players"`
+	// Mistakes are every mistake of the game, the biggest blunder first
+	Mistakes []Mistake `json:"mistakes"`
+}
+
+// Game replays the decisions of the given game, comparing the turn each player chose to the best turn by the analysis package.
+// It returns an error if the result has no decisions, or one of them could not have been made on its board.
+func Game(result game.GameResult) (Report, error) {
+	if len(result.Decisions) == 0 {
+		return Report{}, errors.New("the game has no decisions to review")
+	}
+	config := result.Config.WithDefaults()
+	report := Report{Turns: result.Turns}
+	summaries := map[player.PlayerID]*PlayerSummary{}
+	for _, playerResult := range result.Players {
+		report.Players = append(report.Players, PlayerSummary{ID: playerResult.ID, Name: playerResult.Name})
+	}
+	for idx := range report.Players {
+		summaries[report.Players[idx].ID] = &report.Players[idx]
+	}
+
+	penalties := map[player.PlayerID]int{}
+	for _, decision := range result.Decisions {
+		summary, ok := summaries[decision.PlayerID]
+		if !ok {
+			return Report{}, fmt.Errorf("turn %v: decision of unknown player %v", decision.Turn, decision.PlayerID)
+		}
+		mistake, err := reviewDecision(decision, config.PenaltyValue)
+		if err != nil {
+			return Report{}, fmt.Errorf("turn %v: decision of %v: %w", decision.Turn, summary.Name, err)
+		}
+		summary.Decisions++
+		mistake.PlayerName = summary.Name
+		mistake.Penalties = penalties[decision.PlayerID]
+		if decision.Active && decision.Chosen == (actions.ActivePlayerTurn{}) {
+			penalties[decision.PlayerID]++
+		}
+		if mistake.ScoreLoss < minScoreLoss {
+			continue
+		}
+		summary.Mistakes++
+		summary.ScoreLoss += mistake.ScoreLoss
+		report.Mistakes = append(report.Mistakes, mistake)
+	}
+	slices.SortStableFunc(report.Mistakes, func(a, b Mistake) int {
+		return cmp.Compare(b.ScoreLoss, a.ScoreLoss)
+	})
+	return report, nil
+}
+
+// reviewDecision rates the given decision against the best turn the player could have taken instead
+func reviewDecision(decision game.Decision, penaltyValue int) (Mistake, error) {
+	b, err := board.FromState(decision.Board)
+	if err != nil {
+		return Mistake{}, err
+	}
+	mistake := Mistake{
+		Turn: decision.Turn, PlayerID: decision.PlayerID, Active: decision.Active, Board: decision.Board, DiceRoll: decision.DiceRoll,
+	}
+	var best []analysis.TurnAnalysis
+	if decision.Active {
+		mistake.Chosen, err = analysis.AnalyzeActiveTurn(b, decision.Chosen, decision.ColorFirst, penaltyValue)
+		best = analysis.AnalyzeActiveTurns(b, decision.DiceRoll, penaltyValue)
+	} else {
+		mistake.Chosen, err = analysis.AnalyzeInactiveTurn(b, actions.InactivePlayerTurn{WhiteDiceMove: decision.Chosen.WhiteDiceMove})
+		best = analysis.AnalyzeInactiveTurns(b, decision.DiceRoll)
+	}
+	if err != nil {
+		return Mistake{}, err
+	}
+	mistake.Best = best[0]
+	mistake.ScoreLoss = mistake.Best.ScoreImpact - mistake.Chosen.ScoreImpact
+	return mistake, nil
+}
+
+// Top returns the report with only its given number of biggest blunders, or every mistake if there are no more than that
+func (r Report) Top(n int) Report {
+	if n >= 0 && n < len(r.Mistakes) {
+		r.Mistakes = r.Mistakes[:n]
+	}
+	return r
+}
@@ -0,0 +1,134 @@
+package review
+
+import (
+	"bytes"
+	"math/rand"
+	"qwixx/internal/game"
+	"qwixx/internal/game/actions"
+	"qwixx/internal/game/board"
+	"qwixx/internal/game/player"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func moveOf(rowColor actions.RowColor, cellNumber int) *actions.Move {
+	move := actions.NewMove(rowColor, cellNumber)
+	return &move
+}
+
+func TestGame(t *testing.T) {
+	diceRoll := actions.DiceRoll{
+		WhiteDiceRoll: actions.WhiteDiceRoll{White1: 1, White2: 1},
+		ColorDiceRoll: actions.ColorDiceRoll{Red: 6, Yellow: 6, Green: 6, Blue: 6},
+	}
+	result := game.GameResult{
+		Players: []game.PlayerResult{{ID: "a", Name: "alice"}, {ID: "b", Name: "bob"}},
+		Turns:   2,
+		Decisions: []game.Decision{
+			// alice takes a penalty rather than crossing off red 2 for free
+			{Turn: 1, PlayerID: "a", Active: true, Board: board.State{}, DiceRoll: diceRoll},
+			// bob crosses off red 2 as well, the best move of the roll
+			{Turn: 1, PlayerID: "b", Board: board.State{}, DiceRoll: diceRoll, Chosen: actions.ActivePlayerTurn{WhiteDiceMove: moveOf(actions.RowColorRed, 2)}},
+			// bob crosses off green 7 with the green die, skipping five cells, rather than red 3 with the white dice
+			{
+				Turn: 2, PlayerID: "b", Active: true,
+				Board:    board.State{Rows: map[actions.RowColor][]int{actions.RowColorRed: {2}}},
+				DiceRoll: diceRoll,
+				Chosen:   actions.ActivePlayerTurn{ColorDiceMove: moveOf(actions.RowColorGreen, 7)},
+			},
+			// alice passes
+			{Turn: 2, PlayerID: "a", Board: board.State{}, DiceRoll: diceRoll},
+		},
+	}
+
+	report, err := Game(result)
+	require.NoError(t, err)
+	require.Equal(t, 2, report.Turns)
+	require.Len(t, report.Mistakes, 3)
+	for idx := 1; idx < len(report.Mistakes); idx++ {
+		require.GreaterOrEqual(t, report.Mistakes[idx-1].ScoreLoss, report.Mistakes[idx].ScoreLoss)
+	}
+
+	penalty := report.Mistakes[0]
+	require.Equal(t, 1, penalty.Turn)
+	require.Equal(t, "alice", penalty.PlayerName)
+	require.True(t, penalty.Active)
+	require.Equal(t, -5, penalty.Chosen.ScoreGain)
+	require.Equal(t, actions.ActivePlayerTurn{WhiteDiceMove: moveOf(actions.RowColorRed, 2)}, penalty.Best.Turn)
+	require.InDelta(t, 6, penalty.ScoreLoss, 1e-9)
+
+	require.Equal(t, []PlayerSummary{
+		{ID: "a", Name: "alice", Decisions: 2, Mistakes: 2, ScoreLoss: report.Mistakes[0].ScoreLoss + report.Mistakes[2].ScoreLoss},
+		{ID: "b", Name: "bob", Decisions: 2, Mistakes: 1, ScoreLoss: report.Mistakes[1].ScoreLoss},
+	}, report.Players)
+	// the penalty alice took on the first turn counts on the board of alice's pass on the second
+	require.Equal(t, 1, report.Mistakes[2].Penalties)
+
+	require.Len(t, report.Top(1).Mistakes, 1)
+	require.Len(t, report.Top(10).Mistakes, 3)
+
+	var out bytes.Buffer
+	require.NoError(t, report.WriteText(&out))
+	require.Contains(t, out.String(), "1     alice   active    1+1 R6 Y6 G6 B6  penalty  w R2")
+	require.Contains(t, out.String(), "pass")
+}
+
+func TestGame_Errors(t *testing.T) {
+	type testCase struct {
+		name          string
+		input         game.GameResult
+		expectedError string
+	}
+	players := []game.PlayerResult{{ID: "a", Name: "alice"}}
+	testCases := []testCase{
+		{
+			name:          "no decisions",
+			input:         game.GameResult{Players: players},
+			expectedError: "the game has no decisions to review",
+		},
+		{
+			name: "decision of a player not in the game",
+			input: game.GameResult{Players: players, Decisions: []game.Decision{
+				{Turn: 1, PlayerID: "b"},
+			}},
+			expectedError: "turn 1: decision of unknown player b",
+		},
+		{
+			name: "move the board does not allow",
+			input: game.GameResult{Players: players, Decisions: []game.Decision{
+				{
+					Turn: 3, PlayerID: "a",
+					Board:  board.State{Rows: map[actions.RowColor][]int{actions.RowColorRed: {5}}},
+					Chosen: actions.ActivePlayerTurn{WhiteDiceMove: moveOf(actions.RowColorRed, 4)},
+				},
+			}},
+			expectedError: "turn 3: decision of alice: cell 4 is to the left of already crossed off cells",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Game(tc.input)
+			require.EqualError(t, err, tc.expectedError)
+		})
+	}
+}
+
+func TestGame_PlayedGame(t *testing.T) {
+	first, err := player.NewStrategy(player.StrategyFirst, nil)
+	require.NoError(t, err)
+	careful, err := player.NewStrategy(player.StrategyCareful, nil)
+	require.NoError(t, err)
+	result := game.NewGameRunner([]player.Player{
+		player.NewStrategyPlayer("alice", first, nil),
+		player.NewStrategyPlayer("bob", careful, nil),
+	}, game.WithRand(rand.New(rand.NewSource(1))), game.WithDecisions()).RunGame()
+
+	report, err := Game(result)
+	require.NoError(t, err)
+	// every player decides once a turn, whether they are the active player or not
+	for _, summary := range report.Players {
+		require.Equal(t, result.Turns, summary.Decisions)
+	}
+	require.NotEmpty(t, report.Mistakes)
+}
@@ -0,0 +1,141 @@
+package scoresheet
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"strings"
+)
+
+// PNG writes the given sheets, one below the other, as a PNG image.
+// Text is drawn with a small built-in block font in capitals, so names keep only their letters, digits and spaces.
+func PNG(w io.Writer, sheets ...Sheet) error {
+	shapes, width, height := layout(sheets)
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for _, s := range shapes {
+		switch s.kind {
+		case shapeRect:
+			fillRect(img, s.x, s.y, s.w, s.h, s.fill)
+			if s.strokeWidth > 0 {
+				fillRect(img, s.x, s.y, s.w, s.strokeWidth, s.stroke)
+				fillRect(img, s.x, s.y+s.h-s.strokeWidth, s.w, s.strokeWidth, s.stroke)
+				fillRect(img, s.x, s.y, s.strokeWidth, s.h, s.stroke)
+				fillRect(img, s.x+s.w-s.strokeWidth, s.y, s.strokeWidth, s.h, s.stroke)
+			}
+		case shapeCircle:
+			fillCircle(img, s.x+s.w/2, s.y+s.h/2, min(s.w, s.h)/2, s.fill)
+		case shapeLine:
+			drawLine(img, s.x, s.y, s.x+s.w, s.y+s.h, s.strokeWidth, s.stroke)
+		case shapeText:
+			drawText(img, s)
+		}
+	}
+	return png.Encode(w, img)
+}
+
+func fillRect(img *image.RGBA, x, y, w, h int, c color.RGBA) {
+	for py := y; py < y+h; py++ {
+		for px := x; px < x+w; px++ {
+			img.SetRGBA(px, py, c)
+		}
+	}
+}
+
+func fillCircle(img *image.RGBA, cx, cy, r int, c color.RGBA) {
+	for py := cy - r; py <= cy+r; py++ {
+		for px := cx - r; px <= cx+r; px++ {
+			if (px-cx)*(px-cx)+(py-cy)*(py-cy) <= r*r {
+				img.SetRGBA(px, py, c)
+			}
+		}
+	}
+}
+
+// drawLine draws a line of the given width by stamping squares along it
+func drawLine(img *image.RGBA, x1, y1, x2, y2, width int, c color.RGBA) {
+	steps := max(abs(x2-x1), abs(y2-y1), 1)
+	for step := 0; step <= steps; step++ {
+		x := x1 + (x2-x1)*step/steps
+		y := y1 + (y2-y1)*step/steps
+		fillRect(img, x-width/2, y-width/2, width, width, c)
+	}
+}
+
+func abs(value int) int {
+	if value < 0 {
+		return -value
+	}
+	return value
+}
+
+// glyphs is a block font three pixels wide and five high, scaled up to the size of the text
+var glyphs = map[rune][5]string{
+	'0': {"###", "#.#", "#.#", "#.#", "###"},
+	'1': {".#.", "##.", ".#.", ".#.", "###"},
+	'2': {"###", "..#", "###", "#..", "###"},
+	'3': {"###", "..#", ".##", "..#", "###"},
+	'4': {"#.#", "#.#", "###", "..#", "..#"},
+	'5': {"###", "#..", "###", "..#", "###"},
+	'6': {"###", "#..", "###", "#.#", "###"},
+	'7': {"###", "..#", ".#.", ".#.", ".#."},
+	'8': {"###", "#.#", "###", "#.#", "###"},
+	'9': {"###", "#.#", "###", "..#", "###"},
+	'A': {".#.", "#.#", "###", "#.#", "#.#"},
+	'B': {"##.", "#.#", "##.", "#.#", "##."},
+	'C': {".##", "#..", "#..", "#..", ".##"},
+	'D': {"##.", "#.#", "#.#", "#.#", "##."},
+	'E': {"###", "#..", "##.", "#..", "###"},
+	'F': {"###", "#..", "##.", "#..", "#.."},
+	'G': {".##", "#..", "#.#", "#.#", ".##"},
+	'H': {"#.#", "#.#", "###", "#.#", "#.#"},
+	'I': {"###", ".#.", ".#.", ".#.", "###"},
+	'J': {"..#", "..#", "..#", "#.#", ".#."},
+	'K': {"#.#", "#.#", "##.", "#.#", "#.#"},
+	'L': {"#..", "#..", "#..", "#..", "###"},
+	'M': {"#.#", "###", "###", "#.#", "#.#"},
+	'N': {"##.", "#.#", "#.#", "#.#", "#.#"},
+	'O': {".#.", "#.#", "#.#", "#.#", ".#."},
+	'P': {"##.", "#.#", "##.", "#..", "#.."},
+	'Q': {".#.", "#.#", "#.#", "##.", ".##"},
+	'R': {"##.", "#.#", "##.", "#.#", "#.#"},
+	'S': {".##", "#..", ".#.", "..#", "##."},
+	'T': {"###", ".#.", ".#.", ".#.", ".#."},
+	'U': {"#.#", "#.#", "#.#", "#.#", "###"},
+	'V': {"#.#", "#.#", "#.#", "#.#", ".#."},
+	'W': {"#.#", "#.#", "###", "###", "#.#"},
+	'X': {"#.#", "#.#", ".#.", "#.#", "#.#"},
+	'Y': {"#.#", "#.#", ".#.", ".#.", ".#."},
+	'Z': {"###", "..#", ".#.", "#..", "###"},
+	'-': {"...", "...", "###", "...", "..."},
+	'+': {"...", ".#.", "###", ".#.", "..."},
+	'=': {"...", "###", "...", "###", "..."},
+	' ': {"...", "...", "...", "...", "..."},
+}
+
+// drawText draws the text of the given shape in the block font, leaving out the characters it has no glyph for
+func drawText(img *image.RGBA, s shape) {
+	var text []rune
+	for _, r := range strings.ToUpper(s.text) {
+		if _, ok := glyphs[r]; ok {
+			text = append(text, r)
+		}
+	}
+	scale := max(s.size/7, 1)
+	textWidth := len(text)*4*scale - scale
+	x := s.x
+	if !s.start {
+		x -= textWidth / 2
+	}
+	y := s.y - 5*scale/2
+	for _, r := range text {
+		for row, line := range glyphs[r] {
+			for col, pixel := range line {
+				if pixel == '#' {
+					fillRect(img, x+col*scale, y+row*scale, scale, scale, s.fill)
+				}
+			}
+		}
+		x += 4 * scale
+	}
+}
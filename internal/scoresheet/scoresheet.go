@@ -0,0 +1,230 @@
+// Package scoresheet draws boards as images resembling the printed Qwixx scoresheet:
+// a colored band per row with the numbered cells and the lock at its end, the crossed off cells struck through,
+// and the row totals, penalty track and final score below.
+// Sheets are laid out once and then written as SVG, or as PNG for places that cannot show vector images.
+package scoresheet
+
+import (
+	"fmt"
+	"image/color"
+	"qwixx/internal/game"
+	"qwixx/internal/game/actions"
+	"qwixx/internal/game/board"
+)
+
+// Sheet is one player's scoresheet
+type Sheet struct {
+	// Name is the name of the player, written at the top of the sheet
+	Name      string
+	Board     board.Board
+	Penalties int
+	// PenaltyValue is the number of points each penalty costs, 5 if zero
+	PenaltyValue int
+}
+
+// Score is the final score of the sheet, its board's score minus its penalties
+func (s Sheet) Score() int {
+	penaltyValue := s.PenaltyValue
+	if penaltyValue == 0 {
+		penaltyValue = game.DefaultGameConfig().PenaltyValue
+	}
+	return s.Board.CalculateScore() - penaltyValue*s.Penalties
+}
+
+// SheetsOf returns the sheets of every player of the given finished game, in play order
+func SheetsOf(result game.GameResult) ([]Sheet, error) {
+	sheets := make([]Sheet, 0, len(result.Players))
+	for _, playerResult := range result.Players {
+		playerBoard, err := board.FromState(playerResult.Board)
+		if err != nil {
+			return nil, fmt.Errorf("board of %v: %w", playerResult.Name, err)
+		}
+		sheets = append(sheets, Sheet{
+			Name:         playerResult.Name,
+			Board:        playerBoard,
+			Penalties:    playerResult.Penalties,
+			PenaltyValue: result.Config.PenaltyValue,
+		})
+	}
+	return sheets, nil
+}
+
+// the dimensions of a sheet in pixels
+const (
+	margin      = 16
+	cellSize    = 40
+	cellGap     = 4
+	bandPadding = 8
+	bandGap     = 6
+	headerSize  = 40
+	boxWidth    = 44
+	boxHeight   = 32
+	signWidth   = 20
+	penaltySize = 24
+	sheetGap    = 16
+	bandWidth   = 2*bandPadding + 12*(cellSize+cellGap) - cellGap
+	bandHeight  = 2*bandPadding + cellSize
+	sheetWidth  = 2*margin + bandWidth
+)
+
+var (
+	black = color.RGBA{R: 0x22, G: 0x22, B: 0x22, A: 0xff}
+	white = color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}
+	grey  = color.RGBA{R: 0x88, G: 0x88, B: 0x88, A: 0xff}
+)
+
+var rowColors = map[actions.RowColor]color.RGBA{
+	actions.RowColorRed:    {R: 0xd7, G: 0x26, B: 0x3d, A: 0xff},
+	actions.RowColorYellow: {R: 0xf4, G: 0xc2, B: 0x0d, A: 0xff},
+	actions.RowColorGreen:  {R: 0x2e, G: 0x9e, B: 0x44, A: 0xff},
+	actions.RowColorBlue:   {R: 0x1e, G: 0x63, B: 0xb5, A: 0xff},
+	actions.RowColorOrange: {R: 0xf0, G: 0x8a, B: 0x24, A: 0xff},
+	actions.RowColorPurple: {R: 0x7b, G: 0x3f, B: 0xa0, A: 0xff},
+}
+
+// tint lightens the given color towards white, as the cells of the printed sheet are
+func tint(c color.RGBA) color.RGBA {
+	lighten := func(v uint8) uint8 {
+		return uint8(int(v) + (0xff-int(v))*4/5)
+	}
+	return color.RGBA{R: lighten(c.R), G: lighten(c.G), B: lighten(c.B), A: 0xff}
+}
+
+type shapeKind int
+
+const (
+	shapeRect shapeKind = iota
+	shapeCircle
+	shapeLine
+	shapeText
+)
+
+// shape is one element of a laid out sheet.
+// Rectangles and circles fill the box at x and y of size w by h, lines go from x and y to x+w and y+h,
+// and text is written centered on x, or starting at x if start is set, with y at its middle.
+type shape struct {
+	kind        shapeKind
+	x, y, w, h  int
+	fill        color.RGBA
+	stroke      color.RGBA
+	strokeWidth int
+	text        string
+	size        int
+	start       bool
+}
+
+// layout lays out the given sheets one below the other, returning their shapes and the size of the image
+func layout(sheets []Sheet) (shapes []shape, width, height int) {
+	y := 0
+	for idx, sheet := range sheets {
+		if idx > 0 {
+			y += sheetGap
+		}
+		var sheetHeight int
+		shapes, sheetHeight = layoutSheet(shapes, sheet, y)
+		y += sheetHeight
+	}
+	return shapes, sheetWidth, y
+}
+
+// layoutSheet appends the shapes of the given sheet, starting at the given height, and returns the height of the sheet
+func layoutSheet(shapes []shape, sheet Sheet, top int) ([]shape, int) {
+	rowColorsOfSheet := sheet.Board.Variant().RowColors()
+	height := headerSize + len(rowColorsOfSheet)*(bandHeight+bandGap) + 2*boxHeight + penaltySize + 2*margin
+	shapes = append(shapes,
+		shape{kind: shapeRect, x: 0, y: top, w: sheetWidth, h: height, fill: white, stroke: grey, strokeWidth: 1},
+		shape{kind: shapeText, x: margin, y: top + margin + 8, text: sheet.Name, size: 20, fill: black, start: true},
+		shape{
+			kind: shapeText, x: sheetWidth - margin - 140, y: top + margin + 8,
+			text: fmt.Sprintf("score %v", sheet.Score()), size: 20, fill: black, start: true,
+		},
+	)
+
+	y := top + headerSize
+	for _, rowColor := range rowColorsOfSheet {
+		shapes = layoutRow(shapes, sheet.Board, rowColor, margin, y)
+		y += bandHeight + bandGap
+	}
+
+	// the row totals, added up and minus the penalties, give the score
+	y += boxHeight / 2
+	x := margin
+	for idx, rowColor := range rowColorsOfSheet {
+		if idx > 0 {
+			shapes = append(shapes, shape{kind: shapeText, x: x + signWidth/2, y: y + boxHeight/2, text: "+", size: 18, fill: black})
+			x += signWidth
+		}
+		shapes = appendBox(shapes, x, y, rowColors[rowColor], sheet.Board.RowScore(rowColor))
+		x += boxWidth
+	}
+	shapes = append(shapes, shape{kind: shapeText, x: x + signWidth/2, y: y + boxHeight/2, text: "-", size: 18, fill: black})
+	x += signWidth
+	shapes = appendBox(shapes, x, y, grey, sheet.Board.CalculateScore()-sheet.Score())
+	x += boxWidth
+	shapes = append(shapes, shape{kind: shapeText, x: x + signWidth/2, y: y + boxHeight/2, text: "=", size: 18, fill: black})
+	x += signWidth
+	shapes = appendBox(shapes, x, y, black, sheet.Score())
+
+	// the penalty track
+	y += boxHeight + boxHeight/2
+	shapes = append(shapes, shape{kind: shapeText, x: margin, y: y + penaltySize/2, text: "penalties", size: 14, fill: black, start: true})
+	x = margin + 100
+	for slot := 0; slot < max(4, sheet.Penalties); slot++ {
+		shapes = append(shapes, shape{
+			kind: shapeRect, x: x, y: y, w: penaltySize, h: penaltySize, fill: white, stroke: black, strokeWidth: 2,
+		})
+		if slot < sheet.Penalties {
+			shapes = appendCross(shapes, x, y, penaltySize, penaltySize)
+		}
+		x += penaltySize + cellGap
+	}
+	return shapes, height
+}
+
+// layoutRow appends the shapes of the band of the row with the given color at the given position
+func layoutRow(shapes []shape, b board.Board, rowColor actions.RowColor, left, top int) []shape {
+	shapes = append(shapes, shape{kind: shapeRect, x: left, y: top, w: bandWidth, h: bandHeight, fill: rowColors[rowColor]})
+	x := left + bandPadding
+	y := top + bandPadding
+	for _, cell := range b.Cells(rowColor) {
+		shapes = append(shapes,
+			shape{kind: shapeRect, x: x, y: y, w: cellSize, h: cellSize, fill: tint(rowColors[cell.Color])},
+			shape{
+				kind: shapeText, x: x + cellSize/2, y: y + cellSize/2, text: fmt.Sprint(cell.Number), size: 18, fill: rowColors[cell.Color],
+			},
+		)
+		// a cell crossed off more than once gets a cross for each time, side by side
+		crosses := b.CrossCount(rowColor, cell.Number)
+		for cross := 0; cross < crosses; cross++ {
+			shapes = appendCross(shapes, x+cross*cellSize/crosses, y, cellSize/crosses, cellSize)
+		}
+		x += cellSize + cellGap
+	}
+
+	// the lock at the end of the row, crossed off once the row is locked
+	shapes = append(shapes,
+		shape{kind: shapeCircle, x: x, y: y, w: cellSize, h: cellSize, fill: tint(rowColors[rowColor])},
+		shape{kind: shapeText, x: x + cellSize/2, y: y + cellSize/2, text: "L", size: 18, fill: rowColors[rowColor]},
+	)
+	if b.IsRowLocked(rowColor) {
+		shapes = appendCross(shapes, x, y, cellSize, cellSize)
+	}
+	return shapes
+}
+
+// appendBox appends a box with the given border color holding the given number
+func appendBox(shapes []shape, x, y int, border color.RGBA, number int) []shape {
+	return append(shapes,
+		shape{kind: shapeRect, x: x, y: y, w: boxWidth, h: boxHeight, fill: white, stroke: border, strokeWidth: 3},
+		shape{kind: shapeText, x: x + boxWidth/2, y: y + boxHeight/2, text: fmt.Sprint(number), size: 16, fill: black},
+	)
+}
+
+// appendCross appends a cross striking through the box at the given position
+func appendCross(shapes []shape, x, y, w, h int) []shape {
+	inset := min(w, h) / 6
+	return append(shapes,
+		shape{kind: shapeLine, x: x + inset, y: y + inset, w: w - 2*inset, h: h - 2*inset, stroke: black, strokeWidth: 4},
+		shape{kind: shapeLine, x: x + w - inset, y: y + inset, w: -(w - 2*inset), h: h - 2*inset, stroke: black, strokeWidth: 4},
+	)
+}
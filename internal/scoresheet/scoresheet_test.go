@@ -0,0 +1,91 @@
+package scoresheet
+
+import (
+	"bytes"
+	"encoding/xml"
+	"image/png"
+	"io"
+	"qwixx/internal/game"
+	"qwixx/internal/game/actions"
+	"qwixx/internal/game/board"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testSheet(t *testing.T) Sheet {
+	t.Helper()
+	b, err := board.FromState(board.State{Rows: map[actions.RowColor][]int{
+		actions.RowColorRed:   {2, 3, 4, 5, 6, 12},
+		actions.RowColorGreen: {11},
+	}, Locked: []actions.RowColor{actions.RowColorRed}})
+	require.NoError(t, err)
+	return Sheet{Name: "alice & <bob>", Board: b, Penalties: 1}
+}
+
+func TestSheet_Score(t *testing.T) {
+	sheet := testSheet(t)
+	// six crosses and the lock in red and one in green, minus a penalty
+	require.Equal(t, 28+1-5, sheet.Score())
+	sheet.PenaltyValue = 2
+	require.Equal(t, 28+1-2, sheet.Score())
+}
+
+func TestSVG(t *testing.T) {
+	var out bytes.Buffer
+	require.NoError(t, SVG(&out, testSheet(t), Sheet{Name: "carol", Board: board.NewGameBoard()}))
+	drawn := out.String()
+
+	// the SVG is well formed, with the names escaped
+	decoder := xml.NewDecoder(strings.NewReader(drawn))
+	for {
+		_, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+	}
+	require.True(t, strings.HasPrefix(drawn, `<svg xmlns="http://www.w3.org/2000/svg"`))
+	require.Contains(t, drawn, ">alice &amp; &lt;bob&gt;</text>")
+	require.Contains(t, drawn, ">carol</text>")
+	require.Contains(t, drawn, ">score 24</text>")
+
+	// two crosses for each of the seven crossed off cells, the lock and the penalty of the first sheet
+	require.Equal(t, 2*(7+1+1), strings.Count(drawn, "<line "))
+}
+
+func TestPNG(t *testing.T) {
+	var out bytes.Buffer
+	bigPoints, err := board.NewBoard(board.VariantBigPoints)
+	require.NoError(t, err)
+	require.NoError(t, PNG(&out, testSheet(t), Sheet{Name: "carol", Board: bigPoints}))
+
+	img, err := png.Decode(&out)
+	require.NoError(t, err)
+	_, width, height := layout([]Sheet{testSheet(t), {Board: bigPoints}})
+	require.Equal(t, width, img.Bounds().Dx())
+	require.Equal(t, height, img.Bounds().Dy())
+	// the top left cell of the red band is crossed off
+	r, g, b, _ := img.At(margin+bandPadding+cellSize/2, headerSize+bandPadding+cellSize/2).RGBA()
+	require.Equal(t, []uint32{0x22, 0x22, 0x22}, []uint32{r >> 8, g >> 8, b >> 8})
+}
+
+func TestSheetsOf(t *testing.T) {
+	result := game.GameResult{
+		Players: []game.PlayerResult{
+			{Name: "alice", Penalties: 2, Board: board.State{Rows: map[actions.RowColor][]int{actions.RowColorRed: {2}}}},
+			{Name: "bob", Board: board.State{}},
+		},
+		Config: game.GameConfig{PenaltyValue: 3},
+	}
+	sheets, err := SheetsOf(result)
+	require.NoError(t, err)
+	require.Len(t, sheets, 2)
+	require.Equal(t, "alice", sheets[0].Name)
+	require.Equal(t, 1-6, sheets[0].Score())
+
+	result.Players[1].Board = board.State{Rows: map[actions.RowColor][]int{actions.RowColorRed: {12}}}
+	_, err = SheetsOf(result)
+	require.ErrorContains(t, err, "board of bob")
+}
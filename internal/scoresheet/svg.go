@@ -0,0 +1,58 @@
+package scoresheet
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"image/color"
+	"io"
+)
+
+// SVG writes the given sheets, one below the other, as an SVG image
+func SVG(w io.Writer, sheets ...Sheet) error {
+	shapes, width, height := layout(sheets)
+	out := bufio.NewWriter(w)
+	fmt.Fprintf(out, `<svg xmlns="http://www.w3.org/2000/svg" width="%v" height="%v" viewBox="0 0 %v %v">`+"\n", width, height, width, height)
+	for _, s := range shapes {
+		switch s.kind {
+		case shapeRect:
+			fmt.Fprintf(out, `<rect x="%v" y="%v" width="%v" height="%v" rx="4" %v/>`+"\n", s.x, s.y, s.w, s.h, paint(s))
+		case shapeCircle:
+			fmt.Fprintf(out, `<circle cx="%v" cy="%v" r="%v" %v/>`+"\n", s.x+s.w/2, s.y+s.h/2, min(s.w, s.h)/2, paint(s))
+		case shapeLine:
+			fmt.Fprintf(
+				out, `<line x1="%v" y1="%v" x2="%v" y2="%v" stroke="%v" stroke-width="%v" stroke-linecap="round"/>`+"\n",
+				s.x, s.y, s.x+s.w, s.y+s.h, hex(s.stroke), s.strokeWidth,
+			)
+		case shapeText:
+			anchor := "middle"
+			if s.start {
+				anchor = "start"
+			}
+			fmt.Fprintf(
+				out, `<text x="%v" y="%v" font-family="Helvetica, Arial, sans-serif" font-size="%v" font-weight="bold" `+
+					`text-anchor="%v" dominant-baseline="central" fill="%v">`,
+				s.x, s.y, s.size, anchor, hex(s.fill),
+			)
+			if err := xml.EscapeText(out, []byte(s.text)); err != nil {
+				return err
+			}
+			fmt.Fprint(out, "</text>\n")
+		}
+	}
+	fmt.Fprint(out, "</svg>\n")
+	return out.Flush()
+}
+
+// paint is the fill and stroke attributes of a rectangle or circle
+func paint(s shape) string {
+	attributes := fmt.Sprintf(`fill="%v"`, hex(s.fill))
+	if s.strokeWidth > 0 {
+		attributes += fmt.Sprintf(` stroke="%v" stroke-width="%v"`, hex(s.stroke), s.strokeWidth)
+	}
+	return attributes
+}
+
+func hex(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
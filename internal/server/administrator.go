@@ -1,37 +1,243 @@
 package server
 
 import (
-	"github.com/google/uuid"
+	"fmt"
+	"log/slog"
 	"qwixx/internal/game"
+	"qwixx/internal/game/board"
 	"qwixx/internal/game/player"
+	"qwixx/internal/game/ruleset"
+	"qwixx/internal/logging"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
 )
 
 type GameID string
 
+// gameStartListener is implemented by players who want to know who they are playing against before the game begins
+type gameStartListener interface {
+	gameStarted(
+		gameID GameID, self player.PlayerID, playerNames map[player.PlayerID]string,
+		variant board.Variant, rules ruleset.Ruleset, config game.GameConfig,
+	)
+}
+
 type Administrator struct {
+	mu      sync.Mutex
 	lobbies map[GameID][]player.Player
-	runners []game.GameRunner
+	// variants are the sheets the games of the lobbies will be played on
+	variants map[GameID]board.Variant
+	// rulesets are the rules for crossing off cells the games of the lobbies will be played by
+	rulesets map[GameID]ruleset.Ruleset
+	// configs are the house rules the games of the lobbies will be played by, with the left out rules filled in
+	configs map[GameID]game.GameConfig
+	games   map[GameID]*runningGame
+	// finished lists the games that are over, the earliest to end first, to forget them once they are no longer kept
+	finished []GameID
+	// maxFinished is how many finished games are kept, and finishedTTL how long each is kept for
+	maxFinished int
+	finishedTTL time.Duration
+	now         func() time.Time
+	logger      *slog.Logger
+}
+
+// runningGame is a game that has left its lobby
+type runningGame struct {
+	players []player.Player
+	runner  game.GameRunner
+	// result is how the game ended, nil while it is being played
+	result *game.GameResult
+	// endedAt is when the game ended
+	endedAt time.Time
 }
 
 func NewAdministrator() *Administrator {
-	return &Administrator{lobbies: make(map[GameID][]player.Player)}
+	return &Administrator{
+		lobbies:     make(map[GameID][]player.Player),
+		variants:    make(map[GameID]board.Variant),
+		rulesets:    make(map[GameID]ruleset.Ruleset),
+		configs:     make(map[GameID]game.GameConfig),
+		games:       make(map[GameID]*runningGame),
+		maxFinished: DefaultMaxFinishedGames,
+		finishedTTL: DefaultFinishedGameTTL,
+		now:         time.Now,
+		logger:      logging.Discard(),
+	}
 }
 
-func (a *Administrator) CreateGame(host player.Player) GameID {
+// SetRetention keeps at most the given number of finished games, each for at most the given time,
+// forgetting the earliest to end first
+func (a *Administrator) SetRetention(maxFinished int, ttl time.Duration) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.maxFinished = maxFinished
+	a.finishedTTL = ttl
+	a.forgetFinishedGames()
+}
+
+// SetLogger makes the games started from now on log to the given logger
+func (a *Administrator) SetLogger(logger *slog.Logger) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.logger = logger
+}
+
+// CreateGame opens a lobby hosted by the given player for a game played on the sheet of the given variant,
+// by the given rules for crossing off cells and by the given house rules, which must be valid
+func (a *Administrator) CreateGame(host player.Player, variant board.Variant, rules ruleset.Ruleset, config game.GameConfig) GameID {
+	a.mu.Lock()
+	defer a.mu.Unlock()
 	randomGameID := GameID(uuid.New().String())
 	a.lobbies[randomGameID] = []player.Player{host}
+	a.variants[randomGameID] = variant
+	a.rulesets[randomGameID] = rules
+	a.configs[randomGameID] = config.WithDefaults()
 	return randomGameID
 }
 
-func (a *Administrator) JoinGame(gameID GameID, newPlayer player.Player) {
+// LobbyVariant returns the variant the game of the given lobby will be played on
+func (a *Administrator) LobbyVariant(gameID GameID) board.Variant {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.variants[gameID]
+}
+
+// LobbyRuleset returns the rules for crossing off cells the game of the given lobby will be played by, nil if there is no such lobby
+func (a *Administrator) LobbyRuleset(gameID GameID) ruleset.Ruleset {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.rulesets[gameID]
+}
+
+// LobbyConfig returns the house rules the game of the given lobby will be played by
+func (a *Administrator) LobbyConfig(gameID GameID) game.GameConfig {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.configs[gameID]
+}
+
+func (a *Administrator) JoinGame(gameID GameID, newPlayer player.Player) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if _, ok := a.lobbies[gameID]; !ok {
+		return fmt.Errorf("no lobby %v", gameID)
+	}
 	a.lobbies[gameID] = append(a.lobbies[gameID], newPlayer)
+	return nil
+}
+
+// LobbyPlayers returns the players waiting in the given lobby, the first of which is its host
+func (a *Administrator) LobbyPlayers(gameID GameID) []player.Player {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return append([]player.Player(nil), a.lobbies[gameID]...)
 }
 
-func (a *Administrator) StartGame(gameID GameID) {
-	// TODO does this take the players and then remove them from the map....without deleting them altogether. need pointers maybe?
-	players := a.lobbies[gameID]
+// Members returns everyone in the given lobby or game
+func (a *Administrator) Members(gameID GameID) []player.Player {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if running, ok := a.games[gameID]; ok {
+		return append([]player.Player(nil), running.players...)
+	}
+	return append([]player.Player(nil), a.lobbies[gameID]...)
+}
+
+// StartGame moves the players of the given lobby into a new game and runs it in the background.
+// Players listening for the start of the game are told who they are playing against before the game begins.
+func (a *Administrator) StartGame(gameID GameID) (game.GameRunner, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	players, ok := a.lobbies[gameID]
+	if !ok {
+		return nil, fmt.Errorf("no lobby %v", gameID)
+	}
+	if len(players) < 2 {
+		return nil, fmt.Errorf("a game needs at least two players, lobby %v has %v", gameID, len(players))
+	}
+	variant := a.variants[gameID]
+	rules := a.rulesets[gameID]
+	config := a.configs[gameID]
 	delete(a.lobbies, gameID)
-	runner := game.NewGameRunner(players)
-	a.runners = append(a.runners, runner)
-	go runner.RunGame()
+	delete(a.variants, gameID)
+	delete(a.rulesets, gameID)
+	delete(a.configs, gameID)
+	runner := game.NewGameRunner(
+		players,
+		game.WithLogger(a.logger), game.WithGameID(string(gameID)), game.WithVariant(variant),
+		game.WithRuleset(rules), game.WithConfig(config), game.WithDecisions(),
+	)
+	a.games[gameID] = &runningGame{players: players, runner: runner}
+
+	playersByID := runner.Players()
+	playerNames := make(map[player.PlayerID]string, len(playersByID))
+	for playerID, pl := range playersByID {
+		playerNames[playerID] = pl.GetName()
+	}
+	for playerID, pl := range playersByID {
+		if listener, ok := pl.(gameStartListener); ok {
+			listener.gameStarted(gameID, playerID, playerNames, variant, rules, config)
+		}
+	}
+	go a.runGame(gameID, runner)
+	return runner, nil
+}
+
+// runGame plays the given game to its end and keeps its result
+func (a *Administrator) runGame(gameID GameID, runner game.GameRunner) {
+	result := runner.RunGame()
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if err := result.Validate(); err != nil {
+		// the game is over either way, so keep its result, but a result that could not come about is a bug worth knowing of
+		a.logger.Error(fmt.Sprintf("game %v ended with an invalid result: %v", gameID, err), "game_id", gameID, "error", err)
+	}
+	running := a.games[gameID]
+	running.result = &result
+	running.endedAt = a.now()
+	a.finished = append(a.finished, gameID)
+	a.forgetFinishedGames()
+}
+
+// forgetFinishedGames forgets the finished games that were kept too long, or that more recently finished games push out
+func (a *Administrator) forgetFinishedGames() {
+	expired := a.now().Add(-a.finishedTTL)
+	forget := 0
+	for forget < len(a.finished) &&
+		(len(a.finished)-forget > a.maxFinished || !a.games[a.finished[forget]].endedAt.After(expired)) {
+		delete(a.games, a.finished[forget])
+		forget++
+	}
+	a.finished = a.finished[forget:]
+}
+
+// GameResult returns how the given game ended, which is nil while it is being played,
+// and false if there is no such game or it ended too long ago to be kept
+func (a *Administrator) GameResult(gameID GameID) (*game.GameResult, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.forgetFinishedGames()
+	running, ok := a.games[gameID]
+	if !ok {
+		return nil, false
+	}
+	return running.result, true
+}
+
+// Counts returns how many lobbies are waiting for their game to start, how many games are being played
+// and how many finished games are kept
+func (a *Administrator) Counts() (lobbies, playing, finished int) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.forgetFinishedGames()
+	for _, running := range a.games {
+		if running.result == nil {
+			playing++
+		} else {
+			finished++
+		}
+	}
+	return len(a.lobbies), playing, finished
 }
@@ -1,8 +1,13 @@
 package server
 
 import (
+	"qwixx/internal/game"
+	"qwixx/internal/game/board"
 	"qwixx/internal/game/player"
+	"qwixx/internal/game/ruleset"
+	"slices"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -12,17 +17,17 @@ func TestAdministrator_CreateGame(t *testing.T) {
 	require.Empty(t, admin.lobbies)
 
 	player1 := player.NewComputerPlayer("player1")
-	game1ID := admin.CreateGame(player1)
+	game1ID := admin.CreateGame(player1, board.VariantClassic, ruleset.Classic(), game.GameConfig{})
 	require.Len(t, admin.lobbies, 1)
 	require.Len(t, admin.lobbies[game1ID], 1)
 
 	player2 := player.NewComputerPlayer("player2")
-	game2ID := admin.CreateGame(player2)
+	game2ID := admin.CreateGame(player2, board.VariantClassic, ruleset.Classic(), game.GameConfig{})
 	require.Len(t, admin.lobbies, 2)
 	require.Len(t, admin.lobbies[game2ID], 1)
 
 	player3 := player.NewComputerPlayer("player3")
-	game3ID := admin.CreateGame(player3)
+	game3ID := admin.CreateGame(player3, board.VariantClassic, ruleset.Classic(), game.GameConfig{})
 	require.Len(t, admin.lobbies, 3)
 	require.Len(t, admin.lobbies[game3ID], 1)
 }
@@ -32,7 +37,7 @@ func TestAdministrator_JoinGame(t *testing.T) {
 	require.Empty(t, admin.lobbies)
 
 	player1 := player.NewComputerPlayer("player1")
-	game1ID := admin.CreateGame(player1)
+	game1ID := admin.CreateGame(player1, board.VariantClassic, ruleset.Classic(), game.GameConfig{})
 	require.Len(t, admin.lobbies, 1)
 	require.Len(t, admin.lobbies[game1ID], 1)
 
@@ -52,7 +57,7 @@ func TestAdministrator_StartGame(t *testing.T) {
 	require.Empty(t, admin.lobbies)
 
 	player1 := player.NewComputerPlayer("player1")
-	game1ID := admin.CreateGame(player1)
+	game1ID := admin.CreateGame(player1, board.VariantClassic, ruleset.Classic(), game.GameConfig{})
 
 	player2 := player.NewComputerPlayer("player2")
 	admin.JoinGame(game1ID, player2)
@@ -66,3 +71,47 @@ func TestAdministrator_StartGame(t *testing.T) {
 	admin.StartGame(game1ID)
 	require.Len(t, admin.lobbies, 0)
 }
+
+func TestAdministrator_FinishedGameRetention(t *testing.T) {
+	type testCase struct {
+		name        string
+		maxFinished int
+		ttl         time.Duration
+		elapsed     time.Duration
+		expectKept  []int
+	}
+	testCases := []testCase{
+		{name: "keeps every game within the limits", maxFinished: 3, ttl: time.Hour, expectKept: []int{0, 1, 2}},
+		{name: "forgets the earliest games past the limit", maxFinished: 2, ttl: time.Hour, expectKept: []int{1, 2}},
+		{name: "forgets the games kept too long", maxFinished: 3, ttl: time.Hour, elapsed: time.Hour},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			admin := NewAdministrator()
+			now := time.Now()
+			admin.now = func() time.Time { return now }
+			admin.SetRetention(tc.maxFinished, tc.ttl)
+
+			var gameIDs []GameID
+			for idx := 0; idx < 3; idx++ {
+				gameID := admin.CreateGame(player.NewComputerPlayer("alice"), board.VariantClassic, ruleset.Classic(), game.GameConfig{})
+				require.NoError(t, admin.JoinGame(gameID, player.NewComputerPlayer("bob")))
+				_, err := admin.StartGame(gameID)
+				require.NoError(t, err)
+				// the games are played one after the other, the last to end always being kept
+				for result, _ := admin.GameResult(gameID); result == nil; result, _ = admin.GameResult(gameID) {
+					time.Sleep(time.Millisecond)
+				}
+				gameIDs = append(gameIDs, gameID)
+			}
+			now = now.Add(tc.elapsed)
+
+			_, _, finished := admin.Counts()
+			require.Equal(t, len(tc.expectKept), finished)
+			for idx, gameID := range gameIDs {
+				_, ok := admin.GameResult(gameID)
+				require.Equal(t, slices.Contains(tc.expectKept, idx), ok, "game %d", idx)
+			}
+		})
+	}
+}
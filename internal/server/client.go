@@ -1,14 +1,434 @@
 package server
 
 import (
+	"errors"
 	"fmt"
+	"qwixx/internal/analysis"
+	"qwixx/internal/game"
+	"qwixx/internal/game/actions"
+	"qwixx/internal/game/board"
+	"qwixx/internal/game/player"
+	"qwixx/internal/game/ruleset"
+	"qwixx/internal/protocol"
+	"strings"
+	"sync"
+	"time"
+
 	"github.com/gorilla/websocket"
 )
 
+var _ player.Player = &Client{}
+
+const (
+	// writeTimeout is how long writing a message to a client may take before the client is disconnected,
+	// so a client that stops reading cannot hold up its game
+	writeTimeout = 10 * time.Second
+	// maxMessageBytes is the size of the largest message read from a client, a larger one closing the connection
+	maxMessageBytes = 64 << 10
+)
+
+// Client is a player connected over a websocket.
+// It handles the client's lobby requests, and relays the game's prompts and informs to it, see the protocol package.
 type Client struct {
-	conn *websocket.Conn
+	server *serverImpl
+	conn   *websocket.Conn
+
+	writeMu sync.Mutex
+	// disconnected is set once a write fails, the messages after it being dropped
+	disconnected bool
+
+	mu             sync.Mutex
+	name           string
+	gameID         GameID
+	self           player.PlayerID
+	playerNames    map[player.PlayerID]string
+	opponentBoards map[player.PlayerID]board.Board
+	variant        board.Variant
+	rules          ruleset.Ruleset
+	config         game.GameConfig
+	promptID       int
+	// prompted is the prompt waiting for the client's turn, kept to work out hints for it
+	prompted pendingPrompt
+
+	turns chan protocol.SubmitTurn
+	done  chan struct{}
 }
 
+// pendingPrompt is what the client was prompted with
+type pendingPrompt struct {
+	messageType protocol.MessageType
+	board       board.Board
+	diceRoll    actions.DiceRoll
+}
+
+func newClient(server *serverImpl, conn *websocket.Conn) *Client {
+	return &Client{
+		server:         server,
+		conn:           conn,
+		opponentBoards: make(map[player.PlayerID]board.Board),
+		turns:          make(chan protocol.SubmitTurn, 1),
+		done:           make(chan struct{}),
+	}
+}
+
+// handleWSConnection reads the client's messages until the connection closes
 func (c *Client) handleWSConnection() {
-	fmt.Println("handling connection")
+	defer close(c.done)
+	defer c.conn.Close()
+	c.conn.SetReadLimit(maxMessageBytes)
+	for {
+		var message protocol.Message
+		if err := c.conn.ReadJSON(&message); err != nil {
+			if !websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				c.server.logger().Warn("client disconnected", "client", c.GetName(), "error", err)
+			}
+			return
+		}
+		if err := c.handleMessage(message); err != nil {
+			c.send(protocol.MessageError, protocol.Error{Message: err.Error()})
+		}
+	}
+}
+
+func (c *Client) handleMessage(message protocol.Message) error {
+	switch message.Type {
+	case protocol.MessageCreateLobby:
+		var request protocol.CreateLobby
+		if err := message.Decode(&request); err != nil {
+			return err
+		}
+		variant, err := board.ParseVariant(request.Variant)
+		if err != nil {
+			return err
+		}
+		rules, err := ruleset.New(request.Ruleset)
+		if err != nil {
+			return err
+		}
+		if err := request.Config.Validate(variant); err != nil {
+			return fmt.Errorf("invalid house rules: %w", err)
+		}
+		if err := c.enterLobby(request.Name); err != nil {
+			return err
+		}
+		gameID := c.server.admin.CreateGame(c, variant, rules, request.Config)
+		c.setGameID(gameID)
+		c.server.broadcastLobbyState(gameID)
+	case protocol.MessageJoinLobby:
+		var request protocol.JoinLobby
+		if err := message.Decode(&request); err != nil {
+			return err
+		}
+		if err := c.enterLobby(request.Name); err != nil {
+			return err
+		}
+		gameID := GameID(request.Code)
+		if err := c.server.admin.JoinGame(gameID, c); err != nil {
+			c.setName("")
+			return err
+		}
+		c.setGameID(gameID)
+		c.server.broadcastLobbyState(gameID)
+	case protocol.MessageAddBot:
+		var request protocol.AddBot
+		if err := message.Decode(&request); err != nil {
+			return err
+		}
+		gameID, err := c.hostedLobby("add bots")
+		if err != nil {
+			return err
+		}
+		if err := c.server.admin.JoinGame(gameID, player.NewComputerPlayer(request.Name)); err != nil {
+			return err
+		}
+		c.server.broadcastLobbyState(gameID)
+	case protocol.MessageStartGame:
+		gameID, err := c.hostedLobby("start the game")
+		if err != nil {
+			return err
+		}
+		if _, err := c.server.admin.StartGame(gameID); err != nil {
+			return err
+		}
+	case protocol.MessageSubmitTurn:
+		var submission protocol.SubmitTurn
+		if err := message.Decode(&submission); err != nil {
+			return err
+		}
+		// only the latest submission matters, so replace one nobody has picked up yet
+		select {
+		case <-c.turns:
+		default:
+		}
+		c.turns <- submission
+	case protocol.MessageRequestHint:
+		var request protocol.RequestHint
+		if err := message.Decode(&request); err != nil {
+			return err
+		}
+		hint, err := c.hint(request.PromptID)
+		if err != nil {
+			return err
+		}
+		c.send(protocol.MessageHint, hint)
+	case protocol.MessageChat:
+		var chat protocol.Chat
+		if err := message.Decode(&chat); err != nil {
+			return err
+		}
+		gameID, err := c.lobby()
+		if err != nil {
+			return err
+		}
+		c.server.broadcast(gameID, protocol.MessageChat, protocol.Chat{From: c.GetName(), Text: chat.Text})
+	default:
+		return fmt.Errorf("unknown message type %q", message.Type)
+	}
+	return nil
+}
+
+// hint recommends an answer to the prompt with the given ID, if it is the latest prompt and the game allows hints
+func (c *Client) hint(promptID int) (protocol.Hint, error) {
+	c.mu.Lock()
+	config, prompted, latestPromptID := c.config, c.prompted, c.promptID
+	c.mu.Unlock()
+	if !config.Hints {
+		return protocol.Hint{}, errors.New("hints are not allowed in this game")
+	}
+	if prompted.board == nil || promptID != latestPromptID {
+		return protocol.Hint{}, fmt.Errorf("prompt %v is not waiting for a turn", promptID)
+	}
+
+	var hint analysis.Hint
+	if prompted.messageType == protocol.MessagePromptActive {
+		hint = analysis.ActiveHint(prompted.board, prompted.diceRoll, config.PenaltyValue)
+	} else {
+		hint = analysis.InactiveHint(prompted.board, prompted.diceRoll)
+	}
+	return protocol.Hint{
+		PromptID:      promptID,
+		WhiteDiceMove: hint.Turn.WhiteDiceMove,
+		ColorDiceMove: hint.Turn.ColorDiceMove,
+		Explanation:   hint.Explanation,
+	}, nil
+}
+
+// enterLobby names the client as it enters a lobby, which it can only do once
+func (c *Client) enterLobby(name string) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return errors.New("a name is needed to play")
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.name != "" {
+		return fmt.Errorf("already in lobby %v", c.gameID)
+	}
+	c.name = name
+	return nil
+}
+
+func (c *Client) setName(name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.name = name
+}
+
+func (c *Client) setGameID(gameID GameID) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.gameID = gameID
+}
+
+// lobby returns the lobby or game the client is in
+func (c *Client) lobby() (GameID, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.gameID == "" {
+		return "", errors.New("not in a lobby")
+	}
+	return c.gameID, nil
+}
+
+// hostedLobby returns the lobby the client is in if the client is its host, the first player in it,
+// naming what only the host can do in the error otherwise
+func (c *Client) hostedLobby(action string) (GameID, error) {
+	gameID, err := c.lobby()
+	if err != nil {
+		return "", err
+	}
+	// a lobby that has no players has already been started, which the administrator reports
+	if players := c.server.admin.LobbyPlayers(gameID); len(players) > 0 && players[0] != player.Player(c) {
+		return "", fmt.Errorf("only the host %v can %v", players[0].GetName(), action)
+	}
+	return gameID, nil
+}
+
+// send writes a message to the client. A message that cannot be written in time disconnects the client,
+// which then passes every turn it is prompted for, as the connection cannot be written to after a failed write.
+func (c *Client) send(messageType protocol.MessageType, payload any) {
+	message, err := protocol.NewMessage(messageType, payload)
+	if err != nil {
+		c.server.logger().Error("encoding message", "type", messageType, "error", err)
+		return
+	}
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	if c.disconnected {
+		return
+	}
+	err = c.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+	if err == nil {
+		err = c.conn.WriteJSON(message)
+	}
+	if err != nil {
+		c.server.logger().Warn("disconnecting client", "client", c.GetName(), "type", messageType, "error", err)
+		c.disconnected = true
+		// closing the connection ends handleWSConnection, which stops the client being prompted
+		_ = c.conn.Close()
+	}
+}
+
+func (c *Client) logf(format string, args ...any) {
+	c.send(protocol.MessageLog, protocol.Log{Text: fmt.Sprintf(format, args...)})
+}
+
+func (c *Client) gameStarted(
+	gameID GameID, self player.PlayerID, playerNames map[player.PlayerID]string,
+	variant board.Variant, rules ruleset.Ruleset, config game.GameConfig,
+) {
+	c.mu.Lock()
+	c.self = self
+	c.playerNames = playerNames
+	c.variant = variant
+	c.rules = rules
+	// hints weigh penalties by the config, which leaves out the rules taken from the defaults
+	c.config = config.WithDefaults()
+	c.mu.Unlock()
+
+	players := make([]protocol.PlayerInfo, 0, len(playerNames))
+	for playerID, name := range playerNames {
+		players = append(players, protocol.PlayerInfo{ID: playerID, Name: name})
+	}
+	c.send(protocol.MessageGameStarted, protocol.GameStarted{
+		GameID: string(gameID), You: self, Players: players, Variant: variant, Ruleset: rules.Name(), Config: config,
+	})
+}
+
+func (c *Client) playerName(playerID player.PlayerID) string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if name, ok := c.playerNames[playerID]; ok {
+		return name
+	}
+	return string(playerID)
+}
+
+// prompt sends a prompt of the given type and waits for the client to answer it.
+// A client that disconnects or does not answer in time is treated as passing.
+func (c *Client) prompt(messageType protocol.MessageType, playerBoard board.Board, diceRoll actions.DiceRoll) protocol.SubmitTurn {
+	c.mu.Lock()
+	c.promptID++
+	promptID := c.promptID
+	c.prompted = pendingPrompt{messageType: messageType, board: playerBoard.Copy(), diceRoll: diceRoll}
+	c.mu.Unlock()
+	defer func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		c.prompted = pendingPrompt{}
+	}()
+
+	c.send(messageType, protocol.Prompt{PromptID: promptID, Board: board.StateOf(playerBoard), DiceRoll: diceRoll})
+	timeout := time.After(c.server.settings.turnTimeout())
+	for {
+		select {
+		case submission := <-c.turns:
+			if submission.PromptID == promptID {
+				return submission
+			}
+		case <-timeout:
+			c.logf("no turn submitted in time, passing")
+			return protocol.SubmitTurn{PromptID: promptID}
+		case <-c.done:
+			return protocol.SubmitTurn{PromptID: promptID}
+		}
+	}
+}
+
+func (c *Client) GetName() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.name
+}
+
+func (c *Client) InformOfPlayOrder(playerNames []string) {
+	c.send(protocol.MessagePlayOrder, protocol.PlayOrder{Names: playerNames})
+	c.logf("play order: %v", strings.Join(playerNames, ", "))
+}
+
+func (c *Client) PromptActivePlayerTurn(playerBoard board.Board, diceRoll actions.DiceRoll) actions.ActivePlayerTurn {
+	submission := c.prompt(protocol.MessagePromptActive, playerBoard, diceRoll)
+	return actions.ActivePlayerTurn{WhiteDiceMove: submission.WhiteDiceMove, ColorDiceMove: submission.ColorDiceMove}
+}
+
+func (c *Client) PromptInactivePlayerTurn(playerBoard board.Board, diceRoll actions.DiceRoll) actions.InactivePlayerTurn {
+	submission := c.prompt(protocol.MessagePromptInactive, playerBoard, diceRoll)
+	return actions.InactivePlayerTurn{WhiteDiceMove: submission.WhiteDiceMove}
+}
+
+func (c *Client) InformSuccessfulTurn(updatedBoard board.Board) {
+	c.mu.Lock()
+	self := c.self
+	c.mu.Unlock()
+	c.send(protocol.MessageBoardUpdate, protocol.BoardUpdate{PlayerID: self, Board: board.StateOf(updatedBoard)})
+}
+
+func (c *Client) InformInvalidTurn(err error) {
+	c.mu.Lock()
+	promptID := c.promptID
+	c.mu.Unlock()
+	rejection := board.ViolationOf(err)
+	c.send(protocol.MessageInvalidTurn, protocol.InvalidTurn{PromptID: promptID, Code: rejection.Code, Message: rejection.Message})
+}
+
+// InformOfOpponentMove crosses off the move on the client's copy of the opponent's board, and sends the client the updated board
+func (c *Client) InformOfOpponentMove(playerID player.PlayerID, move actions.Move) {
+	c.mu.Lock()
+	opponentBoard, ok := c.opponentBoards[playerID]
+	if !ok {
+		opponentBoard, _ = board.NewBoard(c.variant, board.WithRuleset(c.rules))
+		c.opponentBoards[playerID] = opponentBoard
+	}
+	err := opponentBoard.MakeMove(move)
+	state := board.StateOf(opponentBoard)
+	c.mu.Unlock()
+	if err != nil {
+		c.server.logger().Error("tracking opponent", "opponent_id", playerID, "client", c.GetName(), "error", err)
+		return
+	}
+	c.send(protocol.MessageBoardUpdate, protocol.BoardUpdate{PlayerID: playerID, Board: state})
+	c.logf("%v crossed off %v %v", c.playerName(playerID), move.RowColor, move.CellNumber)
+}
+
+func (c *Client) InformRowLocked(color actions.RowColor) {
+	c.mu.Lock()
+	for _, opponentBoard := range c.opponentBoards {
+		opponentBoard.LockRow(color)
+	}
+	c.mu.Unlock()
+	c.send(protocol.MessageRowLocked, protocol.RowLocked{RowColor: color})
+	c.logf("the %v row was locked", color)
+}
+
+func (c *Client) InformWin() {
+	c.mu.Lock()
+	self := c.self
+	c.mu.Unlock()
+	c.send(protocol.MessageGameOver, protocol.GameOver{Won: true, WinnerID: self})
+	c.logf("you won!")
+}
+
+func (c *Client) InformLoss(winnerID player.PlayerID) {
+	c.send(protocol.MessageGameOver, protocol.GameOver{Won: false, WinnerID: winnerID})
+	c.logf("%v won the game", c.playerName(winnerID))
 }
@@ -1,40 +1,253 @@
 package server
 
 import (
+	"encoding/json"
 	"fmt"
-	"github.com/gorilla/websocket"
-	"log"
+	"log/slog"
 	"net/http"
+	"qwixx/internal/game"
+	"qwixx/internal/game/player"
+	"qwixx/internal/protocol"
+	"qwixx/internal/review"
+	"qwixx/internal/scoresheet"
+	"slices"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// DefaultTurnTimeout is how long a connected client has to submit a turn if no timeout is configured
+const DefaultTurnTimeout = 2 * time.Minute
+
+const (
+	// DefaultMaxFinishedGames is how many finished games are kept for their scoresheets and reviews if no limit is configured
+	DefaultMaxFinishedGames = 1000
+	// DefaultFinishedGameTTL is how long a finished game is kept for its scoresheets and reviews if no time is configured
+	DefaultFinishedGameTTL = time.Hour
 )
 
 type Server interface {
 	Start(settings Settings) error
+	// Handler serves the websocket and HTTP endpoints of the server with the given settings without listening on them,
+	// to mount the server in another HTTP server or in an httptest.Server
+	Handler(settings Settings) http.Handler
 }
 
 type serverImpl struct {
 	wsUpgrader websocket.Upgrader
+	admin      *Administrator
+	settings   Settings
+	// clients counts the open websocket connections
+	clients atomic.Int64
 }
 
 func New() Server {
-	return &serverImpl{}
+	return newServer(Settings{})
+}
+
+func newServer(settings Settings) *serverImpl {
+	admin := NewAdministrator()
+	admin.SetLogger(settings.logger())
+	admin.SetRetention(settings.maxFinishedGames(), settings.finishedGameTTL())
+	return &serverImpl{
+		admin:    admin,
+		settings: settings,
+	}
+}
+
+func (s *serverImpl) logger() *slog.Logger {
+	return s.settings.logger()
 }
 
 type Settings struct {
 	Endpoint string
+	// Logger receives the server's log records and those of its games, slog.Default() if nil.
+	// Games log their start and end at the info level and every turn at the debug level.
+	Logger *slog.Logger
+	// TurnTimeout is how long a connected client has to submit a turn before it passes, DefaultTurnTimeout if zero
+	TurnTimeout time.Duration
+	// MaxFinishedGames is how many finished games are kept for their scoresheets and reviews,
+	// the earliest to end being forgotten first, DefaultMaxFinishedGames if zero
+	MaxFinishedGames int
+	// FinishedGameTTL is how long a finished game is kept for its scoresheets and reviews, DefaultFinishedGameTTL if zero
+	FinishedGameTTL time.Duration
+}
+
+func (s Settings) logger() *slog.Logger {
+	if s.Logger == nil {
+		return slog.Default()
+	}
+	return s.Logger
+}
+
+func (s Settings) maxFinishedGames() int {
+	if s.MaxFinishedGames <= 0 {
+		return DefaultMaxFinishedGames
+	}
+	return s.MaxFinishedGames
+}
+
+func (s Settings) finishedGameTTL() time.Duration {
+	if s.FinishedGameTTL <= 0 {
+		return DefaultFinishedGameTTL
+	}
+	return s.FinishedGameTTL
+}
+
+func (s Settings) turnTimeout() time.Duration {
+	if s.TurnTimeout <= 0 {
+		return DefaultTurnTimeout
+	}
+	return s.TurnTimeout
 }
 
 func (s *serverImpl) Start(settings Settings) error {
-	fmt.Println("server starting")
-	http.HandleFunc("/ws", s.serveWs)
-	return http.ListenAndServe(settings.Endpoint, nil)
+	handler := s.Handler(settings)
+	s.logger().Info("server starting", "endpoint", settings.Endpoint)
+	return http.ListenAndServe(settings.Endpoint, handler)
+}
+
+func (s *serverImpl) Handler(settings Settings) http.Handler {
+	s.settings = settings
+	s.admin.SetLogger(settings.logger())
+	s.admin.SetRetention(settings.maxFinishedGames(), settings.finishedGameTTL())
+	return s.routes()
+}
+
+func (s *serverImpl) routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ws", s.serveWs)
+	mux.HandleFunc("GET /games/{gameID}/scoresheet", s.serveScoresheet)
+	mux.HandleFunc("GET /games/{gameID}/players/{playerID}/scoresheet", s.serveScoresheet)
+	mux.HandleFunc("GET /games/{gameID}/review", s.serveReview)
+	mux.HandleFunc("GET /stats", s.serveStats)
+	return mux
+}
+
+// finishedGame looks up the result of the game of the request, writing why there is none if there is not
+func (s *serverImpl) finishedGame(w http.ResponseWriter, r *http.Request) (*game.GameResult, bool) {
+	gameID := GameID(r.PathValue("gameID"))
+	result, ok := s.admin.GameResult(gameID)
+	if !ok {
+		http.Error(w, fmt.Sprintf("no game %v", gameID), http.StatusNotFound)
+		return nil, false
+	}
+	if result == nil {
+		http.Error(w, fmt.Sprintf("game %v is not over yet", gameID), http.StatusConflict)
+		return nil, false
+	}
+	return result, true
+}
+
+// serveReview reviews the decisions of a finished game as JSON, listing every mistake or, with ?top=n, the n biggest blunders
+func (s *serverImpl) serveReview(w http.ResponseWriter, r *http.Request) {
+	top := -1
+	if text := r.URL.Query().Get("top"); text != "" {
+		var err error
+		if top, err = strconv.Atoi(text); err != nil || top < 0 {
+			http.Error(w, fmt.Sprintf("top must be a number of mistakes, got %q", text), http.StatusBadRequest)
+			return
+		}
+	}
+	result, ok := s.finishedGame(w, r)
+	if !ok {
+		return
+	}
+	report, err := review.Game(*result)
+	if err != nil {
+		s.logger().Error("reviewing game", "game_id", r.PathValue("gameID"), "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(report.Top(top)); err != nil {
+		s.logger().Warn("writing review", "game_id", r.PathValue("gameID"), "error", err)
+	}
+}
+
+// serveScoresheet draws the final boards of a finished game, or of one of its players, as SVG or, with ?format=png, as PNG
+func (s *serverImpl) serveScoresheet(w http.ResponseWriter, r *http.Request) {
+	gameID := GameID(r.PathValue("gameID"))
+	result, ok := s.finishedGame(w, r)
+	if !ok {
+		return
+	}
+	sheets, err := scoresheet.SheetsOf(*result)
+	if err != nil {
+		s.logger().Error("drawing scoresheet", "game_id", gameID, "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if playerID := player.PlayerID(r.PathValue("playerID")); playerID != "" {
+		idx := slices.IndexFunc(result.Players, func(playerResult game.PlayerResult) bool {
+			return playerResult.ID == playerID
+		})
+		if idx < 0 {
+			http.Error(w, fmt.Sprintf("no player %v in game %v", playerID, gameID), http.StatusNotFound)
+			return
+		}
+		sheets = sheets[idx : idx+1]
+	}
+
+	switch format := r.URL.Query().Get("format"); format {
+	case "", "svg":
+		w.Header().Set("Content-Type", "image/svg+xml")
+		err = scoresheet.SVG(w, sheets...)
+	case "png":
+		w.Header().Set("Content-Type", "image/png")
+		err = scoresheet.PNG(w, sheets...)
+	default:
+		http.Error(w, fmt.Sprintf("unknown format %q, expected svg or png", format), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		s.logger().Warn("writing scoresheet", "game_id", gameID, "error", err)
+	}
 }
 
 func (s *serverImpl) serveWs(w http.ResponseWriter, r *http.Request) {
 	conn, err := s.wsUpgrader.Upgrade(w, r, nil)
 	if err != nil {
-		log.Println(err)
+		s.logger().Warn("upgrading websocket connection", "error", err)
 		return
 	}
-	client := &Client{conn: conn}
-	go client.handleWSConnection()
+	client := newClient(s, conn)
+	s.clients.Add(1)
+	go func() {
+		defer s.clients.Add(-1)
+		client.handleWSConnection()
+	}()
+}
+
+// broadcast sends a message to every connected client in the given lobby or game
+func (s *serverImpl) broadcast(gameID GameID, messageType protocol.MessageType, payload any) {
+	for _, member := range s.admin.Members(gameID) {
+		if client, ok := member.(*Client); ok {
+			client.send(messageType, payload)
+		}
+	}
+}
+
+// broadcastLobbyState tells everyone in the given lobby who is in it
+func (s *serverImpl) broadcastLobbyState(gameID GameID) {
+	players := s.admin.LobbyPlayers(gameID)
+	state := protocol.LobbyState{
+		Code:    string(gameID),
+		Players: make([]string, 0, len(players)),
+		Variant: s.admin.LobbyVariant(gameID),
+		Config:  s.admin.LobbyConfig(gameID),
+	}
+	// the lobby may have just been started, its ruleset then moving on with the game
+	if rules := s.admin.LobbyRuleset(gameID); rules != nil {
+		state.Ruleset = rules.Name()
+	}
+	for idx, pl := range players {
+		if idx == 0 {
+			state.Host = pl.GetName()
+		}
+		state.Players = append(state.Players, pl.GetName())
+	}
+	s.broadcast(gameID, protocol.MessageLobbyState, state)
 }
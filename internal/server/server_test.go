@@ -0,0 +1,458 @@
+package server
+
+import (
+	"encoding/json"
+	"image/png"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"qwixx/internal/analysis"
+	"qwixx/internal/game"
+	"qwixx/internal/game/actions"
+	"qwixx/internal/game/board"
+	"qwixx/internal/game/ruleset"
+	"qwixx/internal/logging"
+	"qwixx/internal/protocol"
+	"qwixx/internal/review"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestServer(t *testing.T) (*serverImpl, string) {
+	t.Helper()
+	s := newServer(Settings{TurnTimeout: 5 * time.Second, Logger: logging.Discard()})
+	httpServer := httptest.NewServer(s.routes())
+	t.Cleanup(httpServer.Close)
+	return s, "ws" + strings.TrimPrefix(httpServer.URL, "http") + "/ws"
+}
+
+func dial(t *testing.T, url string) *websocket.Conn {
+	t.Helper()
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn
+}
+
+func send(t *testing.T, conn *websocket.Conn, messageType protocol.MessageType, payload any) {
+	t.Helper()
+	message, err := protocol.NewMessage(messageType, payload)
+	require.NoError(t, err)
+	require.NoError(t, conn.WriteJSON(message))
+}
+
+// readUntil reads messages from the connection, skipping any not of the given type, decoding the payload of the first that is
+func readUntil(t *testing.T, conn *websocket.Conn, messageType protocol.MessageType, payload any) {
+	t.Helper()
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	for {
+		var message protocol.Message
+		require.NoError(t, conn.ReadJSON(&message))
+		if message.Type != messageType {
+			continue
+		}
+		if payload != nil {
+			require.NoError(t, message.Decode(payload))
+		}
+		return
+	}
+}
+
+func TestServer_Lobby(t *testing.T) {
+	_, url := newTestServer(t)
+	alice := dial(t, url)
+	bob := dial(t, url)
+
+	send(t, alice, protocol.MessageCreateLobby, protocol.CreateLobby{Name: "alice"})
+	var lobby protocol.LobbyState
+	readUntil(t, alice, protocol.MessageLobbyState, &lobby)
+	require.Equal(t, "alice", lobby.Host)
+	require.Equal(t, []string{"alice"}, lobby.Players)
+
+	send(t, bob, protocol.MessageJoinLobby, protocol.JoinLobby{Code: "nonsense", Name: "bob"})
+	var joinError protocol.Error
+	readUntil(t, bob, protocol.MessageError, &joinError)
+	require.Contains(t, joinError.Message, "no lobby")
+
+	send(t, bob, protocol.MessageJoinLobby, protocol.JoinLobby{Code: lobby.Code, Name: "bob"})
+	readUntil(t, alice, protocol.MessageLobbyState, &lobby)
+	require.Equal(t, []string{"alice", "bob"}, lobby.Players)
+	readUntil(t, bob, protocol.MessageLobbyState, &lobby)
+	require.Equal(t, []string{"alice", "bob"}, lobby.Players)
+
+	send(t, bob, protocol.MessageChat, protocol.Chat{Text: "hi alice"})
+	var chat protocol.Chat
+	readUntil(t, alice, protocol.MessageChat, &chat)
+	require.Equal(t, protocol.Chat{From: "bob", Text: "hi alice"}, chat)
+
+	send(t, alice, protocol.MessageCreateLobby, protocol.CreateLobby{Name: "alice"})
+	var createError protocol.Error
+	readUntil(t, alice, protocol.MessageError, &createError)
+	require.Contains(t, createError.Message, "already in lobby")
+}
+
+func TestServer_OnlyHostRunsLobby(t *testing.T) {
+	_, url := newTestServer(t)
+	alice := dial(t, url)
+	bob := dial(t, url)
+
+	send(t, alice, protocol.MessageCreateLobby, protocol.CreateLobby{Name: "alice"})
+	var lobby protocol.LobbyState
+	readUntil(t, alice, protocol.MessageLobbyState, &lobby)
+	send(t, bob, protocol.MessageJoinLobby, protocol.JoinLobby{Code: lobby.Code, Name: "bob"})
+	readUntil(t, bob, protocol.MessageLobbyState, &lobby)
+
+	type testCase struct {
+		name        string
+		messageType protocol.MessageType
+		payload     any
+		expected    string
+	}
+	testCases := []testCase{
+		{name: "add a bot", messageType: protocol.MessageAddBot, payload: protocol.AddBot{Name: "bot"}, expected: "only the host alice can add bots"},
+		{name: "start the game", messageType: protocol.MessageStartGame, expected: "only the host alice can start the game"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			send(t, bob, tc.messageType, tc.payload)
+			var refusal protocol.Error
+			readUntil(t, bob, protocol.MessageError, &refusal)
+			require.Equal(t, tc.expected, refusal.Message)
+		})
+	}
+
+	// the lobby is unchanged, and the host can still start the game
+	send(t, alice, protocol.MessageStartGame, nil)
+	var started protocol.GameStarted
+	readUntil(t, bob, protocol.MessageGameStarted, &started)
+	require.Len(t, started.Players, 2)
+}
+
+func TestServer_MessageTooLarge(t *testing.T) {
+	_, url := newTestServer(t)
+	alice := dial(t, url)
+
+	send(t, alice, protocol.MessageChat, protocol.Chat{Text: strings.Repeat("a", maxMessageBytes)})
+	require.NoError(t, alice.SetReadDeadline(time.Now().Add(5*time.Second)))
+	var message protocol.Message
+	err := alice.ReadJSON(&message)
+	require.True(t, websocket.IsCloseError(err, websocket.CloseMessageTooBig), "expected the connection to be closed, got %v", err)
+}
+
+func TestServer_LobbyConfig(t *testing.T) {
+	_, url := newTestServer(t)
+	alice := dial(t, url)
+
+	send(t, alice, protocol.MessageCreateLobby, protocol.CreateLobby{Name: "alice", Config: game.GameConfig{PenaltyValue: -1}})
+	var createError protocol.Error
+	readUntil(t, alice, protocol.MessageError, &createError)
+	require.Contains(t, createError.Message, "invalid house rules: penalty value must not be negative")
+
+	send(t, alice, protocol.MessageCreateLobby, protocol.CreateLobby{Name: "alice", Config: game.GameConfig{LocksToEnd: 5}})
+	readUntil(t, alice, protocol.MessageError, &createError)
+	require.Contains(t, createError.Message, "invalid house rules: locks to end must be between 0 and 4 on the classic sheet, got 5")
+
+	send(t, alice, protocol.MessageCreateLobby, protocol.CreateLobby{Name: "alice", Ruleset: "nonsense"})
+	readUntil(t, alice, protocol.MessageError, &createError)
+	require.Contains(t, createError.Message, `unknown ruleset "nonsense"`)
+
+	config := game.GameConfig{LocksToEnd: 3, AnyDiceOrder: true}
+	send(t, alice, protocol.MessageCreateLobby, protocol.CreateLobby{Name: "alice", Ruleset: ruleset.NameDouble, Config: config})
+	var lobby protocol.LobbyState
+	readUntil(t, alice, protocol.MessageLobbyState, &lobby)
+	require.Equal(t, config.WithDefaults(), lobby.Config)
+	require.Equal(t, ruleset.NameDouble, lobby.Ruleset)
+
+	send(t, alice, protocol.MessageAddBot, protocol.AddBot{Name: "bot"})
+	readUntil(t, alice, protocol.MessageLobbyState, nil)
+	send(t, alice, protocol.MessageStartGame, nil)
+	var started protocol.GameStarted
+	readUntil(t, alice, protocol.MessageGameStarted, &started)
+	require.Equal(t, config.WithDefaults(), started.Config)
+	require.Equal(t, ruleset.NameDouble, started.Ruleset)
+}
+
+func TestServer_PlayGameAgainstBot(t *testing.T) {
+	_, url := newTestServer(t)
+	alice := dial(t, url)
+
+	send(t, alice, protocol.MessageCreateLobby, protocol.CreateLobby{Name: "alice"})
+	readUntil(t, alice, protocol.MessageLobbyState, nil)
+	send(t, alice, protocol.MessageStartGame, nil)
+	var startError protocol.Error
+	readUntil(t, alice, protocol.MessageError, &startError)
+	require.Contains(t, startError.Message, "at least two players")
+
+	send(t, alice, protocol.MessageAddBot, protocol.AddBot{Name: "bot"})
+	readUntil(t, alice, protocol.MessageLobbyState, nil)
+	send(t, alice, protocol.MessageStartGame, nil)
+
+	var started protocol.GameStarted
+	readUntil(t, alice, protocol.MessageGameStarted, &started)
+	require.Len(t, started.Players, 2)
+	var playOrder protocol.PlayOrder
+	readUntil(t, alice, protocol.MessagePlayOrder, &playOrder)
+	require.ElementsMatch(t, []string{"alice", "bot"}, playOrder.Names)
+
+	// pass on every prompt, which ends the game once alice has taken four penalties
+	require.NoError(t, alice.SetReadDeadline(time.Now().Add(10*time.Second)))
+	for {
+		var message protocol.Message
+		require.NoError(t, alice.ReadJSON(&message))
+		switch message.Type {
+		case protocol.MessagePromptActive, protocol.MessagePromptInactive:
+			var prompt protocol.Prompt
+			require.NoError(t, message.Decode(&prompt))
+			send(t, alice, protocol.MessageSubmitTurn, protocol.SubmitTurn{PromptID: prompt.PromptID})
+		case protocol.MessageGameOver:
+			var gameOver protocol.GameOver
+			require.NoError(t, message.Decode(&gameOver))
+			return
+		}
+	}
+}
+
+func TestServer_Scoresheet(t *testing.T) {
+	_, url := newTestServer(t)
+	baseURL := "http" + strings.TrimSuffix(strings.TrimPrefix(url, "ws"), "/ws")
+	alice := dial(t, url)
+
+	send(t, alice, protocol.MessageCreateLobby, protocol.CreateLobby{Name: "alice"})
+	var lobby protocol.LobbyState
+	readUntil(t, alice, protocol.MessageLobbyState, &lobby)
+	send(t, alice, protocol.MessageAddBot, protocol.AddBot{Name: "bot"})
+	readUntil(t, alice, protocol.MessageLobbyState, nil)
+	send(t, alice, protocol.MessageStartGame, nil)
+	var started protocol.GameStarted
+	readUntil(t, alice, protocol.MessageGameStarted, &started)
+
+	get := func(path string) *http.Response {
+		response, err := http.Get(baseURL + path)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = response.Body.Close() })
+		return response
+	}
+	require.Equal(t, http.StatusNotFound, get("/games/nonsense/scoresheet").StatusCode)
+
+	// pass on every prompt until alice has taken four penalties
+	require.NoError(t, alice.SetReadDeadline(time.Now().Add(10*time.Second)))
+	for over := false; !over; {
+		var message protocol.Message
+		require.NoError(t, alice.ReadJSON(&message))
+		switch message.Type {
+		case protocol.MessagePromptActive, protocol.MessagePromptInactive:
+			var prompt protocol.Prompt
+			require.NoError(t, message.Decode(&prompt))
+			send(t, alice, protocol.MessageSubmitTurn, protocol.SubmitTurn{PromptID: prompt.PromptID})
+		case protocol.MessageGameOver:
+			over = true
+		}
+	}
+
+	// the result is kept right after the players are told the game is over
+	gamePath := "/games/" + started.GameID + "/scoresheet"
+	require.Eventually(t, func() bool {
+		return get(gamePath).StatusCode == http.StatusOK
+	}, time.Second, 10*time.Millisecond)
+
+	response := get(gamePath)
+	require.Equal(t, "image/svg+xml", response.Header.Get("Content-Type"))
+	body, err := io.ReadAll(response.Body)
+	require.NoError(t, err)
+	require.Contains(t, string(body), ">alice</text>")
+	require.Contains(t, string(body), ">bot</text>")
+
+	response = get("/games/" + started.GameID + "/players/" + string(started.You) + "/scoresheet?format=png")
+	require.Equal(t, http.StatusOK, response.StatusCode)
+	require.Equal(t, "image/png", response.Header.Get("Content-Type"))
+	_, err = png.Decode(response.Body)
+	require.NoError(t, err)
+
+	require.Equal(t, http.StatusNotFound, get("/games/"+started.GameID+"/players/nobody/scoresheet").StatusCode)
+	require.Equal(t, http.StatusBadRequest, get(gamePath+"?format=gif").StatusCode)
+}
+
+func TestServer_Hint(t *testing.T) {
+	type testCase struct {
+		name          string
+		inputConfig   game.GameConfig
+		expectedError string
+	}
+	testCases := []testCase{
+		{
+			name:        "hints allowed",
+			inputConfig: game.GameConfig{Hints: true},
+		},
+		{
+			name:          "hints not allowed",
+			inputConfig:   game.GameConfig{},
+			expectedError: "hints are not allowed in this game",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, url := newTestServer(t)
+			alice := dial(t, url)
+
+			send(t, alice, protocol.MessageCreateLobby, protocol.CreateLobby{Name: "alice", Config: tc.inputConfig})
+			readUntil(t, alice, protocol.MessageLobbyState, nil)
+			send(t, alice, protocol.MessageAddBot, protocol.AddBot{Name: "bot"})
+			readUntil(t, alice, protocol.MessageLobbyState, nil)
+			send(t, alice, protocol.MessageStartGame, nil)
+
+			// alice is prompted as the active or an inactive player, depending on the play order
+			var prompt protocol.Prompt
+			var promptType protocol.MessageType
+			require.NoError(t, alice.SetReadDeadline(time.Now().Add(5*time.Second)))
+			for promptType == "" {
+				var message protocol.Message
+				require.NoError(t, alice.ReadJSON(&message))
+				if message.Type == protocol.MessagePromptActive || message.Type == protocol.MessagePromptInactive {
+					promptType = message.Type
+					require.NoError(t, message.Decode(&prompt))
+				}
+			}
+			if tc.expectedError == "" {
+				send(t, alice, protocol.MessageRequestHint, protocol.RequestHint{PromptID: prompt.PromptID + 1})
+				var promptError protocol.Error
+				readUntil(t, alice, protocol.MessageError, &promptError)
+				require.Contains(t, promptError.Message, "is not waiting for a turn")
+			}
+
+			send(t, alice, protocol.MessageRequestHint, protocol.RequestHint{PromptID: prompt.PromptID})
+			if tc.expectedError != "" {
+				var hintError protocol.Error
+				readUntil(t, alice, protocol.MessageError, &hintError)
+				require.Equal(t, tc.expectedError, hintError.Message)
+				return
+			}
+			var hint protocol.Hint
+			readUntil(t, alice, protocol.MessageHint, &hint)
+			require.Equal(t, prompt.PromptID, hint.PromptID)
+			require.NotEmpty(t, hint.Explanation)
+			if promptType == protocol.MessagePromptInactive {
+				require.Nil(t, hint.ColorDiceMove)
+			}
+		})
+	}
+}
+
+// TestClient_HintPenaltyValue checks hints weigh a penalty at the default penalty value when the lobby's config leaves it out
+func TestClient_HintPenaltyValue(t *testing.T) {
+	// every move skips cells, which is only worth it when a penalty costs something
+	playerBoard, _, err := board.Parse("R:2 Y:2 G:12 B:12")
+	require.NoError(t, err)
+	diceRoll := actions.DiceRoll{
+		WhiteDiceRoll: actions.WhiteDiceRoll{White1: 1, White2: 1},
+		ColorDiceRoll: actions.ColorDiceRoll{Red: 6, Yellow: 6, Green: 1, Blue: 1},
+	}
+	expected := analysis.ActiveHint(playerBoard, diceRoll, game.DefaultGameConfig().PenaltyValue)
+	require.NotEqual(t, analysis.ActiveHint(playerBoard, diceRoll, 0).Turn, expected.Turn)
+
+	// the client's connection is gone, so what it is sent is dropped
+	c := &Client{disconnected: true}
+	c.gameStarted("game", "alice", nil, board.VariantClassic, ruleset.Classic(), game.GameConfig{Hints: true})
+	c.promptID = 1
+	c.prompted = pendingPrompt{messageType: protocol.MessagePromptActive, board: playerBoard, diceRoll: diceRoll}
+
+	hint, err := c.hint(1)
+	require.NoError(t, err)
+	require.Equal(t, expected.Explanation, hint.Explanation)
+	require.Equal(t, expected.Turn.ColorDiceMove, hint.ColorDiceMove)
+}
+
+func TestServer_Review(t *testing.T) {
+	_, url := newTestServer(t)
+	baseURL := "http" + strings.TrimSuffix(strings.TrimPrefix(url, "ws"), "/ws")
+	alice := dial(t, url)
+
+	send(t, alice, protocol.MessageCreateLobby, protocol.CreateLobby{Name: "alice"})
+	readUntil(t, alice, protocol.MessageLobbyState, nil)
+	send(t, alice, protocol.MessageAddBot, protocol.AddBot{Name: "bot"})
+	readUntil(t, alice, protocol.MessageLobbyState, nil)
+	send(t, alice, protocol.MessageStartGame, nil)
+	var started protocol.GameStarted
+	readUntil(t, alice, protocol.MessageGameStarted, &started)
+
+	get := func(path string) *http.Response {
+		response, err := http.Get(baseURL + path)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = response.Body.Close() })
+		return response
+	}
+	reviewPath := "/games/" + started.GameID + "/review"
+	require.Equal(t, http.StatusConflict, get(reviewPath).StatusCode)
+
+	// pass on every prompt until alice has taken four penalties
+	require.NoError(t, alice.SetReadDeadline(time.Now().Add(10*time.Second)))
+	for over := false; !over; {
+		var message protocol.Message
+		require.NoError(t, alice.ReadJSON(&message))
+		switch message.Type {
+		case protocol.MessagePromptActive, protocol.MessagePromptInactive:
+			var prompt protocol.Prompt
+			require.NoError(t, message.Decode(&prompt))
+			send(t, alice, protocol.MessageSubmitTurn, protocol.SubmitTurn{PromptID: prompt.PromptID})
+		case protocol.MessageGameOver:
+			over = true
+		}
+	}
+	require.Eventually(t, func() bool {
+		return get(reviewPath).StatusCode == http.StatusOK
+	}, time.Second, 10*time.Millisecond)
+
+	response := get(reviewPath + "?top=2")
+	require.Equal(t, "application/json", response.Header.Get("Content-Type"))
+	var report review.Report
+	require.NoError(t, json.NewDecoder(response.Body).Decode(&report))
+	require.Len(t, report.Players, 2)
+	require.LessOrEqual(t, len(report.Mistakes), 2)
+	for _, summary := range report.Players {
+		require.Greater(t, summary.Decisions, 0)
+	}
+
+	require.Equal(t, http.StatusBadRequest, get(reviewPath+"?top=many").StatusCode)
+	require.Equal(t, http.StatusNotFound, get("/games/nonsense/review").StatusCode)
+}
+
+func TestServer_Stats(t *testing.T) {
+	_, url := newTestServer(t)
+	baseURL := "http" + strings.TrimSuffix(strings.TrimPrefix(url, "ws"), "/ws")
+	alice := dial(t, url)
+	bob := dial(t, url)
+
+	send(t, alice, protocol.MessageCreateLobby, protocol.CreateLobby{Name: "alice"})
+	readUntil(t, alice, protocol.MessageLobbyState, nil)
+	send(t, bob, protocol.MessageCreateLobby, protocol.CreateLobby{Name: "bob"})
+	readUntil(t, bob, protocol.MessageLobbyState, nil)
+	send(t, bob, protocol.MessageAddBot, protocol.AddBot{Name: "bot"})
+	readUntil(t, bob, protocol.MessageLobbyState, nil)
+	send(t, bob, protocol.MessageStartGame, nil)
+	readUntil(t, bob, protocol.MessageGameStarted, nil)
+
+	getStats := func() Stats {
+		response, err := http.Get(baseURL + "/stats")
+		require.NoError(t, err)
+		defer response.Body.Close()
+		require.Equal(t, "application/json", response.Header.Get("Content-Type"))
+		var stats Stats
+		require.NoError(t, json.NewDecoder(response.Body).Decode(&stats))
+		return stats
+	}
+	stats := getStats()
+	require.Equal(t, int64(2), stats.Clients)
+	require.Equal(t, 1, stats.Lobbies)
+	require.Equal(t, 1, stats.GamesPlaying+stats.GamesFinished)
+	require.Positive(t, stats.Goroutines)
+	require.Positive(t, stats.HeapBytes)
+	require.Positive(t, stats.SysBytes)
+
+	require.NoError(t, alice.Close())
+	require.Eventually(t, func() bool {
+		return getStats().Clients == 1
+	}, time.Second, 10*time.Millisecond)
+}
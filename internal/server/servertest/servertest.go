@@ -0,0 +1,53 @@
+// Package servertest runs a game server in-process for the tests of the packages speaking its websocket protocol
+package servertest
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"qwixx/internal/logging"
+	"qwixx/internal/server"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Server is a game server listening on a local port for the duration of a test, whose websocket connections can be dropped
+type Server struct {
+	// URL is the websocket url of the server, such as ws://127.0.0.1:12345/ws
+	URL string
+
+	mu    sync.Mutex
+	conns []net.Conn
+}
+
+// New starts a server that gives clients five seconds to submit a turn and logs nothing, closing it once the test is over
+func New(t testing.TB) *Server {
+	t.Helper()
+	s := &Server{}
+	handler := server.New().Handler(server.Settings{TurnTimeout: 5 * time.Second, Logger: logging.Discard()})
+	httpServer := httptest.NewUnstartedServer(handler)
+	// websocket connections are hijacked from the http server, which then no longer closes them itself
+	httpServer.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+		if state == http.StateHijacked {
+			s.mu.Lock()
+			s.conns = append(s.conns, conn)
+			s.mu.Unlock()
+		}
+	}
+	httpServer.Start()
+	t.Cleanup(httpServer.Close)
+	s.URL = "ws" + strings.TrimPrefix(httpServer.URL, "http") + "/ws"
+	return s
+}
+
+// DropConnections closes every websocket connection to the server
+func (s *Server) DropConnections() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, conn := range s.conns {
+		_ = conn.Close()
+	}
+	s.conns = nil
+}
@@ -0,0 +1,52 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"runtime"
+	"runtime/metrics"
+)
+
+// Stats is a snapshot of the load on the server and of the resources its process uses, served as JSON at /stats
+type Stats struct {
+	// Clients is the number of open websocket connections
+	Clients      int64 `json:"clients"`
+	Lobbies      int   `json:"lobbies"`
+	GamesPlaying int   `json:"games_playing"`
+	// GamesFinished is the number of finished games kept for their scoresheets and reviews, whose results take up memory
+	GamesFinished int `json:"games_finished"`
+
+	Goroutines int `json:"goroutines"`
+	// HeapBytes is the memory taken by live and not yet collected heap objects
+	HeapBytes uint64 `json:"heap_bytes"`
+	// SysBytes is the memory the process got from the operating system
+	SysBytes uint64 `json:"sys_bytes"`
+	GCCycles uint32 `json:"gc_cycles"`
+	// CPUSeconds is the CPU time the process has spent running since it started, as estimated by the Go runtime
+	CPUSeconds float64 `json:"cpu_seconds"`
+}
+
+// stats takes a snapshot of the load on the server and of the resources of its process
+func (s *serverImpl) stats() Stats {
+	stats := Stats{Clients: s.clients.Load(), Goroutines: runtime.NumGoroutine()}
+	stats.Lobbies, stats.GamesPlaying, stats.GamesFinished = s.admin.Counts()
+
+	var memStats runtime.MemStats
+	runtime.ReadMemStats(&memStats)
+	stats.HeapBytes, stats.SysBytes, stats.GCCycles = memStats.HeapAlloc, memStats.Sys, memStats.NumGC
+
+	samples := []metrics.Sample{{Name: "/cpu/classes/total:cpu-seconds"}, {Name: "/cpu/classes/idle:cpu-seconds"}}
+	metrics.Read(samples)
+	if samples[0].Value.Kind() == metrics.KindFloat64 && samples[1].Value.Kind() == metrics.KindFloat64 {
+		stats.CPUSeconds = samples[0].Value.Float64() - samples[1].Value.Float64()
+	}
+	return stats
+}
+
+// serveStats writes the current stats of the server as JSON
+func (s *serverImpl) serveStats(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(s.stats()); err != nil {
+		s.logger().Warn("writing stats", "error", err)
+	}
+}
@@ -0,0 +1,116 @@
+package simulation
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+)
+
+// Format is a way of writing a report
+type Format string
+
+const (
+	FormatText Format = "text"
+	FormatCSV  Format = "csv"
+	FormatJSON Format = "json"
+)
+
+// Write writes the report in the given format
+func (r Report) Write(w io.Writer, format Format) error {
+	switch format {
+	case FormatText:
+		return r.WriteText(w)
+	case FormatCSV:
+		return r.WriteCSV(w)
+	case FormatJSON:
+		return r.WriteJSON(w)
+	default:
+		return fmt.Errorf("unknown format %q, expected text, csv or json", format)
+	}
+}
+
+// WriteText writes the report as tables meant to be read by people
+func (r Report) WriteText(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintf(tw, "%d games, seed %d\n\n", r.Games, r.Seed)
+
+	fmt.Fprintln(tw, "seat\tstrategy\twins\twin rate\t95% CI\tties\tmean score\t95% CI\tstd dev\tmin\tp25\tmedian\tp75\tmax\tpenalties\t")
+	for _, seat := range r.Seats {
+		fmt.Fprintf(
+			tw, "%d\t%s\t%d\t%.1f%%\t%.1f-%.1f%%\t%d\t%.1f\t%.1f-%.1f\t%.1f\t%.0f\t%.0f\t%.0f\t%.0f\t%.0f\t%.2f\t\n",
+			seat.Seat, seat.Strategy, seat.Wins.Count, 100*seat.Wins.Rate, 100*seat.Wins.CI.Low, 100*seat.Wins.CI.High, seat.Ties,
+			seat.Score.Mean, seat.Score.MeanCI.Low, seat.Score.MeanCI.High, seat.Score.StdDev,
+			seat.Score.Min, seat.Score.P25, seat.Score.Median, seat.Score.P75, seat.Score.Max, seat.Penalties.Mean,
+		)
+	}
+
+	fmt.Fprintf(
+		tw, "\ngame length: %.1f turns on average (95%% CI %.1f-%.1f), min %.0f, median %.0f, max %.0f\n\n",
+		r.Turns.Mean, r.Turns.MeanCI.Low, r.Turns.MeanCI.High, r.Turns.Min, r.Turns.Median, r.Turns.Max,
+	)
+
+	fmt.Fprintln(tw, "end reason\tgames\trate\t95% CI\t")
+	for _, reason := range r.EndReasons {
+		fmt.Fprintf(
+			tw, "%s\t%d\t%.1f%%\t%.1f-%.1f%%\t\n",
+			reason.Reason, reason.Count, 100*reason.Rate, 100*reason.CI.Low, 100*reason.CI.High,
+		)
+	}
+	return tw.Flush()
+}
+
+// WriteCSV writes every statistic of the report as a row of scope, statistic, value and confidence interval,
+// where the scope is either a seat like "seat 1 (greedy)" or "games"
+func (r Report) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	row := func(scope, statistic string, value float64, ci *Interval) {
+		record := []string{scope, statistic, formatFloat(value), "", ""}
+		if ci != nil {
+			record[3], record[4] = formatFloat(ci.Low), formatFloat(ci.High)
+		}
+		_ = cw.Write(record)
+	}
+	distributionRows := func(scope, name string, d Distribution) {
+		row(scope, name+"_mean", d.Mean, &d.MeanCI)
+		row(scope, name+"_std_dev", d.StdDev, nil)
+		row(scope, name+"_min", d.Min, nil)
+		row(scope, name+"_p10", d.P10, nil)
+		row(scope, name+"_p25", d.P25, nil)
+		row(scope, name+"_median", d.Median, nil)
+		row(scope, name+"_p75", d.P75, nil)
+		row(scope, name+"_p90", d.P90, nil)
+		row(scope, name+"_max", d.Max, nil)
+	}
+
+	_ = cw.Write([]string{"scope", "statistic", "value", "ci_low", "ci_high"})
+	row("games", "games", float64(r.Games), nil)
+	row("games", "seed", float64(r.Seed), nil)
+	for _, seat := range r.Seats {
+		scope := fmt.Sprintf("seat %d (%s)", seat.Seat, seat.Strategy)
+		row(scope, "wins", float64(seat.Wins.Count), nil)
+		row(scope, "win_rate", seat.Wins.Rate, &seat.Wins.CI)
+		row(scope, "ties", float64(seat.Ties), nil)
+		distributionRows(scope, "score", seat.Score)
+		row(scope, "penalties_mean", seat.Penalties.Mean, &seat.Penalties.MeanCI)
+	}
+	distributionRows("games", "turns", r.Turns)
+	for _, reason := range r.EndReasons {
+		row("games", "end_"+string(reason.Reason), reason.Rate, &reason.CI)
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteJSON writes the report as an indented JSON document
+func (r Report) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
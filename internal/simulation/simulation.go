@@ -0,0 +1,98 @@
+// Package simulation plays many games between computer players to measure how their strategies compare
+package simulation
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"qwixx/internal/game"
+	"qwixx/internal/game/player"
+	"runtime"
+	"sync"
+)
+
+// Config describes a batch of games to simulate
+type Config struct {
+	// Games is the number of games to play
+	Games int
+	// Workers is the number of games played at the same time, defaulting to the number of CPUs
+	Workers int
+	// Strategies are the strategies of the players in each game, one per seat
+	Strategies []string
+	// Seed makes the batch reproducible, game i is played with the seed Seed+i
+	Seed int64
+}
+
+func (c Config) validate() error {
+	if c.Games < 1 {
+		return errors.New("at least one game must be simulated")
+	}
+	if len(c.Strategies) < 2 {
+		return errors.New("a game needs at least two players")
+	}
+	for _, name := range c.Strategies {
+		if _, err := player.NewStrategy(name, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c Config) workers() int {
+	if c.Workers < 1 {
+		return runtime.NumCPU()
+	}
+	return min(c.Workers, c.Games)
+}
+
+// seatName names the player in the given seat after its position and strategy, like "1:greedy"
+func seatName(seat int, strategy string) string {
+	return fmt.Sprintf("%d:%s", seat+1, strategy)
+}
+
+// Run plays the configured games across the configured number of workers and summarizes their results.
+// The results only depend on the seed, not on the number of workers.
+func Run(config Config) (Report, error) {
+	if err := config.validate(); err != nil {
+		return Report{}, err
+	}
+	// every result is folded into the summary as its game finishes, so a long run does not keep them all
+	summary := newSummary(config)
+	var summaryMu sync.Mutex
+
+	gameIndexes := make(chan int)
+	var wg sync.WaitGroup
+	for worker := 0; worker < config.workers(); worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range gameIndexes {
+				result := PlayGame(config.Strategies, config.Seed+int64(idx))
+				summaryMu.Lock()
+				summary.add(result)
+				summaryMu.Unlock()
+			}
+		}()
+	}
+	for idx := 0; idx < config.Games; idx++ {
+		gameIndexes <- idx
+	}
+	close(gameIndexes)
+	wg.Wait()
+
+	return summary.report(), nil
+}
+
+// PlayGame plays a single silent game between players with the given strategies, seated in the given order.
+// The strategies must be valid names for player.NewStrategy.
+func PlayGame(strategies []string, seed int64) game.GameResult {
+	rng := rand.New(rand.NewSource(seed))
+	players := make([]player.Player, 0, len(strategies))
+	for seat, name := range strategies {
+		// every player gets its own source of randomness so the dice do not depend on the choices they make
+		strategy, _ := player.NewStrategy(name, rand.New(rand.NewSource(rng.Int63())))
+		players = append(players, player.NewStrategyPlayer(seatName(seat, name), strategy, nil))
+	}
+	runner := game.NewGameRunner(players, game.WithRand(rng))
+	return runner.RunGame()
+}
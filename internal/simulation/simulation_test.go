@@ -0,0 +1,166 @@
+package simulation
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"qwixx/internal/game"
+	"qwixx/internal/game/player"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRun_IsReproducibleRegardlessOfWorkers(t *testing.T) {
+	config := Config{
+		Games:      50,
+		Workers:    1,
+		Strategies: []string{"first", "random", "careful"},
+		Seed:       42,
+	}
+	sequential, err := Run(config)
+	require.NoError(t, err)
+
+	config.Workers = 8
+	parallel, err := Run(config)
+	require.NoError(t, err)
+	require.Equal(t, sequential, parallel)
+
+	require.Equal(t, 50, sequential.Games)
+	require.Len(t, sequential.Seats, 3)
+	gamesByEndReason := 0
+	for _, reason := range sequential.EndReasons {
+		gamesByEndReason += reason.Count
+	}
+	require.Equal(t, 50, gamesByEndReason)
+}
+
+func TestRun_InvalidConfig(t *testing.T) {
+	type testCase struct {
+		name          string
+		config        Config
+		expectedError string
+	}
+	testCases := []testCase{
+		{
+			name:          "no games",
+			config:        Config{Strategies: []string{"first", "first"}},
+			expectedError: "at least one game must be simulated",
+		},
+		{
+			name:          "one player",
+			config:        Config{Games: 1, Strategies: []string{"first"}},
+			expectedError: "a game needs at least two players",
+		},
+		{
+			name:          "unknown strategy",
+			config:        Config{Games: 1, Strategies: []string{"first", "cheating"}},
+			expectedError: `unknown strategy "cheating"`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Run(tc.config)
+			require.ErrorContains(t, err, tc.expectedError)
+		})
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	config := Config{Strategies: []string{"first", "greedy"}, Seed: 7}
+	results := []game.GameResult{
+		{
+			Players: []game.PlayerResult{
+				{ID: "a", Name: "2:greedy", Score: 30, Won: true},
+				{ID: "b", Name: "1:first", Score: 10, Penalties: 4},
+			},
+			Winners:   []player.PlayerID{"a"},
+			Turns:     20,
+			EndReason: game.EndReasonPenalties,
+		},
+		{
+			Players: []game.PlayerResult{
+				{ID: "c", Name: "1:first", Score: 20, Won: true},
+				{ID: "d", Name: "2:greedy", Score: 20, Won: true},
+			},
+			Winners:   []player.PlayerID{"c", "d"},
+			Turns:     30,
+			EndReason: game.EndReasonRowsLocked,
+		},
+	}
+
+	report := Summarize(config, results)
+	require.Equal(t, 2, report.Games)
+	require.Equal(t, int64(7), report.Seed)
+
+	first := report.Seats[0]
+	require.Equal(t, "first", first.Strategy)
+	require.Equal(t, 1, first.Wins.Count)
+	require.Equal(t, 0.5, first.Wins.Rate)
+	require.Equal(t, 1, first.Ties)
+	require.Equal(t, 15.0, first.Score.Mean)
+	require.Equal(t, 2.0, first.Penalties.Mean)
+
+	greedy := report.Seats[1]
+	require.Equal(t, 2, greedy.Wins.Count)
+	require.Equal(t, 1.0, greedy.Wins.Rate)
+	require.Equal(t, 25.0, greedy.Score.Median)
+
+	require.Equal(t, 25.0, report.Turns.Mean)
+	require.Equal(t, []EndReasonStats{
+		{Reason: game.EndReasonRowsLocked, Proportion: proportion(1, 2)},
+		{Reason: game.EndReasonPenalties, Proportion: proportion(1, 2)},
+		{Reason: game.EndReasonTurnLimit, Proportion: proportion(0, 2)},
+	}, report.EndReasons)
+}
+
+func TestWilsonInterval(t *testing.T) {
+	interval := wilsonInterval(50, 100)
+	require.InDelta(t, 0.4038, interval.Low, 0.0001)
+	require.InDelta(t, 0.5962, interval.High, 0.0001)
+
+	interval = wilsonInterval(0, 10)
+	require.Equal(t, 0.0, interval.Low)
+	require.InDelta(t, 0.2775, interval.High, 0.0001)
+}
+
+func TestDistribution(t *testing.T) {
+	d := distribution([]float64{5, 1, 4, 2, 3})
+	require.Equal(t, 3.0, d.Mean)
+	require.InDelta(t, 1.5811, d.StdDev, 0.0001)
+	require.Equal(t, 1.0, d.Min)
+	require.InDelta(t, 1.4, d.P10, 0.0001)
+	require.Equal(t, 2.0, d.P25)
+	require.Equal(t, 3.0, d.Median)
+	require.Equal(t, 4.0, d.P75)
+	require.Equal(t, 5.0, d.Max)
+	require.InDelta(t, 3-1.96*1.5811/2.2361, d.MeanCI.Low, 0.001)
+
+	require.Equal(t, Distribution{}, distribution(nil))
+}
+
+func TestReport_Write(t *testing.T) {
+	report, err := Run(Config{Games: 10, Strategies: []string{"first", "greedy"}, Seed: 1})
+	require.NoError(t, err)
+
+	var text bytes.Buffer
+	require.NoError(t, report.Write(&text, FormatText))
+	require.Contains(t, text.String(), "10 games, seed 1")
+	require.Contains(t, text.String(), "greedy")
+	require.Contains(t, text.String(), "rows_locked")
+
+	var csvOutput bytes.Buffer
+	require.NoError(t, report.Write(&csvOutput, FormatCSV))
+	records, err := csv.NewReader(&csvOutput).ReadAll()
+	require.NoError(t, err)
+	require.Equal(t, []string{"scope", "statistic", "value", "ci_low", "ci_high"}, records[0])
+	require.Contains(t, records, []string{"games", "games", "10", "", ""})
+
+	var jsonOutput bytes.Buffer
+	require.NoError(t, report.Write(&jsonOutput, FormatJSON))
+	var decoded Report
+	require.NoError(t, json.Unmarshal(jsonOutput.Bytes(), &decoded))
+	require.Equal(t, report, decoded)
+
+	require.Error(t, report.Write(&text, "xml"))
+}
@@ -0,0 +1,215 @@
+package simulation
+
+import (
+	"math"
+	"qwixx/internal/game"
+	"slices"
+)
+
+// z95 is the z-score of a two sided 95% confidence interval
+const z95 = 1.959964
+
+// Interval is a 95% confidence interval
+type Interval struct {
+	Low  float64 `json:"low"`
+	High float64 `json:"high"`
+}
+
+// Distribution summarizes a sample of numbers
+type Distribution struct {
+	Mean float64 `json:"mean"`
+	// MeanCI is the normal approximation confidence interval of the mean
+	MeanCI Interval `json:"mean_ci"`
+	StdDev float64  `json:"std_dev"`
+	Min    float64  `json:"min"`
+	P10    float64  `json:"p10"`
+	P25    float64  `json:"p25"`
+	Median float64  `json:"median"`
+	P75    float64  `json:"p75"`
+	P90    float64  `json:"p90"`
+	Max    float64  `json:"max"`
+}
+
+// Proportion is how often something happened, with the Wilson score confidence interval of its rate
+type Proportion struct {
+	Count int      `json:"count"`
+	Rate  float64  `json:"rate"`
+	CI    Interval `json:"ci"`
+}
+
+// SeatStats summarizes how the player in one seat did
+type SeatStats struct {
+	// Seat is the 1-based position the player was given in, the play order of each game is shuffled from it
+	Seat     int    `json:"seat"`
+	Strategy string `json:"strategy"`
+	// Wins counts the games this player had the highest score in, including ties
+	Wins Proportion `json:"wins"`
+	// Ties counts the wins that were shared with another player
+	Ties      int          `json:"ties"`
+	Score     Distribution `json:"score"`
+	Penalties Distribution `json:"penalties"`
+}
+
+// EndReasonStats is how often games ended for one reason
+type EndReasonStats struct {
+	Reason game.EndReason `json:"reason"`
+	Proportion
+}
+
+// Report summarizes a batch of simulated games
+type Report struct {
+	Games      int              `json:"games"`
+	Seed       int64            `json:"seed"`
+	Seats      []SeatStats      `json:"seats"`
+	Turns      Distribution     `json:"turns"`
+	EndReasons []EndReasonStats `json:"end_reasons"`
+}
+
+// Summarize computes the statistics of the given results of games played with the given config
+func Summarize(config Config, results []game.GameResult) Report {
+	summary := newSummary(config)
+	for _, result := range results {
+		summary.add(result)
+	}
+	return summary.report()
+}
+
+// summary folds the results of games played with a config into what their statistics are computed from,
+// so the results themselves need not be kept
+type summary struct {
+	config      Config
+	seatsByName map[string]int
+	games       int
+	wins        []int
+	ties        []int
+	scores      [][]float64
+	penalties   [][]float64
+	turns       []float64
+	endReasons  map[game.EndReason]int
+}
+
+func newSummary(config Config) *summary {
+	seatsByName := make(map[string]int, len(config.Strategies))
+	for seat, name := range config.Strategies {
+		seatsByName[seatName(seat, name)] = seat
+	}
+	return &summary{
+		config:      config,
+		seatsByName: seatsByName,
+		wins:        make([]int, len(config.Strategies)),
+		ties:        make([]int, len(config.Strategies)),
+		scores:      make([][]float64, len(config.Strategies)),
+		penalties:   make([][]float64, len(config.Strategies)),
+		endReasons:  make(map[game.EndReason]int),
+	}
+}
+
+// add folds the result of one more game into the summary
+func (s *summary) add(result game.GameResult) {
+	s.games++
+	s.turns = append(s.turns, float64(result.Turns))
+	s.endReasons[result.EndReason]++
+	for _, playerResult := range result.Players {
+		seat := s.seatsByName[playerResult.Name]
+		s.scores[seat] = append(s.scores[seat], float64(playerResult.Score))
+		s.penalties[seat] = append(s.penalties[seat], float64(playerResult.Penalties))
+		if playerResult.Won {
+			s.wins[seat]++
+			if len(result.Winners) > 1 {
+				s.ties[seat]++
+			}
+		}
+	}
+}
+
+// report computes the statistics of the games added so far, which do not depend on the order they were added in
+func (s *summary) report() Report {
+	report := Report{
+		Games: s.games,
+		Seed:  s.config.Seed,
+		Turns: distribution(s.turns),
+	}
+	for seat, name := range s.config.Strategies {
+		report.Seats = append(report.Seats, SeatStats{
+			Seat:      seat + 1,
+			Strategy:  name,
+			Wins:      proportion(s.wins[seat], s.games),
+			Ties:      s.ties[seat],
+			Score:     distribution(s.scores[seat]),
+			Penalties: distribution(s.penalties[seat]),
+		})
+	}
+	for _, reason := range []game.EndReason{game.EndReasonRowsLocked, game.EndReasonPenalties, game.EndReasonTurnLimit} {
+		report.EndReasons = append(report.EndReasons, EndReasonStats{
+			Reason:     reason,
+			Proportion: proportion(s.endReasons[reason], s.games),
+		})
+	}
+	return report
+}
+
+func proportion(count, total int) Proportion {
+	if total == 0 {
+		return Proportion{}
+	}
+	return Proportion{
+		Count: count,
+		Rate:  float64(count) / float64(total),
+		CI:    wilsonInterval(count, total),
+	}
+}
+
+// wilsonInterval is the Wilson score interval of a proportion, which unlike the normal approximation
+// stays within [0, 1] and behaves for rates close to 0 or 1
+func wilsonInterval(successes, trials int) Interval {
+	n := float64(trials)
+	p := float64(successes) / n
+	denominator := 1 + z95*z95/n
+	center := (p + z95*z95/(2*n)) / denominator
+	margin := z95 * math.Sqrt(p*(1-p)/n+z95*z95/(4*n*n)) / denominator
+	return Interval{Low: math.Max(0, center-margin), High: math.Min(1, center+margin)}
+}
+
+func distribution(values []float64) Distribution {
+	if len(values) == 0 {
+		return Distribution{}
+	}
+	sorted := slices.Clone(values)
+	slices.Sort(sorted)
+
+	var sum float64
+	for _, value := range sorted {
+		sum += value
+	}
+	mean := sum / float64(len(sorted))
+	var squares float64
+	for _, value := range sorted {
+		squares += (value - mean) * (value - mean)
+	}
+	var stdDev float64
+	if len(sorted) > 1 {
+		stdDev = math.Sqrt(squares / float64(len(sorted)-1))
+	}
+	margin := z95 * stdDev / math.Sqrt(float64(len(sorted)))
+
+	return Distribution{
+		Mean:   mean,
+		MeanCI: Interval{Low: mean - margin, High: mean + margin},
+		StdDev: stdDev,
+		Min:    sorted[0],
+		P10:    percentile(sorted, 0.10),
+		P25:    percentile(sorted, 0.25),
+		Median: percentile(sorted, 0.50),
+		P75:    percentile(sorted, 0.75),
+		P90:    percentile(sorted, 0.90),
+		Max:    sorted[len(sorted)-1],
+	}
+}
+
+// percentile linearly interpolates the given percentile of the sorted values
+func percentile(sorted []float64, p float64) float64 {
+	position := p * float64(len(sorted)-1)
+	lower := int(math.Floor(position))
+	upper := int(math.Ceil(position))
+	return sorted[lower] + (sorted[upper]-sorted[lower])*(position-float64(lower))
+}
@@ -0,0 +1,72 @@
+package tournament
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+// WriteText writes the standings, head-to-head matrix and significance tests as tables meant to be read by people
+func (r Results) WriteText(w io.Writer) error {
+	games := 0
+	for _, match := range r.Matches {
+		games += len(match.Games)
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintf(tw, "%s tournament, %d matches, %d games, seed %d\n\n", r.Format, len(r.Matches), games, r.Seed)
+
+	fmt.Fprintln(tw, "rank\tentrant\tmatch points\tmatches\tgame points\tgames\tmean score\trating\t")
+	for _, standing := range r.Standings {
+		entrant := standing.Entrant
+		if standing.Byes > 0 {
+			entrant += fmt.Sprintf(" (byes: %d)", standing.Byes)
+		}
+		fmt.Fprintf(
+			tw, "%d\t%s\t%.1f\t%d\t%.1f\t%d\t%.1f\t%.0f\t\n",
+			standing.Rank, entrant, standing.MatchPoints, standing.Matches,
+			standing.GamePoints, standing.Games, standing.MeanScore, standing.Rating,
+		)
+	}
+
+	fmt.Fprintln(tw, "\nhead to head, wins-losses-ties of the row against the column")
+	header := []string{""}
+	for _, entrant := range r.Entrants {
+		header = append(header, entrant.Name)
+	}
+	fmt.Fprintln(tw, strings.Join(header, "\t")+"\t")
+	for a, entrant := range r.Entrants {
+		row := []string{entrant.Name}
+		for b := range r.Entrants {
+			record := r.HeadToHead[a][b]
+			if a == b || record.Games == 0 {
+				row = append(row, "-")
+				continue
+			}
+			row = append(row, fmt.Sprintf("%d-%d-%d", record.Wins, record.Losses, record.Ties))
+		}
+		fmt.Fprintln(tw, strings.Join(row, "\t")+"\t")
+	}
+
+	fmt.Fprintf(tw, "\nsign tests, significant below p=%v\n", significanceLevel)
+	fmt.Fprintln(tw, "entrant\topponent\twins\tlosses\tties\tp-value\t\t")
+	for _, test := range r.Significance {
+		significant := ""
+		if test.Significant {
+			significant = "*"
+		}
+		fmt.Fprintf(
+			tw, "%s\t%s\t%d\t%d\t%d\t%.4f\t%s\t\n",
+			test.Entrant, test.Opponent, test.Wins, test.Losses, test.Ties, test.PValue, significant,
+		)
+	}
+	return tw.Flush()
+}
+
+// WriteJSON writes the results, including every game played, as an indented JSON document
+func (r Results) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
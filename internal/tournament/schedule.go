@@ -0,0 +1,104 @@
+package tournament
+
+import (
+	"slices"
+)
+
+// combinations lists every group of size entrants out of count, each group in ascending order
+func combinations(count, size int) [][]int {
+	var groups [][]int
+	var build func(start int, group []int)
+	build = func(start int, group []int) {
+		if len(group) == size {
+			groups = append(groups, slices.Clone(group))
+			return
+		}
+		for idx := start; idx < count; idx++ {
+			build(idx+1, append(group, idx))
+		}
+	}
+	build(0, nil)
+	return groups
+}
+
+// rotations lists the seat orders that let every entrant of the table sit in every seat once
+func rotations(table []int) [][]int {
+	orders := make([][]int, 0, len(table))
+	for shift := range table {
+		orders = append(orders, append(slices.Clone(table[shift:]), table[:shift]...))
+	}
+	return orders
+}
+
+// permutations lists every possible seat order of the table
+func permutations(table []int) [][]int {
+	if len(table) <= 1 {
+		return [][]int{slices.Clone(table)}
+	}
+	var orders [][]int
+	for idx, first := range table {
+		rest := append(slices.Clone(table[:idx]), table[idx+1:]...)
+		for _, order := range permutations(rest) {
+			orders = append(orders, append([]int{first}, order...))
+		}
+	}
+	return orders
+}
+
+// swissPairings pairs the entrants for the next round of a Swiss tournament from the matches played so far.
+// Entrants are ranked by their standings and each is paired with the best ranked entrant below them they have not played yet,
+// or the next free entrant if they have played everyone. With an odd number of entrants,
+// the lowest ranked entrant without a bye so far sits the round out, which is returned as the bye or -1 if there is none.
+func swissPairings(count int, matches []Match, byes []int) (pairings [][]int, bye int) {
+	ranking := rankEntrants(count, matches, byes)
+	played := make(map[[2]int]bool)
+	for _, match := range matches {
+		played[[2]int{match.Entrants[0], match.Entrants[1]}] = true
+		played[[2]int{match.Entrants[1], match.Entrants[0]}] = true
+	}
+
+	bye = -1
+	if count%2 == 1 {
+		bye = ranking[len(ranking)-1]
+		for idx := len(ranking) - 1; idx >= 0; idx-- {
+			if !slices.Contains(byes, ranking[idx]) {
+				bye = ranking[idx]
+				break
+			}
+		}
+		ranking = slices.DeleteFunc(ranking, func(entrant int) bool { return entrant == bye })
+	}
+
+	paired := make(map[int]bool, count)
+	for idx, entrant := range ranking {
+		if paired[entrant] {
+			continue
+		}
+		opponent := -1
+		for _, candidate := range ranking[idx+1:] {
+			if paired[candidate] {
+				continue
+			}
+			if opponent < 0 {
+				opponent = candidate
+			}
+			if !played[[2]int{entrant, candidate}] {
+				opponent = candidate
+				break
+			}
+		}
+		paired[entrant], paired[opponent] = true, true
+		pairings = append(pairings, []int{min(entrant, opponent), max(entrant, opponent)})
+	}
+	return pairings, bye
+}
+
+// rankEntrants orders the entrant indexes by their standings after the given matches, best first
+func rankEntrants(count int, matches []Match, byes []int) []int {
+	standings := computeStandings(count, matches, byes)
+	ranking := make([]int, 0, count)
+	for _, standing := range standings {
+		ranking = append(ranking, standing.entrant)
+	}
+	return ranking
+}
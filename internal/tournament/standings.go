@@ -0,0 +1,274 @@
+package tournament
+
+import (
+	"cmp"
+	"math"
+	"slices"
+)
+
+const (
+	// initialRating is the Elo rating every entrant starts the tournament with
+	initialRating = 1500
+	// ratingK is how far the rating of an entrant moves after a single two player game
+	ratingK = 16
+	// significanceLevel is the p-value below which a head-to-head difference is reported as significant
+	significanceLevel = 0.05
+)
+
+// Standing is how an entrant placed in a tournament
+type Standing struct {
+	Rank    int    `json:"rank"`
+	Entrant string `json:"entrant"`
+	// MatchPoints are 1 for every match won, split evenly between entrants tied on game points, and 1 for a bye
+	MatchPoints float64 `json:"match_points"`
+	Matches     int     `json:"matches"`
+	Byes        int     `json:"byes"`
+	// GamePoints are 1 for every game won, split evenly between players tied for the highest score
+	GamePoints float64 `json:"game_points"`
+	Games      int     `json:"games"`
+	MeanScore  float64 `json:"mean_score"`
+	// Rating is an Elo rating, updated with every pair of players of each game in the order the games were played
+	Rating float64 `json:"rating"`
+
+	entrant    int
+	totalScore int
+}
+
+// HeadToHead is how one entrant did against another in the games they played together
+type HeadToHead struct {
+	Games  int `json:"games"`
+	Wins   int `json:"wins"`
+	Losses int `json:"losses"`
+	Ties   int `json:"ties"`
+	// MeanScoreDiff is the average difference between the entrant's score and the opponent's
+	MeanScoreDiff float64 `json:"mean_score_diff"`
+
+	totalScoreDiff int
+}
+
+// PairTest is a two-sided sign test of whether one entrant beats another more often than chance would explain,
+// ignoring tied games
+type PairTest struct {
+	Entrant     string  `json:"entrant"`
+	Opponent    string  `json:"opponent"`
+	Wins        int     `json:"wins"`
+	Losses      int     `json:"losses"`
+	Ties        int     `json:"ties"`
+	PValue      float64 `json:"p_value"`
+	Significant bool    `json:"significant"`
+}
+
+// Results are the outcome of a tournament
+type Results struct {
+	Format    Format     `json:"format"`
+	Seed      int64      `json:"seed"`
+	Entrants  []Entrant  `json:"entrants"`
+	Standings []Standing `json:"standings"`
+	// HeadToHead holds the record of the entrant of each row against the entrant of each column,
+	// both in the order the entrants were given in
+	HeadToHead   [][]HeadToHead `json:"head_to_head"`
+	Significance []PairTest     `json:"significance"`
+	Matches      []Match        `json:"matches"`
+	// Byes are the names of the entrants that sat out a round of a Swiss tournament, in round order
+	Byes []string `json:"byes,omitempty"`
+}
+
+func newResults(config Config, matches []Match, byes []int) Results {
+	results := Results{
+		Format:     config.Format,
+		Seed:       config.Seed,
+		Entrants:   config.Entrants,
+		Matches:    matches,
+		HeadToHead: headToHead(len(config.Entrants), matches),
+	}
+	for _, standing := range computeStandings(len(config.Entrants), matches, byes) {
+		standing.Entrant = config.Entrants[standing.entrant].Name
+		results.Standings = append(results.Standings, standing)
+	}
+	for a := range config.Entrants {
+		for b := a + 1; b < len(config.Entrants); b++ {
+			record := results.HeadToHead[a][b]
+			if record.Games == 0 {
+				continue
+			}
+			pValue := signTest(record.Wins, record.Losses)
+			results.Significance = append(results.Significance, PairTest{
+				Entrant:     config.Entrants[a].Name,
+				Opponent:    config.Entrants[b].Name,
+				Wins:        record.Wins,
+				Losses:      record.Losses,
+				Ties:        record.Ties,
+				PValue:      pValue,
+				Significant: pValue < significanceLevel,
+			})
+		}
+	}
+	for _, bye := range byes {
+		results.Byes = append(results.Byes, config.Entrants[bye].Name)
+	}
+	return results
+}
+
+// computeStandings ranks the entrants by match points, then game points, then rating
+func computeStandings(count int, matches []Match, byes []int) []Standing {
+	standings := make([]Standing, count)
+	for idx := range standings {
+		standings[idx] = Standing{entrant: idx, Rating: initialRating}
+	}
+	for _, bye := range byes {
+		standings[bye].Byes++
+		standings[bye].MatchPoints++
+	}
+
+	for _, match := range matches {
+		matchGamePoints := make(map[int]float64, len(match.Entrants))
+		for _, record := range match.Games {
+			for entrant, points := range gamePoints(record) {
+				matchGamePoints[entrant] += points
+				standings[entrant].GamePoints += points
+			}
+			for seat, entrant := range record.Seating {
+				standings[entrant].Games++
+				standings[entrant].totalScore += record.Scores[seat]
+			}
+			updateRatings(standings, record)
+		}
+
+		best := math.Inf(-1)
+		var leaders []int
+		for _, entrant := range match.Entrants {
+			standings[entrant].Matches++
+			switch points := matchGamePoints[entrant]; {
+			case points > best:
+				best, leaders = points, []int{entrant}
+			case points == best:
+				leaders = append(leaders, entrant)
+			}
+		}
+		for _, entrant := range leaders {
+			standings[entrant].MatchPoints += 1 / float64(len(leaders))
+		}
+	}
+
+	for idx := range standings {
+		if standings[idx].Games > 0 {
+			standings[idx].MeanScore = float64(standings[idx].totalScore) / float64(standings[idx].Games)
+		}
+	}
+	slices.SortStableFunc(standings, func(a, b Standing) int {
+		if c := cmp.Compare(b.MatchPoints, a.MatchPoints); c != 0 {
+			return c
+		}
+		if c := cmp.Compare(b.GamePoints, a.GamePoints); c != 0 {
+			return c
+		}
+		return cmp.Compare(b.Rating, a.Rating)
+	})
+	for idx := range standings {
+		standings[idx].Rank = idx + 1
+	}
+	return standings
+}
+
+// gamePoints gives 1 point to the winner of the game, split evenly between players tied for the highest score
+func gamePoints(record GameRecord) map[int]float64 {
+	best := slices.Max(record.Scores)
+	var winners []int
+	for seat, score := range record.Scores {
+		if score == best {
+			winners = append(winners, record.Seating[seat])
+		}
+	}
+	points := make(map[int]float64, len(winners))
+	for _, winner := range winners {
+		points[winner] = 1 / float64(len(winners))
+	}
+	return points
+}
+
+// updateRatings treats a game as a two player game between every pair of its players,
+// moving their ratings by the difference between the expected and actual outcome.
+// The adjustment is scaled down at bigger tables so a game moves a rating about as much regardless of its size.
+func updateRatings(standings []Standing, record GameRecord) {
+	// standings is indexed by entrant until it is sorted
+	k := ratingK / float64(len(record.Seating)-1)
+	changes := make(map[int]float64, len(record.Seating))
+	for seatA, a := range record.Seating {
+		for seatB := seatA + 1; seatB < len(record.Seating); seatB++ {
+			b := record.Seating[seatB]
+			expected := 1 / (1 + math.Pow(10, (standings[b].Rating-standings[a].Rating)/400))
+			actual := 0.5
+			switch {
+			case record.Scores[seatA] > record.Scores[seatB]:
+				actual = 1
+			case record.Scores[seatA] < record.Scores[seatB]:
+				actual = 0
+			}
+			changes[a] += k * (actual - expected)
+			changes[b] -= k * (actual - expected)
+		}
+	}
+	for entrant, change := range changes {
+		standings[entrant].Rating += change
+	}
+}
+
+// headToHead tallies the record of every entrant against every other across all games they played together
+func headToHead(count int, matches []Match) [][]HeadToHead {
+	records := make([][]HeadToHead, count)
+	for idx := range records {
+		records[idx] = make([]HeadToHead, count)
+	}
+	for _, match := range matches {
+		for _, game := range match.Games {
+			for seatA, a := range game.Seating {
+				for seatB, b := range game.Seating {
+					if seatA == seatB {
+						continue
+					}
+					record := &records[a][b]
+					record.Games++
+					record.totalScoreDiff += game.Scores[seatA] - game.Scores[seatB]
+					switch {
+					case game.Scores[seatA] > game.Scores[seatB]:
+						record.Wins++
+					case game.Scores[seatA] < game.Scores[seatB]:
+						record.Losses++
+					default:
+						record.Ties++
+					}
+				}
+			}
+		}
+	}
+	for a := range records {
+		for b := range records[a] {
+			if records[a][b].Games > 0 {
+				records[a][b].MeanScoreDiff = float64(records[a][b].totalScoreDiff) / float64(records[a][b].Games)
+			}
+		}
+	}
+	return records
+}
+
+// signTest is the two-sided p-value of an exact binomial test that wins and losses are equally likely
+func signTest(wins, losses int) float64 {
+	n := wins + losses
+	if n == 0 {
+		return 1
+	}
+	k := min(wins, losses)
+	// P(X <= k) for X ~ Binomial(n, 1/2), summed in log space so large tournaments do not overflow
+	var tail float64
+	for i := 0; i <= k; i++ {
+		tail += math.Exp(logBinomial(n, i) - float64(n)*math.Ln2)
+	}
+	return math.Min(1, 2*tail)
+}
+
+func logBinomial(n, k int) float64 {
+	lgammaN, _ := math.Lgamma(float64(n + 1))
+	lgammaK, _ := math.Lgamma(float64(k + 1))
+	lgammaNK, _ := math.Lgamma(float64(n - k + 1))
+	return lgammaN - lgammaK - lgammaNK
+}
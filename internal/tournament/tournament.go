@@ -0,0 +1,246 @@
+// Package tournament plays bots against each other in scheduled matches to rank their strategies fairly
+package tournament
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"qwixx/internal/game"
+	"qwixx/internal/game/player"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Format is how the matches of a tournament are scheduled
+type Format string
+
+const (
+	// FormatRoundRobin plays every group of TableSize entrants against each other once
+	FormatRoundRobin Format = "round-robin"
+	// FormatSwiss plays Rounds rounds of two player matches, pairing entrants with similar standings who have not met yet
+	FormatSwiss Format = "swiss"
+	// FormatPermutations plays every group of TableSize entrants in every possible seat order
+	FormatPermutations Format = "permutations"
+)
+
+// Formats lists the tournament formats Run knows
+func Formats() []Format {
+	return []Format{FormatRoundRobin, FormatSwiss, FormatPermutations}
+}
+
+// Entrant is a bot taking part in a tournament
+type Entrant struct {
+	Name     string `json:"name"`
+	Strategy string `json:"strategy"`
+}
+
+// ParseEntrants parses a comma separated list of entrants, each either a strategy name or name=strategy
+func ParseEntrants(list string) ([]Entrant, error) {
+	var entrants []Entrant
+	for _, field := range strings.Split(list, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		name, strategy, found := strings.Cut(field, "=")
+		if !found {
+			strategy = name
+		}
+		if _, err := player.NewStrategy(strategy, nil); err != nil {
+			return nil, err
+		}
+		entrants = append(entrants, Entrant{Name: name, Strategy: strategy})
+	}
+	return entrants, nil
+}
+
+// Config describes a tournament
+type Config struct {
+	Entrants []Entrant
+	Format   Format
+	// TableSize is the number of players in each game, defaulting to two. Swiss tournaments are always played in pairs.
+	TableSize int
+	// GamesPerMatch is the number of seeds each match is played with, defaulting to ten.
+	// Every match uses the same seeds and plays each of them in several seat orders,
+	// so all entrants face the same dice from every seat, like in duplicate bridge.
+	GamesPerMatch int
+	// Rounds is the number of rounds of a Swiss tournament, defaulting to enough rounds to separate the entrants
+	Rounds int
+	Seed   int64
+	// Workers is the number of games played at the same time, defaulting to the number of CPUs
+	Workers int
+}
+
+func (c Config) tableSize() int {
+	if c.Format == FormatSwiss || c.TableSize == 0 {
+		return 2
+	}
+	return c.TableSize
+}
+
+func (c Config) gamesPerMatch() int {
+	if c.GamesPerMatch < 1 {
+		return 10
+	}
+	return c.GamesPerMatch
+}
+
+func (c Config) rounds() int {
+	if c.Rounds > 0 {
+		return c.Rounds
+	}
+	rounds := 1
+	for 1<<rounds < len(c.Entrants) {
+		rounds++
+	}
+	return rounds
+}
+
+func (c Config) workers() int {
+	if c.Workers < 1 {
+		return runtime.NumCPU()
+	}
+	return c.Workers
+}
+
+func (c Config) validate() error {
+	if !slices.Contains(Formats(), c.Format) {
+		return fmt.Errorf("unknown tournament format %q", c.Format)
+	}
+	if len(c.Entrants) < 2 {
+		return errors.New("a tournament needs at least two entrants")
+	}
+	if c.tableSize() < 2 || c.tableSize() > len(c.Entrants) {
+		return fmt.Errorf("tables must seat between 2 and %d players", len(c.Entrants))
+	}
+	names := make(map[string]bool, len(c.Entrants))
+	for _, entrant := range c.Entrants {
+		if names[entrant.Name] {
+			return fmt.Errorf("entrant %q is entered twice, give them different names like a=%s,b=%s", entrant.Name, entrant.Strategy, entrant.Strategy)
+		}
+		names[entrant.Name] = true
+		if _, err := player.NewStrategy(entrant.Strategy, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GameRecord is the result of one game of a match
+type GameRecord struct {
+	Seed int64 `json:"seed"`
+	// Seating are the indexes of the entrants in the order they were seated in
+	Seating []int `json:"seating"`
+	// Scores are the scores of the entrants in the order they were seated in
+	Scores    []int          `json:"scores"`
+	Turns     int            `json:"turns"`
+	EndReason game.EndReason `json:"end_reason"`
+}
+
+// Match is a group of entrants playing every seed of the tournament against each other
+type Match struct {
+	Round int `json:"round"`
+	// Entrants are the indexes of the entrants playing the match
+	Entrants []int        `json:"entrants"`
+	Games    []GameRecord `json:"games"`
+}
+
+// Run plays the tournament and ranks its entrants
+func Run(config Config) (Results, error) {
+	if err := config.validate(); err != nil {
+		return Results{}, err
+	}
+
+	var matches []Match
+	var byes []int
+	switch config.Format {
+	case FormatRoundRobin:
+		matches = playRound(config, 1, combinations(len(config.Entrants), config.tableSize()), rotations)
+	case FormatPermutations:
+		matches = playRound(config, 1, combinations(len(config.Entrants), config.tableSize()), permutations)
+	case FormatSwiss:
+		for round := 1; round <= config.rounds(); round++ {
+			pairings, bye := swissPairings(len(config.Entrants), matches, byes)
+			if bye >= 0 {
+				byes = append(byes, bye)
+			}
+			matches = append(matches, playRound(config, round, pairings, rotations)...)
+		}
+	}
+	return newResults(config, matches, byes), nil
+}
+
+// playRound plays the given groups of entrants against each other,
+// each with every seed of the tournament in each of the seat orders given by seatOrders
+func playRound(config Config, round int, tables [][]int, seatOrders func([]int) [][]int) []Match {
+	type job struct {
+		match, game int
+		seating     []int
+		seed        int64
+	}
+	matches := make([]Match, len(tables))
+	var jobs []job
+	for matchIdx, table := range tables {
+		matches[matchIdx] = Match{Round: round, Entrants: table}
+		for seedIdx := 0; seedIdx < config.gamesPerMatch(); seedIdx++ {
+			for _, seating := range seatOrders(table) {
+				jobs = append(jobs, job{
+					match:   matchIdx,
+					game:    len(matches[matchIdx].Games),
+					seating: seating,
+					seed:    config.Seed + int64(seedIdx),
+				})
+				matches[matchIdx].Games = append(matches[matchIdx].Games, GameRecord{})
+			}
+		}
+	}
+
+	queue := make(chan job)
+	var wg sync.WaitGroup
+	for worker := 0; worker < config.workers(); worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range queue {
+				matches[j.match].Games[j.game] = playGame(config.Entrants, j.seating, j.seed)
+			}
+		}()
+	}
+	for _, j := range jobs {
+		queue <- j
+	}
+	close(queue)
+	wg.Wait()
+	return matches
+}
+
+// playGame plays one silent game between the entrants at the given indexes, seated in that order
+func playGame(entrants []Entrant, seating []int, seed int64) GameRecord {
+	players := make([]player.Player, 0, len(seating))
+	for _, entrantIdx := range seating {
+		entrant := entrants[entrantIdx]
+		// strategies get their own randomness so that the dice only depend on the seed
+		strategyRand := rand.New(rand.NewSource(seed ^ int64(entrantIdx+1)<<32))
+		strategy, _ := player.NewStrategy(entrant.Strategy, strategyRand)
+		players = append(players, player.NewStrategyPlayer(entrant.Name, strategy, nil))
+	}
+	runner := game.NewGameRunner(players, game.WithRand(rand.New(rand.NewSource(seed))))
+	result := runner.RunGame()
+
+	scoresByName := make(map[string]int, len(result.Players))
+	for _, playerResult := range result.Players {
+		scoresByName[playerResult.Name] = playerResult.Score
+	}
+	record := GameRecord{
+		Seed:      seed,
+		Seating:   seating,
+		Turns:     result.Turns,
+		EndReason: result.EndReason,
+	}
+	for _, entrantIdx := range seating {
+		record.Scores = append(record.Scores, scoresByName[entrants[entrantIdx].Name])
+	}
+	return record
+}
@@ -0,0 +1,209 @@
+package tournament
+
+import (
+	"qwixx/internal/game"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseEntrants(t *testing.T) {
+	entrants, err := ParseEntrants("greedy, a=careful,b=careful,")
+	require.NoError(t, err)
+	require.Equal(t, []Entrant{
+		{Name: "greedy", Strategy: "greedy"},
+		{Name: "a", Strategy: "careful"},
+		{Name: "b", Strategy: "careful"},
+	}, entrants)
+
+	_, err = ParseEntrants("greedy,x=cheating")
+	require.ErrorContains(t, err, `unknown strategy "cheating"`)
+}
+
+func TestRun_InvalidConfig(t *testing.T) {
+	type testCase struct {
+		name          string
+		config        Config
+		expectedError string
+	}
+	testCases := []testCase{
+		{
+			name:          "unknown format",
+			config:        Config{Format: "knockout", Entrants: []Entrant{{"a", "first"}, {"b", "first"}}},
+			expectedError: `unknown tournament format "knockout"`,
+		},
+		{
+			name:          "one entrant",
+			config:        Config{Format: FormatRoundRobin, Entrants: []Entrant{{"a", "first"}}},
+			expectedError: "a tournament needs at least two entrants",
+		},
+		{
+			name:          "table bigger than the field",
+			config:        Config{Format: FormatRoundRobin, TableSize: 3, Entrants: []Entrant{{"a", "first"}, {"b", "first"}}},
+			expectedError: "tables must seat between 2 and 2 players",
+		},
+		{
+			name:          "duplicate names",
+			config:        Config{Format: FormatRoundRobin, Entrants: []Entrant{{"a", "first"}, {"a", "greedy"}}},
+			expectedError: `entrant "a" is entered twice`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Run(tc.config)
+			require.ErrorContains(t, err, tc.expectedError)
+		})
+	}
+}
+
+func TestRun_RoundRobinSharesSeedsAcrossSeats(t *testing.T) {
+	config := Config{
+		Entrants:      []Entrant{{"first", "first"}, {"greedy", "greedy"}, {"careful", "careful"}},
+		Format:        FormatRoundRobin,
+		GamesPerMatch: 3,
+		Seed:          10,
+		Workers:       4,
+	}
+	results, err := Run(config)
+	require.NoError(t, err)
+
+	require.Len(t, results.Matches, 3)
+	for _, match := range results.Matches {
+		// every seed is played once from each seat
+		require.Len(t, match.Games, 6)
+		for idx := 0; idx < len(match.Games); idx += 2 {
+			require.Equal(t, match.Games[idx].Seed, match.Games[idx+1].Seed)
+			require.Equal(t, match.Games[idx].Seating[0], match.Games[idx+1].Seating[1])
+		}
+		require.Equal(t, []int64{10, 10, 11, 11, 12, 12}, seeds(match.Games))
+	}
+
+	require.Len(t, results.Standings, 3)
+	for _, standing := range results.Standings {
+		require.Equal(t, 2, standing.Matches)
+		require.Equal(t, 12, standing.Games)
+	}
+	require.Len(t, results.Significance, 3)
+
+	config.Workers = 1
+	sequential, err := Run(config)
+	require.NoError(t, err)
+	require.Equal(t, results, sequential)
+}
+
+func seeds(games []GameRecord) []int64 {
+	var seeds []int64
+	for _, record := range games {
+		seeds = append(seeds, record.Seed)
+	}
+	return seeds
+}
+
+func TestRun_Swiss(t *testing.T) {
+	entrants, err := ParseEntrants("a=first,b=random,c=greedy,d=careful,e=greedy")
+	require.NoError(t, err)
+	results, err := Run(Config{Entrants: entrants, Format: FormatSwiss, Rounds: 3, GamesPerMatch: 2, Seed: 1})
+	require.NoError(t, err)
+
+	// five entrants means two matches and a bye every round
+	require.Len(t, results.Matches, 6)
+	require.Len(t, results.Byes, 3)
+	require.Len(t, map[string]bool{results.Byes[0]: true, results.Byes[1]: true, results.Byes[2]: true}, 3, "nobody gets two byes")
+
+	pairs := make(map[[2]int]bool)
+	for _, match := range results.Matches {
+		pair := [2]int{match.Entrants[0], match.Entrants[1]}
+		require.False(t, pairs[pair], "entrants %v met twice", pair)
+		pairs[pair] = true
+	}
+}
+
+func TestRun_Permutations(t *testing.T) {
+	entrants, err := ParseEntrants("first,greedy,careful")
+	require.NoError(t, err)
+	results, err := Run(Config{Entrants: entrants, Format: FormatPermutations, TableSize: 3, GamesPerMatch: 1, Seed: 5})
+	require.NoError(t, err)
+
+	require.Len(t, results.Matches, 1)
+	var seatings [][]int
+	for _, record := range results.Matches[0].Games {
+		seatings = append(seatings, record.Seating)
+	}
+	require.ElementsMatch(t, [][]int{{0, 1, 2}, {0, 2, 1}, {1, 0, 2}, {1, 2, 0}, {2, 0, 1}, {2, 1, 0}}, seatings)
+}
+
+func TestSchedules(t *testing.T) {
+	require.Equal(t, [][]int{{0, 1}, {0, 2}, {0, 3}, {1, 2}, {1, 3}, {2, 3}}, combinations(4, 2))
+	require.Equal(t, [][]int{{0, 1, 2}, {0, 1, 3}, {0, 2, 3}, {1, 2, 3}}, combinations(4, 3))
+	require.Equal(t, [][]int{{4, 5, 6}, {5, 6, 4}, {6, 4, 5}}, rotations([]int{4, 5, 6}))
+	require.Len(t, permutations([]int{1, 2, 3, 4}), 24)
+}
+
+func TestSwissPairings(t *testing.T) {
+	// 0 beat 1 and 2 beat 3 in the first round, so the winners and losers meet next
+	matches := []Match{
+		{Entrants: []int{0, 1}, Games: []GameRecord{{Seating: []int{0, 1}, Scores: []int{20, 10}}}},
+		{Entrants: []int{2, 3}, Games: []GameRecord{{Seating: []int{2, 3}, Scores: []int{30, 10}}}},
+	}
+	pairings, bye := swissPairings(4, matches, nil)
+	require.Equal(t, -1, bye)
+	require.Equal(t, [][]int{{0, 2}, {1, 3}}, pairings)
+
+	// the lowest ranked entrant without a bye sits out
+	pairings, bye = swissPairings(3, nil, []int{2})
+	require.Equal(t, 1, bye)
+	require.Equal(t, [][]int{{0, 2}}, pairings)
+}
+
+func TestComputeStandings(t *testing.T) {
+	matches := []Match{
+		{
+			Entrants: []int{0, 1},
+			Games: []GameRecord{
+				{Seating: []int{0, 1}, Scores: []int{20, 10}, EndReason: game.EndReasonRowsLocked},
+				{Seating: []int{1, 0}, Scores: []int{15, 15}, EndReason: game.EndReasonPenalties},
+			},
+		},
+	}
+	standings := computeStandings(3, matches, []int{2})
+
+	require.Equal(t, 0, standings[0].entrant)
+	require.Equal(t, 1, standings[0].Rank)
+	require.Equal(t, 1.0, standings[0].MatchPoints)
+	require.Equal(t, 1.5, standings[0].GamePoints)
+	require.Equal(t, 17.5, standings[0].MeanScore)
+	require.Greater(t, standings[0].Rating, float64(initialRating))
+
+	// the bye is worth a match win but no games
+	require.Equal(t, 2, standings[1].entrant)
+	require.Equal(t, 1.0, standings[1].MatchPoints)
+	require.Equal(t, 1, standings[1].Byes)
+	require.Equal(t, float64(initialRating), standings[1].Rating)
+
+	require.Equal(t, 1, standings[2].entrant)
+	require.Equal(t, 0.5, standings[2].GamePoints)
+	require.Less(t, standings[2].Rating, float64(initialRating))
+}
+
+func TestHeadToHead(t *testing.T) {
+	matches := []Match{{
+		Entrants: []int{0, 1, 2},
+		Games: []GameRecord{
+			{Seating: []int{2, 0, 1}, Scores: []int{5, 20, 10}},
+			{Seating: []int{0, 1, 2}, Scores: []int{10, 10, 30}},
+		},
+	}}
+	records := headToHead(3, matches)
+	require.Equal(t, HeadToHead{Games: 2, Wins: 1, Ties: 1, MeanScoreDiff: 5, totalScoreDiff: 10}, records[0][1])
+	require.Equal(t, HeadToHead{Games: 2, Losses: 1, Ties: 1, MeanScoreDiff: -5, totalScoreDiff: -10}, records[1][0])
+	require.Equal(t, HeadToHead{Games: 2, Wins: 1, Losses: 1, MeanScoreDiff: 2.5, totalScoreDiff: 5}, records[2][0])
+}
+
+func TestSignTest(t *testing.T) {
+	require.Equal(t, 1.0, signTest(0, 0))
+	require.Equal(t, 1.0, signTest(5, 5))
+	// P(X <= 1) for 10 fair coin flips is 11/1024
+	require.InDelta(t, 22.0/1024, signTest(9, 1), 1e-12)
+	require.InDelta(t, 22.0/1024, signTest(1, 9), 1e-12)
+	require.Less(t, signTest(600, 400), 1e-9)
+}
@@ -0,0 +1,419 @@
+// Package tui implements a full-screen terminal client for the websocket server.
+// The Model holds everything the client knows and decides what to send to the server in response to keys,
+// while View draws it, so both can be exercised without a terminal or a server.
+package tui
+
+import (
+	"fmt"
+	"qwixx/internal/game"
+	"qwixx/internal/game/actions"
+	"qwixx/internal/game/board"
+	"qwixx/internal/game/player"
+	"qwixx/internal/game/rule_checker"
+	"qwixx/internal/protocol"
+	"slices"
+	"strings"
+)
+
+// maxLines is how many chat and log lines are kept
+const maxLines = 200
+
+// Key is a key press, either a printable rune or one of the special keys below
+type Key rune
+
+const (
+	KeyUp Key = -(iota + 1)
+	KeyDown
+	KeyLeft
+	KeyRight
+	KeyEnter
+	KeyTab
+	KeyEscape
+	KeyBackspace
+	KeyCtrlC
+)
+
+// prompt is a request for a turn the player has not answered yet
+type prompt struct {
+	id       int
+	active   bool
+	board    board.Board
+	diceRoll actions.DiceRoll
+}
+
+// Model is the state of the client
+type Model struct {
+	Name      string
+	LobbyCode string
+	Lobby     protocol.LobbyState
+	Quit      bool
+
+	started   bool
+	over      bool
+	you       player.PlayerID
+	variant   board.Variant
+	config    game.GameConfig
+	players   []protocol.PlayerInfo
+	playOrder []string
+	boards    map[player.PlayerID]board.State
+
+	prompt    *prompt
+	diceRoll  *actions.DiceRoll
+	cursorRow int
+	cursorCol int
+	white     *actions.Move
+	color     *actions.Move
+
+	chatting  bool
+	chatInput string
+	chat      []string
+	log       []string
+	status    string
+}
+
+func NewModel(name string) *Model {
+	return &Model{
+		Name:   name,
+		boards: make(map[player.PlayerID]board.State),
+		status: "waiting for the lobby",
+	}
+}
+
+func appendLine(lines []string, line string) []string {
+	lines = append(lines, line)
+	if len(lines) > maxLines {
+		lines = lines[len(lines)-maxLines:]
+	}
+	return lines
+}
+
+// HandleMessage updates the model with a message from the server
+func (m *Model) HandleMessage(message protocol.Message) error {
+	switch message.Type {
+	case protocol.MessageLobbyState:
+		if err := message.Decode(&m.Lobby); err != nil {
+			return err
+		}
+		m.LobbyCode = m.Lobby.Code
+		m.status = fmt.Sprintf("in lobby %v: b adds a bot, s starts the game", m.Lobby.Code)
+	case protocol.MessageError:
+		var serverError protocol.Error
+		if err := message.Decode(&serverError); err != nil {
+			return err
+		}
+		m.status = "error: " + serverError.Message
+	case protocol.MessageGameStarted:
+		var started protocol.GameStarted
+		if err := message.Decode(&started); err != nil {
+			return err
+		}
+		m.started = true
+		m.you = started.You
+		m.variant = started.Variant
+		m.config = started.Config
+		m.players = started.Players
+		emptyBoard, err := board.NewBoard(started.Variant)
+		if err != nil {
+			return err
+		}
+		for _, info := range started.Players {
+			m.boards[info.ID] = board.StateOf(emptyBoard)
+		}
+		m.status = "the game has started"
+	case protocol.MessagePlayOrder:
+		var playOrder protocol.PlayOrder
+		if err := message.Decode(&playOrder); err != nil {
+			return err
+		}
+		m.playOrder = playOrder.Names
+		m.sortPlayers()
+	case protocol.MessagePromptActive, protocol.MessagePromptInactive:
+		var received protocol.Prompt
+		if err := message.Decode(&received); err != nil {
+			return err
+		}
+		promptBoard, err := board.FromState(received.Board)
+		if err != nil {
+			return err
+		}
+		m.prompt = &prompt{
+			id:       received.PromptID,
+			active:   message.Type == protocol.MessagePromptActive,
+			board:    promptBoard,
+			diceRoll: received.DiceRoll,
+		}
+		m.diceRoll = &received.DiceRoll
+		m.boards[m.you] = received.Board
+		m.white, m.color = nil, nil
+		if m.prompt.active {
+			m.status = "your turn: w/c pick the cell for the white/color dice, enter submits, p takes a penalty"
+		} else {
+			m.status = "white dice: w picks the cell for their sum, enter submits, p passes"
+		}
+	case protocol.MessageInvalidTurn:
+		var invalid protocol.InvalidTurn
+		if err := message.Decode(&invalid); err != nil {
+			return err
+		}
+		// the prompt asking again replaces the status, so the reason is kept in the log
+		m.log = appendLine(m.log, "turn rejected: "+invalid.Message)
+	case protocol.MessageBoardUpdate:
+		var update protocol.BoardUpdate
+		if err := message.Decode(&update); err != nil {
+			return err
+		}
+		m.boards[update.PlayerID] = update.Board
+	case protocol.MessageRowLocked:
+		var locked protocol.RowLocked
+		if err := message.Decode(&locked); err != nil {
+			return err
+		}
+		for playerID, state := range m.boards {
+			if !slices.Contains(state.Locked, locked.RowColor) {
+				state.Locked = append(state.Locked, locked.RowColor)
+				m.boards[playerID] = state
+			}
+		}
+	case protocol.MessageGameOver:
+		var gameOver protocol.GameOver
+		if err := message.Decode(&gameOver); err != nil {
+			return err
+		}
+		m.over = true
+		m.prompt = nil
+		if gameOver.Won {
+			m.status = "game over, you won! q quits"
+		} else {
+			m.status = fmt.Sprintf("game over, %v won. q quits", m.playerName(gameOver.WinnerID))
+		}
+	case protocol.MessageLog:
+		var logLine protocol.Log
+		if err := message.Decode(&logLine); err != nil {
+			return err
+		}
+		m.log = appendLine(m.log, logLine.Text)
+	case protocol.MessageChat:
+		var chat protocol.Chat
+		if err := message.Decode(&chat); err != nil {
+			return err
+		}
+		m.chat = appendLine(m.chat, fmt.Sprintf("%v: %v", chat.From, chat.Text))
+	}
+	return nil
+}
+
+// sortPlayers orders the players by the play order
+func (m *Model) sortPlayers() {
+	slices.SortStableFunc(m.players, func(a, b protocol.PlayerInfo) int {
+		return slices.Index(m.playOrder, a.Name) - slices.Index(m.playOrder, b.Name)
+	})
+}
+
+func (m *Model) playerName(playerID player.PlayerID) string {
+	for _, info := range m.players {
+		if info.ID == playerID {
+			return info.Name
+		}
+	}
+	return string(playerID)
+}
+
+// cursorMove is the move for the cell under the cursor
+func (m *Model) cursorMove() actions.Move {
+	rowColor := m.rowColors()[m.cursorRow]
+	return actions.NewMove(rowColor, m.cells(rowColor)[m.cursorCol].Number)
+}
+
+// LegalWhiteMoves are the cells that can be crossed off with the sum of the white dice
+func (m *Model) LegalWhiteMoves() []actions.Move {
+	if m.prompt == nil {
+		return nil
+	}
+	var legal []actions.Move
+	for _, turn := range rule_checker.LegalInactiveTurns(m.prompt.board, m.prompt.diceRoll) {
+		if turn.WhiteDiceMove != nil {
+			legal = append(legal, *turn.WhiteDiceMove)
+		}
+	}
+	return legal
+}
+
+// LegalColorMoves are the cells that can be crossed off with a color die, after the chosen white dice move is made,
+// or before it if the house rules let the dice be used in any order
+func (m *Model) LegalColorMoves() []actions.Move {
+	if m.prompt == nil || !m.prompt.active {
+		return nil
+	}
+	var legal []actions.Move
+	for _, turn := range rule_checker.LegalActiveTurns(m.prompt.board, m.prompt.diceRoll) {
+		sameWhiteDiceMove := turn.WhiteDiceMove == nil && m.white == nil ||
+			turn.WhiteDiceMove != nil && m.white != nil && *turn.WhiteDiceMove == *m.white
+		if sameWhiteDiceMove && turn.ColorDiceMove != nil {
+			legal = append(legal, *turn.ColorDiceMove)
+		}
+	}
+	if m.config.AnyDiceOrder && m.white != nil {
+		for _, colorDiceMove := range rule_checker.PossibleColorDiceMoves(m.prompt.board, m.prompt.diceRoll) {
+			turn := actions.ActivePlayerTurn{WhiteDiceMove: m.white, ColorDiceMove: &colorDiceMove}
+			colorFirst, err := rule_checker.ValidateActivePlayerTurnInAnyOrder(m.prompt.board, m.prompt.diceRoll, turn)
+			if err == nil && colorFirst {
+				legal = append(legal, colorDiceMove)
+			}
+		}
+	}
+	return legal
+}
+
+// HandleKey updates the model with a key press, returning the messages to send to the server
+func (m *Model) HandleKey(key Key) []protocol.Message {
+	if key == KeyCtrlC {
+		m.Quit = true
+		return nil
+	}
+	if m.chatting {
+		return m.handleChatKey(key)
+	}
+
+	switch key {
+	case KeyTab:
+		m.chatting = true
+	case 'q':
+		m.Quit = true
+	case KeyUp, 'k':
+		m.cursorRow = (m.cursorRow + len(m.rowColors()) - 1) % len(m.rowColors())
+	case KeyDown, 'j':
+		m.cursorRow = (m.cursorRow + 1) % len(m.rowColors())
+	case KeyLeft, 'h':
+		m.cursorCol = (m.cursorCol + 10) % 11
+	case KeyRight, 'l':
+		m.cursorCol = (m.cursorCol + 1) % 11
+	case 'b':
+		if !m.started {
+			return m.message(protocol.MessageAddBot, protocol.AddBot{Name: fmt.Sprintf("bot%v", len(m.Lobby.Players))})
+		}
+	case 's':
+		if !m.started {
+			return m.message(protocol.MessageStartGame, nil)
+		}
+	case 'w':
+		m.chooseWhite()
+	case 'c':
+		m.chooseColor()
+	case 'p':
+		if m.prompt != nil {
+			m.white, m.color = nil, nil
+			return m.submit()
+		}
+	case KeyEnter:
+		if m.prompt != nil {
+			return m.submit()
+		}
+	}
+	return nil
+}
+
+func (m *Model) handleChatKey(key Key) []protocol.Message {
+	switch key {
+	case KeyTab, KeyEscape:
+		m.chatting = false
+	case KeyBackspace:
+		if len(m.chatInput) > 0 {
+			runes := []rune(m.chatInput)
+			m.chatInput = string(runes[:len(runes)-1])
+		}
+	case KeyEnter:
+		text := strings.TrimSpace(m.chatInput)
+		m.chatInput = ""
+		if text != "" {
+			return m.message(protocol.MessageChat, protocol.Chat{Text: text})
+		}
+	default:
+		if key > 0 {
+			m.chatInput += string(rune(key))
+		}
+	}
+	return nil
+}
+
+// chooseWhite toggles the cell under the cursor as the white dice move
+func (m *Model) chooseWhite() {
+	if m.prompt == nil {
+		return
+	}
+	move := m.cursorMove()
+	if m.white != nil && *m.white == move {
+		m.white = nil
+	} else if slices.Contains(m.LegalWhiteMoves(), move) {
+		m.white = &move
+	} else {
+		m.status = fmt.Sprintf("%v %v cannot be crossed off with the white dice", move.RowColor, move.CellNumber)
+		return
+	}
+	// the white dice move changes which color dice moves are possible
+	if m.color != nil && !slices.Contains(m.LegalColorMoves(), *m.color) {
+		m.color = nil
+	}
+	m.status = m.selectionStatus()
+}
+
+// chooseColor toggles the cell under the cursor as the color dice move
+func (m *Model) chooseColor() {
+	if m.prompt == nil {
+		return
+	}
+	if !m.prompt.active {
+		m.status = "only the active player can use the color dice"
+		return
+	}
+	move := m.cursorMove()
+	if m.color != nil && *m.color == move {
+		m.color = nil
+	} else if slices.Contains(m.LegalColorMoves(), move) {
+		m.color = &move
+	} else {
+		m.status = fmt.Sprintf("%v %v cannot be crossed off with a color die", move.RowColor, move.CellNumber)
+		return
+	}
+	m.status = m.selectionStatus()
+}
+
+func (m *Model) selectionStatus() string {
+	describe := func(move *actions.Move) string {
+		if move == nil {
+			return "-"
+		}
+		return fmt.Sprintf("%v %v", move.RowColor, move.CellNumber)
+	}
+	return fmt.Sprintf("white: %v, color: %v. enter submits", describe(m.white), describe(m.color))
+}
+
+// submit answers the current prompt with the chosen moves
+func (m *Model) submit() []protocol.Message {
+	submission := protocol.SubmitTurn{PromptID: m.prompt.id, WhiteDiceMove: m.white}
+	if m.prompt.active {
+		submission.ColorDiceMove = m.color
+	}
+	m.prompt = nil
+	m.white, m.color = nil, nil
+	m.status = "waiting for the other players"
+	return m.message(protocol.MessageSubmitTurn, submission)
+}
+
+func (m *Model) message(messageType protocol.MessageType, payload any) []protocol.Message {
+	message, err := protocol.NewMessage(messageType, payload)
+	if err != nil {
+		m.status = err.Error()
+		return nil
+	}
+	return []protocol.Message{message}
+}
+
+// rowColors lists the colors of the rows from top to bottom on the sheet of the game
+func (m *Model) rowColors() []actions.RowColor {
+	return m.variant.RowColors()
+}
+
+// cells lists the cells of the row with the given color from left to right on the sheet of the game
+func (m *Model) cells(rowColor actions.RowColor) []board.Cell {
+	return m.variant.Cells(rowColor)
+}
@@ -0,0 +1,172 @@
+package tui
+
+import (
+	"qwixx/internal/game"
+	"qwixx/internal/game/actions"
+	"qwixx/internal/game/board"
+	"qwixx/internal/protocol"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var testDiceRoll = actions.DiceRoll{
+	WhiteDiceRoll: actions.WhiteDiceRoll{
+		White1: 4,
+		White2: 5,
+	},
+	ColorDiceRoll: actions.ColorDiceRoll{
+		Red:    4,
+		Yellow: 3,
+		Green:  6,
+		Blue:   2,
+	},
+}
+
+func message(t *testing.T, messageType protocol.MessageType, payload any) protocol.Message {
+	t.Helper()
+	m, err := protocol.NewMessage(messageType, payload)
+	require.NoError(t, err)
+	return m
+}
+
+// startedModel returns a model for alice in a game against bob that was just prompted for its active turn
+func startedModel(t *testing.T) *Model {
+	t.Helper()
+	m := NewModel("alice")
+	require.NoError(t, m.HandleMessage(message(t, protocol.MessageGameStarted, protocol.GameStarted{
+		GameID:  "game",
+		You:     "a",
+		Players: []protocol.PlayerInfo{{ID: "b", Name: "bob"}, {ID: "a", Name: "alice"}},
+	})))
+	require.NoError(t, m.HandleMessage(message(t, protocol.MessagePlayOrder, protocol.PlayOrder{Names: []string{"alice", "bob"}})))
+	require.NoError(t, m.HandleMessage(message(t, protocol.MessagePromptActive, protocol.Prompt{
+		PromptID: 7,
+		Board:    board.StateOf(board.NewGameBoard()),
+		DiceRoll: testDiceRoll,
+	})))
+	return m
+}
+
+func pressKeys(m *Model, keys ...Key) []protocol.Message {
+	var sent []protocol.Message
+	for _, key := range keys {
+		sent = append(sent, m.HandleKey(key)...)
+	}
+	return sent
+}
+
+func TestModel_SubmitActiveTurn(t *testing.T) {
+	m := startedModel(t)
+	require.Equal(t, []protocol.PlayerInfo{{ID: "a", Name: "alice"}, {ID: "b", Name: "bob"}}, m.players)
+
+	// red 9 is the eighth cell of the top row
+	sent := pressKeys(m, KeyRight, KeyRight, KeyRight, KeyRight, KeyRight, KeyRight, KeyRight, 'w')
+	require.Empty(t, sent)
+	require.Equal(t, &actions.Move{RowColor: actions.RowColorRed, CellNumber: 9}, m.white)
+	require.NotContains(t, m.LegalColorMoves(), actions.NewMove(actions.RowColorRed, 8), "red 8 is left of the white dice move")
+
+	// red 8 is no longer allowed, the cursor wraps around to blue 7 in the bottom row
+	pressKeys(m, KeyLeft, 'c')
+	require.Nil(t, m.color)
+	require.Contains(t, m.status, "cannot be crossed off with a color die")
+	pressKeys(m, KeyUp, KeyLeft, 'c')
+	require.Equal(t, &actions.Move{RowColor: actions.RowColorBlue, CellNumber: 7}, m.color)
+
+	sent = pressKeys(m, KeyEnter)
+	require.Len(t, sent, 1)
+	require.Equal(t, protocol.MessageSubmitTurn, sent[0].Type)
+	var submission protocol.SubmitTurn
+	require.NoError(t, sent[0].Decode(&submission))
+	require.Equal(t, protocol.SubmitTurn{
+		PromptID:      7,
+		WhiteDiceMove: &actions.Move{RowColor: actions.RowColorRed, CellNumber: 9},
+		ColorDiceMove: &actions.Move{RowColor: actions.RowColorBlue, CellNumber: 7},
+	}, submission)
+	require.Nil(t, m.prompt)
+}
+
+func TestModel_AnyDiceOrder(t *testing.T) {
+	m := NewModel("alice")
+	require.NoError(t, m.HandleMessage(message(t, protocol.MessageGameStarted, protocol.GameStarted{
+		GameID:  "game",
+		You:     "a",
+		Players: []protocol.PlayerInfo{{ID: "a", Name: "alice"}, {ID: "b", Name: "bob"}},
+		Config:  game.GameConfig{AnyDiceOrder: true}.WithDefaults(),
+	})))
+	require.NoError(t, m.HandleMessage(message(t, protocol.MessagePromptActive, protocol.Prompt{
+		PromptID: 7,
+		Board:    board.StateOf(board.NewGameBoard()),
+		DiceRoll: testDiceRoll,
+	})))
+
+	// with the dice in any order, the red die can cross off red 8 before the white dice cross off red 9
+	pressKeys(m, KeyRight, KeyRight, KeyRight, KeyRight, KeyRight, KeyRight, KeyRight, 'w')
+	require.Contains(t, m.LegalColorMoves(), actions.NewMove(actions.RowColorRed, 8))
+	pressKeys(m, KeyLeft, 'c')
+	require.Equal(t, &actions.Move{RowColor: actions.RowColorRed, CellNumber: 8}, m.color)
+}
+
+func TestModel_InactivePromptRejectsColorDice(t *testing.T) {
+	m := startedModel(t)
+	require.NoError(t, m.HandleMessage(message(t, protocol.MessagePromptInactive, protocol.Prompt{
+		PromptID: 8,
+		Board:    board.StateOf(board.NewGameBoard()),
+		DiceRoll: testDiceRoll,
+	})))
+
+	pressKeys(m, 'c')
+	require.Nil(t, m.color)
+	require.Equal(t, "only the active player can use the color dice", m.status)
+
+	sent := pressKeys(m, 'p')
+	require.Len(t, sent, 1)
+	var submission protocol.SubmitTurn
+	require.NoError(t, sent[0].Decode(&submission))
+	require.Equal(t, protocol.SubmitTurn{PromptID: 8}, submission)
+}
+
+func TestModel_Chat(t *testing.T) {
+	m := startedModel(t)
+	sent := pressKeys(m, KeyTab, 'h', 'i', 'x', KeyBackspace, '!', KeyEnter)
+	require.Len(t, sent, 1)
+	var chat protocol.Chat
+	require.NoError(t, sent[0].Decode(&chat))
+	require.Equal(t, "hi!", chat.Text)
+
+	// keys go to the board again after leaving the chat
+	pressKeys(m, KeyEscape, 'l')
+	require.Equal(t, 1, m.cursorCol)
+
+	require.NoError(t, m.HandleMessage(message(t, protocol.MessageChat, protocol.Chat{From: "bob", Text: "gl"})))
+	require.Equal(t, []string{"bob: gl"}, m.chat)
+
+	require.NoError(t, m.HandleMessage(message(t, protocol.MessageInvalidTurn, protocol.InvalidTurn{
+		PromptID: 2,
+		Code:     board.CodeRowLocked,
+		Message:  "white dice move (Yellow 9): row is locked",
+	})))
+	require.Equal(t, []string{"turn rejected: white dice move (Yellow 9): row is locked"}, m.log)
+}
+
+func TestModel_View(t *testing.T) {
+	m := startedModel(t)
+	require.NoError(t, m.HandleMessage(message(t, protocol.MessageBoardUpdate, protocol.BoardUpdate{
+		PlayerID: "b",
+		Board:    board.State{Rows: map[actions.RowColor][]int{actions.RowColorGreen: {12, 11}}},
+	})))
+	require.NoError(t, m.HandleMessage(message(t, protocol.MessageRowLocked, protocol.RowLocked{RowColor: actions.RowColorYellow})))
+	require.NoError(t, m.HandleMessage(message(t, protocol.MessageLog, protocol.Log{Text: "bob crossed off Green 11"})))
+
+	screen := m.View(120, 30)
+	plain := ansiEscape.ReplaceAllString(screen, "")
+	require.Contains(t, plain, "alice (you)")
+	require.Contains(t, plain, "bob crossed off Green 11")
+	require.Contains(t, plain, "[ 2]  3   4", "the cursor starts on the first cell")
+	require.Contains(t, plain, "  X   X  10", "bob's crossed off green cells")
+	// red 9 is legal for the white dice and highlighted on alice's board
+	require.Contains(t, screen, ansiReverse+" 9")
+	// the yellow row is locked on every board
+	require.Equal(t, 2, strings.Count(screen, ansiReverse+" L"))
+}
@@ -0,0 +1,116 @@
+package tui
+
+import (
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	ansiAlternateScreen = "\x1b[?1049h"
+	ansiMainScreen      = "\x1b[?1049l"
+	ansiHideCursor      = "\x1b[?25l"
+	ansiShowCursor      = "\x1b[?25h"
+)
+
+// stty runs stty against the given terminal
+func stty(tty *os.File, args ...string) (string, error) {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = tty
+	output, err := cmd.Output()
+	return strings.TrimSpace(string(output)), err
+}
+
+// EnterFullScreen switches the terminal to raw mode on an alternate screen,
+// returning a function that restores it to the way it was
+func EnterFullScreen(tty *os.File) (restore func(), err error) {
+	previous, err := stty(tty, "-g")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := stty(tty, "raw", "-echo"); err != nil {
+		return nil, err
+	}
+	_, _ = io.WriteString(tty, ansiAlternateScreen+ansiHideCursor)
+	return func() {
+		_, _ = io.WriteString(tty, ansiShowCursor+ansiMainScreen)
+		_, _ = stty(tty, previous)
+	}, nil
+}
+
+// Size returns the width and height of the terminal, falling back to 80x24 if it cannot be determined
+func Size(tty *os.File) (width, height int) {
+	output, err := stty(tty, "size")
+	if err != nil {
+		return 80, 24
+	}
+	fields := strings.Fields(output)
+	if len(fields) != 2 {
+		return 80, 24
+	}
+	rows, rowsErr := strconv.Atoi(fields[0])
+	cols, colsErr := strconv.Atoi(fields[1])
+	if rowsErr != nil || colsErr != nil {
+		return 80, 24
+	}
+	return cols, rows
+}
+
+// ReadKeys decodes key presses from the raw terminal input until it is closed
+func ReadKeys(in io.Reader, keys chan<- Key) {
+	defer close(keys)
+	buf := make([]byte, 64)
+	for {
+		n, err := in.Read(buf)
+		for _, key := range DecodeKeys(buf[:n]) {
+			keys <- key
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// DecodeKeys decodes the key presses in a chunk of raw terminal input
+func DecodeKeys(input []byte) []Key {
+	var keys []Key
+	for len(input) > 0 {
+		switch {
+		case len(input) >= 3 && input[0] == 0x1b && input[1] == '[':
+			switch input[2] {
+			case 'A':
+				keys = append(keys, KeyUp)
+			case 'B':
+				keys = append(keys, KeyDown)
+			case 'C':
+				keys = append(keys, KeyRight)
+			case 'D':
+				keys = append(keys, KeyLeft)
+			}
+			input = input[3:]
+			continue
+		case input[0] == 0x1b:
+			keys = append(keys, KeyEscape)
+		case input[0] == '\r' || input[0] == '\n':
+			keys = append(keys, KeyEnter)
+		case input[0] == '\t':
+			keys = append(keys, KeyTab)
+		case input[0] == 0x7f || input[0] == 0x08:
+			keys = append(keys, KeyBackspace)
+		case input[0] == 0x03:
+			keys = append(keys, KeyCtrlC)
+		case input[0] < 0x20:
+			// other control characters are ignored
+		default:
+			r, size := utf8.DecodeRune(input)
+			keys = append(keys, Key(r))
+			input = input[size:]
+			continue
+		}
+		input = input[1:]
+	}
+	return keys
+}
@@ -0,0 +1,42 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeKeys(t *testing.T) {
+	type testCase struct {
+		name         string
+		input        []byte
+		expectedKeys []Key
+	}
+	testCases := []testCase{
+		{
+			name:         "arrow keys",
+			input:        []byte("\x1b[A\x1b[B\x1b[C\x1b[D"),
+			expectedKeys: []Key{KeyUp, KeyDown, KeyRight, KeyLeft},
+		},
+		{
+			name:         "printable characters and enter",
+			input:        []byte("wc\r"),
+			expectedKeys: []Key{'w', 'c', KeyEnter},
+		},
+		{
+			name:         "control keys",
+			input:        []byte{'\t', 0x7f, 0x03, 0x1b},
+			expectedKeys: []Key{KeyTab, KeyBackspace, KeyCtrlC, KeyEscape},
+		},
+		{
+			name:         "multi byte runes",
+			input:        []byte("héé"),
+			expectedKeys: []Key{'h', 'é', 'é'},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expectedKeys, DecodeKeys(tc.input))
+		})
+	}
+}
@@ -0,0 +1,218 @@
+package tui
+
+import (
+	"fmt"
+	"qwixx/internal/game"
+	"qwixx/internal/game/actions"
+	"qwixx/internal/game/board"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	ansiReset     = "\x1b[0m"
+	ansiBold      = "\x1b[1m"
+	ansiDim       = "\x1b[2m"
+	ansiUnderline = "\x1b[4m"
+	ansiReverse   = "\x1b[7m"
+
+	// ansiClearScreen moves the cursor to the top left and clears the screen
+	ansiClearScreen = "\x1b[H\x1b[2J"
+)
+
+var rowANSIColors = map[actions.RowColor]string{
+	actions.RowColorRed:    "\x1b[31m",
+	actions.RowColorYellow: "\x1b[33m",
+	actions.RowColorGreen:  "\x1b[32m",
+	actions.RowColorBlue:   "\x1b[34m",
+	actions.RowColorOrange: "\x1b[38;5;208m",
+	actions.RowColorPurple: "\x1b[35m",
+}
+
+var ansiEscape = regexp.MustCompile("\x1b\\[[0-9;]*[a-zA-Z]")
+
+// visibleWidth is the number of columns the given text takes up on screen, ignoring escape codes
+func visibleWidth(text string) int {
+	return utf8.RuneCountInString(ansiEscape.ReplaceAllString(text, ""))
+}
+
+// fit pads or truncates the given line to exactly the given visible width
+func fit(line string, width int) string {
+	if visibleWidth(line) <= width {
+		return line + strings.Repeat(" ", width-visibleWidth(line))
+	}
+	plain := []rune(ansiEscape.ReplaceAllString(line, ""))
+	return string(plain[:width])
+}
+
+// View draws the whole screen for a terminal of the given size
+func (m *Model) View(width, height int) string {
+	if width < 40 || height < 10 {
+		return ansiClearScreen + "terminal too small"
+	}
+	leftWidth := width * 3 / 5
+	rightWidth := width - leftWidth - 3
+	bodyHeight := height - 2
+
+	var left []string
+	if m.started {
+		left = m.boardLines()
+	} else {
+		left = m.lobbyLines()
+	}
+	right := m.sideLines(bodyHeight)
+
+	var screen strings.Builder
+	screen.WriteString(ansiClearScreen)
+	for idx := 0; idx < bodyHeight; idx++ {
+		var leftLine, rightLine string
+		if idx < len(left) {
+			leftLine = left[idx]
+		}
+		if idx < len(right) {
+			rightLine = right[idx]
+		}
+		screen.WriteString(fit(leftLine, leftWidth))
+		screen.WriteString(ansiReset + " │ ")
+		screen.WriteString(fit(rightLine, rightWidth))
+		screen.WriteString(ansiReset + "\r\n")
+	}
+	screen.WriteString(strings.Repeat("─", width) + "\r\n")
+	screen.WriteString(fit(m.status, width))
+	return screen.String()
+}
+
+func (m *Model) lobbyLines() []string {
+	lines := []string{ansiBold + "Qwixx" + ansiReset, ""}
+	if m.Lobby.Code == "" {
+		return append(lines, "connecting...")
+	}
+	lines = append(lines, "lobby code: "+m.Lobby.Code)
+	if m.Lobby.Config != game.DefaultGameConfig() && m.Lobby.Config != (game.GameConfig{}) {
+		lines = append(lines, "house rules: "+m.Lobby.Config.String())
+	}
+	lines = append(lines, "", "players:")
+	for _, name := range m.Lobby.Players {
+		line := "  " + name
+		if name == m.Lobby.Host {
+			line += " (host)"
+		}
+		lines = append(lines, line)
+	}
+	return append(lines, "", "b: add a bot   s: start the game   tab: chat   q: quit")
+}
+
+func (m *Model) boardLines() []string {
+	var lines []string
+	if m.diceRoll != nil {
+		dice := fmt.Sprintf(
+			"dice: white %v %v  %vred %v%v  %vyellow %v%v  %vgreen %v%v  %vblue %v%v",
+			m.diceRoll.White1, m.diceRoll.White2,
+			rowANSIColors[actions.RowColorRed], m.diceRoll.Red, ansiReset,
+			rowANSIColors[actions.RowColorYellow], m.diceRoll.Yellow, ansiReset,
+			rowANSIColors[actions.RowColorGreen], m.diceRoll.Green, ansiReset,
+			rowANSIColors[actions.RowColorBlue], m.diceRoll.Blue, ansiReset,
+		)
+		if m.diceRoll.Orange > 0 || m.diceRoll.Purple > 0 {
+			dice += fmt.Sprintf(
+				"  %vorange %v%v  %vpurple %v%v",
+				rowANSIColors[actions.RowColorOrange], m.diceRoll.Orange, ansiReset,
+				rowANSIColors[actions.RowColorPurple], m.diceRoll.Purple, ansiReset,
+			)
+		}
+		lines = append(lines, dice)
+	} else {
+		lines = append(lines, "dice: not rolled yet")
+	}
+	lines = append(lines, "")
+
+	for _, info := range m.players {
+		title := info.Name
+		if info.ID == m.you {
+			title = ansiBold + info.Name + " (you)" + ansiReset
+		}
+		lines = append(lines, title)
+		lines = append(lines, m.rowLines(m.boards[info.ID], info.ID == m.you)...)
+		lines = append(lines, "")
+	}
+	return lines
+}
+
+// rowLines draws the rows of a board, highlighting legal and chosen cells and the cursor on the player's own board
+func (m *Model) rowLines(state board.State, own bool) []string {
+	var legalWhite, legalColor []actions.Move
+	if own {
+		legalWhite = m.LegalWhiteMoves()
+		legalColor = m.LegalColorMoves()
+	}
+	lines := make([]string, 0, len(m.rowColors()))
+	for rowIdx, rowColor := range m.rowColors() {
+		var line strings.Builder
+		line.WriteString(rowANSIColors[rowColor])
+		for colIdx, cell := range m.cells(rowColor) {
+			cellNumber := cell.Number
+			move := actions.NewMove(rowColor, cellNumber)
+			text := fmt.Sprintf("%2d", cellNumber)
+			var style string
+			switch {
+			case slices.Contains(state.Rows[rowColor], cellNumber):
+				text = " X"
+				style = ansiBold
+			case m.white != nil && own && *m.white == move, m.color != nil && own && *m.color == move:
+				text = " *"
+				style = ansiBold + ansiReverse
+			case slices.Contains(legalWhite, move):
+				style = ansiReverse
+			case slices.Contains(legalColor, move):
+				style = ansiUnderline
+			}
+			before, after := " ", " "
+			if own && m.cursorRow == rowIdx && m.cursorCol == colIdx {
+				before, after = "[", "]"
+			}
+			if cell.Color != rowColor {
+				// on a sheet with mixed colors the cells are drawn in their own colors rather than their row's
+				style = rowANSIColors[cell.Color] + style
+			}
+			line.WriteString(before + style + text + ansiReset + rowANSIColors[rowColor] + after)
+		}
+		if slices.Contains(state.Locked, rowColor) {
+			line.WriteString(ansiReverse + " L" + ansiReset)
+		} else {
+			line.WriteString(ansiDim + " L" + ansiReset)
+		}
+		lines = append(lines, line.String())
+	}
+	return lines
+}
+
+// sideLines draws the chat above the game log
+func (m *Model) sideLines(height int) []string {
+	chatHeight := height/2 - 2
+	logHeight := height - chatHeight - 4
+
+	lines := []string{ansiBold + "chat" + ansiReset}
+	lines = append(lines, lastLines(m.chat, chatHeight)...)
+	for len(lines) < chatHeight+1 {
+		lines = append(lines, "")
+	}
+	if m.chatting {
+		lines = append(lines, "> "+m.chatInput+"_")
+	} else {
+		lines = append(lines, ansiDim+"tab to chat"+ansiReset)
+	}
+	lines = append(lines, "", ansiBold+"game log"+ansiReset)
+	return append(lines, lastLines(m.log, logHeight)...)
+}
+
+func lastLines(lines []string, count int) []string {
+	if count <= 0 {
+		return nil
+	}
+	if len(lines) > count {
+		return lines[len(lines)-count:]
+	}
+	return lines
+}